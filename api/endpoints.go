@@ -36,12 +36,48 @@ func post(i *restAPIHandler, path string, w http.ResponseWriter, r *http.Request
 	case "/ob/unfollow", "/ob/unfollow/":
 		i.POSTUnfollow(w, r)
 		return
+	case "/ob/post", "/ob/post/":
+		i.POSTPost(w, r)
+		return
+	case "/ob/channel", "/ob/channel/":
+		i.POSTChannel(w, r)
+		return
+	case "/ob/moderator", "/ob/moderator/":
+		i.POSTModerator(w, r)
+		return
+	case "/ob/rating", "/ob/rating/":
+		i.POSTRating(w, r)
+		return
+	case "/ob/refund", "/ob/refund/":
+		i.POSTRefund(w, r)
+		return
+	case "/ob/orderconfirmation", "/ob/orderconfirmation/":
+		i.POSTOrderConfirmation(w, r)
+		return
+	case "/ob/orderfulfillment", "/ob/orderfulfillment/":
+		i.POSTOrderFulfillment(w, r)
+		return
+	case "/ob/inventory", "/ob/inventory/":
+		i.POSTInventory(w, r)
+		return
 	case "/ob/profile", "/ob/profile/":
 		i.PUTProfile(w, r) // POST and PUT are the same here
 		return
 	case "/wallet/spend", "/wallet/spend/":
 		i.POSTSpendCoins(w, r) // POST and PUT are the same here
 		return
+	case "/wallet/rescan", "/wallet/rescan/":
+		i.POSTRescan(w, r)
+		return
+	case "/wallet/unsigned", "/wallet/unsigned/":
+		i.POSTUnsignedTransaction(w, r)
+		return
+	case "/wallet/sign", "/wallet/sign/":
+		i.POSTSignTransaction(w, r)
+		return
+	case "/wallet/broadcast", "/wallet/broadcast/":
+		i.POSTBroadcastTransaction(w, r)
+		return
 	}
 }
 
@@ -54,6 +90,46 @@ func get(i *restAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETPeers(w, r)
 		return
 	}
+	if strings.Contains(path, "/ob/followers") {
+		i.GETFollowers(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/following") {
+		i.GETFollowing(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/feed") {
+		i.GETFeed(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/search/tag/") {
+		i.GETTagSearch(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/search") {
+		i.GETSearch(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/channel/") {
+		i.GETChannel(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/moderators") {
+		i.GETModerators(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/ratings") {
+		i.GETRatings(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/purchase/") {
+		i.GETPurchase(w, r)
+		return
+	}
+	if strings.Contains(path, "/ob/inventory/") {
+		i.GETInventory(w, r)
+		return
+	}
 	if strings.Contains(path, "/wallet/address") {
 		i.GETAddress(w, r)
 		return
@@ -66,4 +142,8 @@ func get(i *restAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETBalance(w, r)
 		return
 	}
+	if strings.Contains(path, "/wallet/xpub") {
+		i.GETXpub(w, r)
+		return
+	}
 }
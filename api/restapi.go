@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,15 +13,22 @@ import (
 	"os"
 	"path"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net/service"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/ipfs/go-ipfs/core/corehttp"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcd/chaincfg"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
 )
 
 type RestAPIConfig struct {
@@ -459,7 +468,7 @@ func (i *restAPIHandler) POSTFollow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := i.node.Follow(pid.ID); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(errorStatus(err))
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
 	}
@@ -480,6 +489,535 @@ func (i *restAPIHandler) POSTUnfollow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := i.node.Unfollow(pid.ID); err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+func (i *restAPIHandler) GETFollowers(w http.ResponseWriter, r *http.Request) {
+	local := func(offset int, limit int) ([]string, int, error) {
+		followers, err := i.node.Datastore.Followers().Get(offset, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		var ids []string
+		for _, f := range followers {
+			ids = append(ids, f.PeerId)
+		}
+		return ids, i.node.Datastore.Followers().Count(), nil
+	}
+	// Only followers with a valid proof of following the peer are returned
+	remote := func(peerId string, b []byte) ([]string, error) {
+		var followers []repo.Follower
+		if err := json.Unmarshal(b, &followers); err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, f := range followers {
+			id, err := peer.IDB58Decode(f.PeerId)
+			if err != nil {
+				continue
+			}
+			if _, err := service.VerifyFollowCommand(f.Proof, pb.Message_FOLLOW, id, peerId); err != nil {
+				log.Debugf("Dropping follower %s of %s: %s", f.PeerId, peerId, err)
+				continue
+			}
+			ids = append(ids, f.PeerId)
+		}
+		return ids, nil
+	}
+	i.getPeerList(w, r, "followers", local, remote)
+}
+
+func (i *restAPIHandler) GETFollowing(w http.ResponseWriter, r *http.Request) {
+	local := func(offset int, limit int) ([]string, int, error) {
+		following, err := i.node.Datastore.Following().Get(offset, limit)
+		return following, i.node.Datastore.Following().Count(), err
+	}
+	remote := func(peerId string, b []byte) ([]string, error) {
+		var following []string
+		err := json.Unmarshal(b, &following)
+		return following, err
+	}
+	i.getPeerList(w, r, "following", local, remote)
+}
+
+// Serve a page of our own list, or when the path ends with a peer ID, of the list
+// that peer published in its root directory
+func (i *restAPIHandler) getPeerList(w http.ResponseWriter, r *http.Request, name string,
+	local func(offset int, limit int) ([]string, int, error),
+	remote func(peerId string, b []byte) ([]string, error)) {
+	w.Header().Add("Content-Type", "application/json")
+	offset, limit, err := pagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	var peers []string
+	var count int
+	_, peerId := path.Split(strings.TrimSuffix(r.URL.Path, "/"))
+	if peerId == name || peerId == i.node.IpfsNode.Identity.Pretty() {
+		peers, count, err = local(offset, limit)
+		if err != nil {
+			w.WriteHeader(errorStatus(err))
+			fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+			return
+		}
+	} else {
+		b, err := ipfs.Cat(i.node.Context, path.Join("/ipns", peerId, name))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+			return
+		}
+		all, err := remote(peerId, b)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{"success": false, "reason": "Invalid %s list: %s"}`, name, err)
+			return
+		}
+		count = len(all)
+		if offset < len(all) {
+			all = all[offset:]
+			if limit >= 0 && limit < len(all) {
+				all = all[:limit]
+			}
+			peers = all
+		}
+	}
+	if peers == nil {
+		peers = []string{}
+	}
+	ret, err := json.MarshalIndent(map[string]interface{}{
+		"count": count,
+		name:    peers,
+	}, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+func (i *restAPIHandler) POSTPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	post := new(pb.Post)
+	if err := jsonpb.Unmarshal(r.Body, post); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	hash, err := i.node.Post(post)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true, "hash": "%s"}`, hash)
+}
+
+// Serve our posts and those of the peers we follow, newest first
+func (i *restAPIHandler) GETFeed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	offset, limit, err := pagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	items, err := i.node.Datastore.Feed().Get(offset, limit)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	type feedItem struct {
+		Hash   string          `json:"hash"`
+		PeerId string          `json:"peerId"`
+		Post   json.RawMessage `json:"post"`
+	}
+	m := jsonpb.Marshaler{}
+	feed := []feedItem{}
+	for _, item := range items {
+		sp := new(pb.SignedPost)
+		if err := proto.Unmarshal(item.Post, sp); err != nil {
+			log.Error(err)
+			continue
+		}
+		post, err := m.MarshalToString(sp.Post)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		feed = append(feed, feedItem{item.Hash, item.PeerId, json.RawMessage(post)})
+	}
+	ret, err := json.MarshalIndent(feed, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Search the network for listings published under a tag
+func (i *restAPIHandler) GETTagSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	_, tag := path.Split(r.URL.Path)
+	if tag == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success": false, "reason": "A tag is required"}`)
+		return
+	}
+	type tagResult struct {
+		Hash     string          `json:"hash"`
+		Contract json.RawMessage `json:"contract"`
+	}
+	m := jsonpb.Marshaler{}
+	results := []tagResult{}
+	for _, result := range i.node.SearchTag(tag) {
+		contract, err := m.MarshalToString(result.Contract)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		results = append(results, tagResult{result.Hash, json.RawMessage(contract)})
+	}
+	ret, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Add a post to a channel
+func (i *restAPIHandler) POSTChannel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type ChannelPost struct {
+		Channel string `json:"channel"`
+		Hash    string `json:"hash"`
+	}
+	var cp ChannelPost
+	if err := json.NewDecoder(r.Body).Decode(&cp); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if err := i.node.AddToChannel(cp.Channel, cp.Hash); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Serve the posts added to a channel by any peer
+func (i *restAPIHandler) GETChannel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	_, channel := path.Split(r.URL.Path)
+	results, err := i.node.GetChannel(channel)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	type channelItem struct {
+		Hash   string          `json:"hash"`
+		PeerId string          `json:"peerId"`
+		Post   json.RawMessage `json:"post"`
+	}
+	m := jsonpb.Marshaler{}
+	posts := []channelItem{}
+	for _, result := range results {
+		post, err := m.MarshalToString(result.Post.Post)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		posts = append(posts, channelItem{result.Hash, result.PeerId, json.RawMessage(post)})
+	}
+	ret, err := json.MarshalIndent(posts, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Search the listings in the local search index
+func (i *restAPIHandler) GETSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	offset, limit, err := pagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	query, err := searchQuery(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	listings, err := i.node.Datastore.SearchIndex().Search(query, offset, limit)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	type searchResult struct {
+		Hash         string   `json:"hash"`
+		PeerId       string   `json:"peerId"`
+		ListingName  string   `json:"listingName"`
+		Title        string   `json:"title"`
+		Description  string   `json:"description"`
+		Tags         []string `json:"tags"`
+		Category     string   `json:"category"`
+		Price        uint64   `json:"price"`
+		CurrencyCode string   `json:"currencyCode,omitempty"`
+		FiatPrice    float64  `json:"fiatPrice,omitempty"`
+		ShipsTo      []string `json:"shipsTo"`
+	}
+	results := []searchResult{}
+	for _, l := range listings {
+		res := searchResult{
+			Hash:         l.Hash,
+			PeerId:       l.PeerId,
+			ListingName:  l.ListingName,
+			Title:        l.Title,
+			Description:  l.Description,
+			Tags:         l.Tags,
+			Category:     pb.Listing_Metadata_Category(l.Category).String(),
+			Price:        l.Price,
+			CurrencyCode: l.CurrencyCode,
+			FiatPrice:    l.FiatPrice,
+			ShipsTo:      []string{},
+		}
+		for _, region := range l.ShipsTo {
+			res.ShipsTo = append(res.ShipsTo, pb.CountryCode(region).String())
+		}
+		results = append(results, res)
+	}
+	ret, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Become a moderator, or update our moderator profile
+func (i *restAPIHandler) POSTModerator(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	mod := new(pb.Moderator)
+	if err := jsonpb.Unmarshal(r.Body, mod); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if err := i.node.SetModerator(mod); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Serve the moderators discovered on the network
+func (i *restAPIHandler) GETModerators(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type moderator struct {
+		PeerId    string          `json:"peerId"`
+		Moderator json.RawMessage `json:"moderator"`
+	}
+	m := jsonpb.Marshaler{}
+	mods := []moderator{}
+	for _, result := range i.node.GetModerators() {
+		mod, err := m.MarshalToString(result.Moderator)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		mods = append(mods, moderator{result.PeerId, json.RawMessage(mod)})
+	}
+	ret, err := json.MarshalIndent(mods, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Rate one of our orders. The body holds the order's contract and the rating.
+func (i *restAPIHandler) POSTRating(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type RatingParam struct {
+		Contract json.RawMessage `json:"contract"`
+		Rating   json.RawMessage `json:"rating"`
+	}
+	var rp RatingParam
+	if err := json.NewDecoder(r.Body).Decode(&rp); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	contract := new(pb.RicardianContract)
+	if err := jsonpb.UnmarshalString(string(rp.Contract), contract); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	rating := new(pb.Rating)
+	if err := jsonpb.UnmarshalString(string(rp.Rating), rating); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if err := i.node.RateOrder(contract, rating); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Serve the verified ratings of a peer's store, or of ours if no peer is given, with
+// the average of each score
+func (i *restAPIHandler) GETRatings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	_, peerId := path.Split(r.URL.Path)
+	if peerId == "" || peerId == "ratings" {
+		peerId = i.node.IpfsNode.Identity.Pretty()
+	}
+	contracts, err := i.node.GetRatings(peerId)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	type rating struct {
+		BuyerId       string `json:"buyerId"`
+		Quality       uint32 `json:"quality"`
+		Delivery      uint32 `json:"delivery"`
+		Communication uint32 `json:"communication"`
+		Review        string `json:"review"`
+		Timestamp     uint64 `json:"timestamp"`
+	}
+	type average struct {
+		Quality       float64 `json:"quality"`
+		Delivery      float64 `json:"delivery"`
+		Communication float64 `json:"communication"`
+	}
+	type ratings struct {
+		Count   int      `json:"count"`
+		Average average  `json:"average"`
+		Ratings []rating `json:"ratings"`
+	}
+	ret := ratings{Ratings: []rating{}}
+	for _, c := range contracts {
+		rt := c.BuyerRating
+		ret.Ratings = append(ret.Ratings, rating{c.BuyerOrder.BuyerID.Guid, rt.Quality, rt.Delivery, rt.Communication, rt.Review, rt.Timestamp})
+		ret.Average.Quality += float64(rt.Quality)
+		ret.Average.Delivery += float64(rt.Delivery)
+		ret.Average.Communication += float64(rt.Communication)
+	}
+	ret.Count = len(ret.Ratings)
+	if ret.Count > 0 {
+		ret.Average.Quality /= float64(ret.Count)
+		ret.Average.Delivery /= float64(ret.Count)
+		ret.Average.Communication /= float64(ret.Count)
+	}
+	b, err := json.MarshalIndent(ret, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(b))
+}
+
+// Refund all or part of an order placed with us. A moderated order needs the coins
+// paid into escrow as "txid:index" outpoints with their values.
+func (i *restAPIHandler) POSTRefund(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type EscrowCoin struct {
+		Outpoint string `json:"outpoint"`
+		Value    int    `json:"value"`
+	}
+	type RefundParam struct {
+		Contract    json.RawMessage `json:"contract"`
+		Amount      uint64          `json:"amount"`
+		Memo        string          `json:"memo"`
+		EscrowCoins []EscrowCoin    `json:"escrowCoins"`
+		FeePerByte  uint64          `json:"feePerByte"`
+	}
+	var rp RefundParam
+	if err := json.NewDecoder(r.Body).Decode(&rp); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	contract := new(pb.RicardianContract)
+	if err := jsonpb.UnmarshalString(string(rp.Contract), contract); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	var coins []bitcoin.Utxo
+	for _, c := range rp.EscrowCoins {
+		op, err := parseOutpoint(c.Outpoint)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+			return
+		}
+		txid, _ := hex.DecodeString(op.Hash.String())
+		coins = append(coins, bitcoin.Utxo{Txid: txid, Index: int(op.Index), Value: c.Value})
+	}
+	if err := i.node.RefundOrder(contract, rp.Amount, rp.Memo, coins, rp.FeePerByte); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Fulfill an order placed with us. Physical goods need a carrier and tracking number,
+// digital goods the file's url and services a note.
+func (i *restAPIHandler) POSTOrderFulfillment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type FulfillmentParam struct {
+		Contract    json.RawMessage `json:"contract"`
+		Fulfillment json.RawMessage `json:"fulfillment"`
+	}
+	var fp FulfillmentParam
+	if err := json.NewDecoder(r.Body).Decode(&fp); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	contract := new(pb.RicardianContract)
+	if err := jsonpb.UnmarshalString(string(fp.Contract), contract); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fulfillment := new(pb.OrderFulfillment)
+	if err := jsonpb.UnmarshalString(string(fp.Fulfillment), fulfillment); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if err := i.node.FulfillOrder(contract, fulfillment); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
@@ -487,6 +1025,167 @@ func (i *restAPIHandler) POSTUnfollow(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `{"success": true}`)
 }
 
+// Confirm an order placed with us, taking the items out of stock. Fails with 409
+// Conflict if there isn't enough stock.
+func (i *restAPIHandler) POSTOrderConfirmation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	contract := new(pb.RicardianContract)
+	if err := jsonpb.Unmarshal(r.Body, contract); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if err := i.node.ConfirmOrder(contract); err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Set the stock of a variant of one of our listings. The variant is given by its
+// options, or none for a listing without options. A negative count stops tracking the
+// variant. The stock level in the listing is updated when it's next published.
+func (i *restAPIHandler) POSTInventory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type InventoryParam struct {
+		ListingName string            `json:"listingName"`
+		Options     map[string]string `json:"options"`
+		Count       int               `json:"count"`
+	}
+	var ip InventoryParam
+	if err := json.NewDecoder(r.Body).Decode(&ip); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	if ip.ListingName == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success": false, "reason": "Missing listing name"}`)
+		return
+	}
+	var err error
+	variant := core.InventoryVariant(ip.Options)
+	if ip.Count < 0 {
+		err = i.node.Datastore.Inventory().Delete(ip.ListingName, variant)
+	} else {
+		err = i.node.Datastore.Inventory().Put(ip.ListingName, variant, ip.Count)
+	}
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true}`)
+}
+
+// Serve the stock of each tracked variant of one of our listings
+func (i *restAPIHandler) GETInventory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	_, listingName := path.Split(r.URL.Path)
+	stock, err := i.node.Datastore.Inventory().Get(listingName)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	b, err := json.MarshalIndent(stock, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(b))
+}
+
+// Serve the state and contract of an order we placed by its hex encoded order hash
+func (i *restAPIHandler) GETPurchase(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	_, orderHash := path.Split(r.URL.Path)
+	ser, state, err := i.node.Datastore.Purchases().Get(orderHash)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	contract := new(pb.RicardianContract)
+	if err := proto.Unmarshal(ser, contract); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: false,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(contract)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"state": "%s", "contract": %s}`, state, out)
+}
+
+// Parse the offset and limit query parameters. Without a limit everything after the
+// offset is returned.
+func pagination(r *http.Request) (offset int, limit int, err error) {
+	limit = -1
+	if s := r.URL.Query().Get("offset"); s != "" {
+		offset, err = strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("Invalid offset %s", s)
+		}
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			return 0, 0, fmt.Errorf("Invalid limit %s", s)
+		}
+	}
+	return offset, limit, nil
+}
+
+// Read the search filters from the URL query. Countries and categories are given by
+// name, for example shipsTo=UNITED_STATES&category=PHYSICAL_GOOD.
+func searchQuery(r *http.Request) (repo.SearchQuery, error) {
+	params := r.URL.Query()
+	query := repo.SearchQuery{
+		Text:         params.Get("q"),
+		CurrencyCode: params.Get("currency"),
+	}
+	if s := params.Get("shipsTo"); s != "" {
+		code, ok := pb.CountryCode_value[strings.ToUpper(s)]
+		if !ok {
+			return query, fmt.Errorf("Unknown country %s", s)
+		}
+		query.ShipsTo = int(code)
+	}
+	if s := params.Get("category"); s != "" {
+		category, ok := pb.Listing_Metadata_Category_value[strings.ToUpper(s)]
+		if !ok {
+			return query, fmt.Errorf("Unknown category %s", s)
+		}
+		query.Category = int(category)
+	}
+	var err error
+	if s := params.Get("minPrice"); s != "" {
+		query.MinPrice, err = strconv.ParseFloat(s, 64)
+		if err != nil || query.MinPrice < 0 {
+			return query, fmt.Errorf("Invalid minimum price %s", s)
+		}
+	}
+	if s := params.Get("maxPrice"); s != "" {
+		query.MaxPrice, err = strconv.ParseFloat(s, 64)
+		if err != nil || query.MaxPrice < 0 {
+			return query, fmt.Errorf("Invalid maximum price %s", s)
+		}
+	}
+	return query, nil
+}
+
 func (i *restAPIHandler) GETAddress(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 	addr := i.node.Wallet.GetCurrentAddress(bitcoin.RECEIVING)
@@ -497,7 +1196,7 @@ func (i *restAPIHandler) GETMnemonic(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 	mn, err := i.node.Datastore.Config().GetMnemonic()
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(errorStatus(err))
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
 	}
@@ -512,38 +1211,277 @@ func (i *restAPIHandler) GETBalance(w http.ResponseWriter, r *http.Request) {
 
 func (i *restAPIHandler) POSTSpendCoins(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
+	opts, err := decodeSpendOptions(r, i.node.Wallet.Params())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	result, err := i.node.Wallet.SpendWithOptions(opts)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	type Response struct {
+		Success bool   `json:"success"`
+		Txid    string `json:"txid"`
+		Fee     int64  `json:"fee"`
+		Change  int64  `json:"change"`
+		Tx      string `json:"tx,omitempty"`
+	}
+	resp := Response{
+		Success: true,
+		Txid:    result.Txid,
+		Fee:     result.Fee,
+		Change:  result.Change,
+	}
+	if opts.DryRun {
+		resp.Tx = hex.EncodeToString(result.Tx)
+	}
+	respJson, err := json.MarshalIndent(resp, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(respJson))
+}
+
+// Decode the spend options shared by the spend and unsigned transaction endpoints
+func decodeSpendOptions(r *http.Request, params *chaincfg.Params) (bitcoin.SpendOptions, error) {
+	type Output struct {
+		Address string
+		Amount  int64
+	}
 	type Send struct {
-		Address  string
-		Amount   int64
-		FeeLevel string
+		Address     string
+		Amount      int64
+		Outputs     []Output
+		Utxos       []string
+		FeeLevel    string
+		FeePerByte  uint64
+		SubtractFee bool
+		SendAll     bool
+		DryRun      bool
 	}
+	var opts bitcoin.SpendOptions
 	decoder := json.NewDecoder(r.Body)
 	var snd Send
-	err := decoder.Decode(&snd)
+	if err := decoder.Decode(&snd); err != nil {
+		return opts, err
+	}
+	if snd.Address != "" {
+		snd.Outputs = append([]Output{{snd.Address, snd.Amount}}, snd.Outputs...)
+	}
+	opts = bitcoin.SpendOptions{
+		FeePerByte:  snd.FeePerByte,
+		SubtractFee: snd.SubtractFee,
+		SendAll:     snd.SendAll,
+		DryRun:      snd.DryRun,
+	}
+	for _, o := range snd.Outputs {
+		addr, err := btc.DecodeAddress(o.Address, params)
+		if err != nil {
+			return opts, err
+		}
+		opts.Outputs = append(opts.Outputs, bitcoin.SpendOutput{Address: addr, Amount: o.Amount})
+	}
+	for _, u := range snd.Utxos {
+		op, err := parseOutpoint(u)
+		if err != nil {
+			return opts, err
+		}
+		opts.Inputs = append(opts.Inputs, *op)
+	}
+	switch strings.ToUpper(snd.FeeLevel) {
+	case "PRIORITY":
+		opts.FeeLevel = bitcoin.PRIOIRTY
+	case "ECONOMIC":
+		opts.FeeLevel = bitcoin.ECONOMIC
+	default:
+		opts.FeeLevel = bitcoin.NORMAL
+	}
+	return opts, nil
+}
+
+// The JSON form of an unsigned transaction exported for offline signing
+type unsignedInput struct {
+	Outpoint     string `json:"outpoint"`
+	Value        int64  `json:"value"`
+	ScriptPubKey string `json:"scriptPubKey"`
+	KeyPath      string `json:"keyPath"`
+}
+
+type unsignedTransaction struct {
+	Tx     string          `json:"tx"`
+	Inputs []unsignedInput `json:"inputs"`
+	Fee    int64           `json:"fee"`
+	Change int64           `json:"change"`
+}
+
+// Build a transaction from the same options as a spend but return it unsigned along with
+// the key path of each input so it can be signed offline.
+func (i *restAPIHandler) POSTUnsignedTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	opts, err := decodeSpendOptions(r, i.node.Wallet.Params())
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
 	}
-	addr, err := btc.DecodeAddress("2ND3E7kix3xogR77H8F35zjufXZkHUvWTvA", i.node.Wallet.Params())
+	utx, err := i.node.Wallet.CreateUnsignedTransaction(opts)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	resp := unsignedTransaction{
+		Tx:     hex.EncodeToString(utx.Tx),
+		Fee:    utx.Fee,
+		Change: utx.Change,
+	}
+	for _, in := range utx.Inputs {
+		resp.Inputs = append(resp.Inputs, unsignedInput{
+			Outpoint:     in.Outpoint.String(),
+			Value:        in.Value,
+			ScriptPubKey: hex.EncodeToString(in.ScriptPubKey),
+			KeyPath:      in.KeyPath,
+		})
+	}
+	respJson, err := json.MarshalIndent(resp, "", "    ")
 	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprint(w, string(respJson))
+}
+
+// Sign a transaction exported by /wallet/unsigned. This is meant to be called on an
+// offline node holding the private keys.
+func (i *restAPIHandler) POSTSignTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	var req unsignedTransaction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
 	}
-	var feeLevel bitcoin.FeeLevel
-	switch strings.ToUpper(snd.FeeLevel) {
-	case "PRIORITY":
-		feeLevel = bitcoin.PRIOIRTY
-	case "NORMAL":
-		feeLevel = bitcoin.NORMAL
-	case "Economic":
-		feeLevel = bitcoin.ECONOMIC
+	utx := &bitcoin.UnsignedTransaction{Fee: req.Fee, Change: req.Change}
+	var err error
+	utx.Tx, err = hex.DecodeString(req.Tx)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	for _, in := range req.Inputs {
+		op, err := parseOutpoint(in.Outpoint)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+			return
+		}
+		script, err := hex.DecodeString(in.ScriptPubKey)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+			return
+		}
+		utx.Inputs = append(utx.Inputs, bitcoin.UnsignedInput{
+			Outpoint:     *op,
+			Value:        in.Value,
+			ScriptPubKey: script,
+			KeyPath:      in.KeyPath,
+		})
 	}
-	if err := i.node.Wallet.Spend(snd.Amount, addr, feeLevel); err != nil {
+	signed, err := i.node.Wallet.SignTransaction(utx)
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
 		return
 	}
+	fmt.Fprintf(w, `{"success": true, "tx": "%s"}`, hex.EncodeToString(signed))
+}
+
+// Broadcast a transaction signed offline
+func (i *restAPIHandler) POSTBroadcastTransaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	type Broadcast struct {
+		Tx string
+	}
+	var req Broadcast
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	raw, err := hex.DecodeString(req.Tx)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	txid, err := i.node.Wallet.BroadcastTransaction(raw)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"success": false, "reason": "%s"}`, err)
+		return
+	}
+	fmt.Fprintf(w, `{"success": true, "txid": "%s"}`, txid)
+}
+
+// The account extended public key which a watch-only node can be set up with
+func (i *restAPIHandler) GETXpub(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"xpub": "%s", "watchOnly": %t}`, i.node.Wallet.GetAccountPublicKey().String(), i.node.Wallet.WatchOnly())
+}
+
+// Rescanning can take several minutes so we return immediately and notify
+// the UI over the websocket when it's done.
+func (i *restAPIHandler) POSTRescan(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	go func() {
+		if err := i.node.Wallet.Rescan(); err != nil {
+			log.Error(err)
+			i.node.Broadcast <- []byte(`{"notification": {"rescan": {"success": false, "reason": "` + err.Error() + `"}}}`)
+			return
+		}
+		i.node.Broadcast <- []byte(`{"notification": {"rescan": {"success": true}}}`)
+	}()
 	fmt.Fprintf(w, `{"success": true}`)
-}
\ No newline at end of file
+}
+
+// Parse an outpoint in the form txid:index
+func parseOutpoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid utxo %s, expected txid:index", s)
+	}
+	sha, err := wire.NewShaHashFromStr(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	return wire.NewOutPoint(sha, uint32(index)), nil
+}
+
+// The HTTP status for an error returned by the datastore
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repo.ErrWrongPassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, repo.ErrLocked):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, repo.ErrInsufficientStock):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
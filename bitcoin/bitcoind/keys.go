@@ -0,0 +1,106 @@
+package bitcoind
+
+import (
+	"encoding/binary"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/txscript"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+// BIP32 hierarchy
+// m / account' / purpose / address_index
+// This is the same hierarchy as the libbitcoin wallet so either wallet can be
+// restored from the other's mnemonic. In watch-only mode the account key is an
+// extended public key from the config.
+
+func (w *BitcoindWallet) GetMasterPrivateKey() *b32.Key {
+	return w.masterPrivateKey
+}
+
+func (w *BitcoindWallet) GetMasterPublicKey() *b32.Key {
+	return w.masterPublicKey
+}
+
+func (w *BitcoindWallet) GetAccountPublicKey() *b32.Key {
+	return w.accountKey.PublicKey()
+}
+
+func (w *BitcoindWallet) WatchOnly() bool {
+	return !w.accountKey.IsPrivate
+}
+
+func (w *BitcoindWallet) GetCurrentKey(purpose bitcoin.KeyPurpose) *b32.Key {
+	key, used, _ := w.db.Keys().GetLastKey(purpose)
+	if key == nil { // No keys in this chain have been generated yet. Let's generate key 0.
+		return w.putChildKey(purpose, 0)
+	} else if used { // The last key in the chain has been used so let's generate a new key.
+		index := binary.BigEndian.Uint32(key.ChildNumber)
+		return w.putChildKey(purpose, index + 1)
+	} else { // The last key in the chain is unused so let's just return it.
+		return key
+	}
+}
+
+func (w *BitcoindWallet) GetFreshKey(purpose bitcoin.KeyPurpose) *b32.Key {
+	key, _, _ := w.db.Keys().GetLastKey(purpose)
+	if key == nil {
+		return w.putChildKey(purpose, 0)
+	}
+	index := binary.BigEndian.Uint32(key.ChildNumber)
+	return w.putChildKey(purpose, index + 1)
+}
+
+func (w *BitcoindWallet) GetCurrentAddress(purpose bitcoin.KeyPurpose) btc.Address {
+	return w.addressForKey(w.GetCurrentKey(purpose))
+}
+
+func (w *BitcoindWallet) GetFreshAddress(purpose bitcoin.KeyPurpose) btc.Address {
+	return w.addressForKey(w.GetFreshKey(purpose))
+}
+
+// Generate the child key at index, save it in the db and import its address into the
+// node's wallet without a rescan. A new key can't have any history yet.
+func (w *BitcoindWallet) putChildKey(purpose bitcoin.KeyPurpose, index uint32) *b32.Key {
+	childKey := w.generateChildKey(purpose, index)
+	script, _ := txscript.PayToAddrScript(w.addressForKey(childKey))
+	w.db.Keys().Put(childKey, script, purpose)
+	if err := w.importKey(childKey, false); err != nil {
+		log.Errorf("Failed to import key into bitcoind: %s\n", err)
+	}
+	return childKey
+}
+
+func (w *BitcoindWallet) generateChildKey(purpose bitcoin.KeyPurpose, index uint32) *b32.Key {
+	purposeMK, _ := bitcoin.NewChildKey(w.accountKey, uint32(purpose))
+	childKey, _ := bitcoin.NewChildKey(purposeMK, index)
+	return childKey
+}
+
+func (w *BitcoindWallet) addressForKey(key *b32.Key) btc.Address {
+	addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+	return addr.AddressPubKeyHash()
+}
+
+// Import the key's address into the node's wallet as watch-only. The private key never
+// leaves our database. Importing an address the node already watches is harmless. A
+// rescan of the whole chain is slow so it should only be requested for the last key
+// of a batch.
+func (w *BitcoindWallet) importKey(key *b32.Key, rescan bool) error {
+	return w.call("importaddress", nil, w.addressForKey(key).EncodeAddress(), account, rescan)
+}
+
+// Import the address of every key in the db into the node's wallet
+func (w *BitcoindWallet) importKeys() error {
+	keys, err := w.db.Keys().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := w.importKey(key, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
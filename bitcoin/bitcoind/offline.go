@@ -0,0 +1,142 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+var errWatchOnly = errors.New("Spending is disabled in watch-only mode, export an unsigned transaction and sign it offline")
+
+// Build the transaction as in a dry run and attach the value, script and key path of each
+// input so it can be signed by a wallet which holds the private keys.
+func (w *BitcoindWallet) CreateUnsignedTransaction(opts bitcoin.SpendOptions) (*bitcoin.UnsignedTransaction, error) {
+	opts.DryRun = true
+	result, err := w.SpendWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(result.Tx)); err != nil {
+		return nil, err
+	}
+	var outpoints []wire.OutPoint
+	for _, in := range tx.TxIn {
+		outpoints = append(outpoints, in.PreviousOutPoint)
+	}
+	coins, err := w.gatherCoins(outpoints)
+	if err != nil {
+		return nil, err
+	}
+	utx := &bitcoin.UnsignedTransaction{
+		Tx:     result.Tx,
+		Fee:    result.Fee,
+		Change: result.Change,
+	}
+	for _, op := range outpoints {
+		for _, c := range coins {
+			txid, _ := hex.DecodeString(op.Hash.String())
+			if !bytes.Equal(c.utxo.Txid, txid) || uint32(c.utxo.Index) != op.Index {
+				continue
+			}
+			path, err := w.keyPath(c.key)
+			if err != nil {
+				return nil, err
+			}
+			utx.Inputs = append(utx.Inputs, bitcoin.UnsignedInput{
+				Outpoint:     op,
+				Value:        int64(c.utxo.Value),
+				ScriptPubKey: c.utxo.ScriptPubKey,
+				KeyPath:      path,
+			})
+		}
+	}
+	return utx, nil
+}
+
+// Sign an unsigned transaction using the key paths of its inputs. Each key must pay to
+// the script of the input it signs so we never sign for coins we don't control.
+func (w *BitcoindWallet) SignTransaction(utx *bitcoin.UnsignedTransaction) ([]byte, error) {
+	if w.WatchOnly() {
+		return nil, errWatchOnly
+	}
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(utx.Tx)); err != nil {
+		return nil, err
+	}
+	var coins []coin
+	for _, in := range utx.Inputs {
+		purpose, index, err := bitcoin.ParseKeyPath(in.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key := w.generateChildKey(purpose, index)
+		script, _ := txscript.PayToAddrScript(w.addressForKey(key))
+		if !bytes.Equal(script, in.ScriptPubKey) {
+			return nil, errors.New("Key path " + in.KeyPath + " does not match the input script")
+		}
+		txid, _ := hex.DecodeString(in.Outpoint.Hash.String())
+		coins = append(coins, coin{
+			utxo: bitcoin.Utxo{Txid: txid, Index: int(in.Outpoint.Index), Value: int(in.Value), ScriptPubKey: in.ScriptPubKey},
+			key:  key,
+		})
+	}
+	if err := w.signTransaction(tx, coins); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	tx.Serialize(&b)
+	return b.Bytes(), nil
+}
+
+// Broadcast a transaction signed offline and add it to the wallet. Signatures for our own
+// coins are verified first so a bad signer is caught before the transaction is relayed.
+func (w *BitcoindWallet) BroadcastTransaction(raw []byte) (string, error) {
+	tx, err := btc.NewTxFromBytes(stripWitness(raw))
+	if err != nil {
+		return "", err
+	}
+	for i, in := range tx.MsgTx().TxIn {
+		txid, _ := hex.DecodeString(in.PreviousOutPoint.Hash.String())
+		for _, u := range w.db.Coins().GetAll() {
+			if !bytes.Equal(u.Txid, txid) || uint32(u.Index) != in.PreviousOutPoint.Index {
+				continue
+			}
+			engine, err := txscript.NewEngine(u.ScriptPubKey, tx.MsgTx(), i, txscript.StandardVerifyFlags, nil)
+			if err != nil {
+				return "", err
+			}
+			if err := engine.Execute(); err != nil {
+				return "", errors.New("Invalid signature: " + err.Error())
+			}
+		}
+	}
+	var txid string
+	if err := w.call("sendrawtransaction", &txid, hex.EncodeToString(raw)); err != nil {
+		return "", err
+	}
+	log.Infof("Broadcast tx %s to bitcoin network\n", txid)
+	w.syncLock.Lock()
+	w.processTransaction(tx, raw, 0, time.Now())
+	w.syncLock.Unlock()
+	return tx.Sha().String(), nil
+}
+
+// Find the BIP32 path of one of our keys
+func (w *BitcoindWallet) keyPath(key *b32.Key) (string, error) {
+	index := binary.BigEndian.Uint32(key.ChildNumber)
+	for _, purpose := range []bitcoin.KeyPurpose{bitcoin.RECEIVING, bitcoin.CHANGE, bitcoin.REFUND} {
+		if bytes.Equal(w.generateChildKey(purpose, index).PublicKey().Key, key.PublicKey().Key) {
+			return bitcoin.KeyPath(purpose, index), nil
+		}
+	}
+	return "", errors.New("Key is not derived from the account key")
+}
@@ -0,0 +1,100 @@
+package bitcoind
+
+import (
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// The number of transactions fetched from the node after a rescan. This is large
+// enough to cover the full history of any wallet we expect to restore.
+const rescanTxCount = 1000000
+
+type keyIndex struct {
+	purpose bitcoin.KeyPurpose
+	index   int
+}
+
+// Rebuild the keys, transactions and coins tables from the master key. Addresses are
+// imported into the node for gapLimit keys past the last used key of each chain and the
+// node rescans the blockchain for them. This repeats until every chain ends in gapLimit
+// unused keys. Each rescan walks the whole chain so this can take a long time.
+func (w *BitcoindWallet) Rescan() error {
+	log.Info("Rescanning wallet")
+	purposes := []bitcoin.KeyPurpose{bitcoin.RECEIVING, bitcoin.CHANGE, bitcoin.REFUND}
+	keys := make(map[string]keyIndex)
+	derived := make(map[bitcoin.KeyPurpose]int)
+	lastUsed := map[bitcoin.KeyPurpose]int{bitcoin.RECEIVING: -1, bitcoin.CHANGE: -1, bitcoin.REFUND: -1}
+	for {
+		// Derive and import keys up to the gap limit on each chain, rescanning with the last one
+		var toImport []keyIndex
+		for _, purpose := range purposes {
+			for i := derived[purpose]; i <= lastUsed[purpose] + w.gapLimit; i++ {
+				toImport = append(toImport, keyIndex{purpose, i})
+			}
+		}
+		if len(toImport) == 0 {
+			break
+		}
+		for i, k := range toImport {
+			key := w.generateChildKey(k.purpose, uint32(k.index))
+			if err := w.importKey(key, i == len(toImport) - 1); err != nil {
+				return err
+			}
+			keys[w.addressForKey(key).EncodeAddress()] = k
+			derived[k.purpose] = k.index + 1
+		}
+
+		used, err := w.usedAddresses()
+		if err != nil {
+			return err
+		}
+		for _, addr := range used {
+			if k, ok := keys[addr]; ok && k.index > lastUsed[k.purpose] {
+				lastUsed[k.purpose] = k.index
+			}
+		}
+	}
+
+	// Save the keys, marking those up to the last used key on each chain as used
+	for _, purpose := range purposes {
+		for i := 0; i < derived[purpose]; i++ {
+			key := w.generateChildKey(purpose, uint32(i))
+			script, _ := txscript.PayToAddrScript(w.addressForKey(key))
+			w.db.Keys().Put(key, script, purpose) // Fails harmlessly if we already have the key
+			if i <= lastUsed[purpose] {
+				w.db.Keys().MarkKeyAsUsed(key)
+			}
+		}
+	}
+
+	w.syncLock.Lock()
+	for _, u := range w.db.Coins().GetAll() {
+		w.db.Coins().Delete(u.Txid, u.Index)
+	}
+	for _, tx := range w.db.Transactions().GetAll() {
+		w.db.Transactions().Delete(tx.Txid)
+	}
+	w.syncLock.Unlock()
+
+	// If this fails the next sync will pick up the most recent transactions
+	if err := w.syncTransactions(rescanTxCount); err != nil {
+		return err
+	}
+	log.Infof("Rescan complete, found %d transactions\n", len(w.db.Transactions().GetAll()))
+	return nil
+}
+
+// Return the addresses in the node's wallet that have received coins
+func (w *BitcoindWallet) usedAddresses() ([]string, error) {
+	var received []struct {
+		Address string `json:"address"`
+	}
+	if err := w.call("listreceivedbyaddress", &received, 0, false, true); err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, r := range received {
+		addrs = append(addrs, r.Address)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,330 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+// Worst case sizes used for fee estimation. These match the libbitcoin wallet.
+const (
+	redeemP2PKHInputSize = 32 + 4 + 1 + 1 + 73 + 1 + 33 + 4
+	p2pkhOutputSize      = 8 + 1 + 25
+)
+
+var errInsufficientFunds = errors.New("insuffient funds")
+
+type coin struct {
+	utxo bitcoin.Utxo
+	key  *b32.Key
+}
+
+func (w *BitcoindWallet) Spend(amount int64, addr btc.Address, feeLevel bitcoin.FeeLevel) error {
+	_, err := w.SpendWithOptions(bitcoin.SpendOptions{
+		Outputs:  []bitcoin.SpendOutput{{Address: addr, Amount: amount}},
+		FeeLevel: feeLevel,
+	})
+	return err
+}
+
+func (w *BitcoindWallet) SpendWithOptions(opts bitcoin.SpendOptions) (*bitcoin.SpendResult, error) {
+	if w.WatchOnly() && !opts.DryRun {
+		return nil, errWatchOnly
+	}
+	if len(opts.Outputs) == 0 {
+		return nil, errors.New("No outputs specified")
+	}
+	if opts.SendAll && len(opts.Outputs) != 1 {
+		return nil, errors.New("Send all requires exactly one output")
+	}
+	var outputs []*wire.TxOut
+	for _, o := range opts.Outputs {
+		script, err := txscript.PayToAddrScript(o.Address)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.SendAll && txrules.IsDustAmount(btc.Amount(o.Amount), len(script), txrules.DefaultRelayFeePerKb) {
+			return nil, errors.New("Amount is below dust threshold")
+		}
+		outputs = append(outputs, wire.NewTxOut(o.Amount, script))
+	}
+
+	coins, err := w.gatherCoins(opts.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	feePerByte := opts.FeePerByte
+	if feePerByte == 0 {
+		feePerByte = w.getFeePerByte(opts.FeeLevel)
+	}
+	feePerKB := btc.Amount(int64(feePerByte) * 1000)
+	subtractFee := opts.SubtractFee || opts.SendAll
+
+	// Add coins largest first until they cover the outputs and, unless the fee is
+	// subtracted from the outputs, the fee for a transaction with change.
+	selected := coins
+	if !opts.SendAll && len(opts.Inputs) == 0 {
+		sort.Sort(byValue(coins))
+		var target btc.Amount
+		for _, out := range outputs {
+			target += btc.Amount(out.Value)
+		}
+		selected = nil
+		var total btc.Amount
+		for _, c := range coins {
+			selected = append(selected, c)
+			total += btc.Amount(c.utxo.Value)
+			fee := btc.Amount(0)
+			if !subtractFee {
+				fee = txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(len(selected), outputs, true))
+			}
+			if total >= target + fee {
+				break
+			}
+		}
+	}
+
+	tx, changeIndex, fee, err := w.buildTransaction(outputs, selected, feePerKB, subtractFee, opts.SendAll)
+	if err != nil {
+		return nil, err
+	}
+	result := &bitcoin.SpendResult{Fee: int64(fee)}
+	if changeIndex >= 0 {
+		result.Change = tx.TxOut[changeIndex].Value
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+
+	if !opts.DryRun {
+		if err := w.signTransaction(tx, selected); err != nil {
+			return nil, err
+		}
+	}
+	var serializedTx bytes.Buffer
+	tx.Serialize(&serializedTx)
+	result.Tx = serializedTx.Bytes()
+	result.Txid = tx.TxSha().String()
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if _, err := w.Client.SendRawTransaction(tx, false); err != nil {
+		return nil, err
+	}
+	log.Infof("Broadcast tx %s to bitcoin network\n", result.Txid)
+	w.syncLock.Lock()
+	w.processTransaction(btc.NewTx(tx), result.Tx, 0, time.Now())
+	w.syncLock.Unlock()
+	return result, nil
+}
+
+// Return the wallet's coins with their keys. If outpoints are given only those coins are
+// returned and an error is raised if any of them are not in the wallet.
+func (w *BitcoindWallet) gatherCoins(outpoints []wire.OutPoint) ([]coin, error) {
+	var coins []coin
+	found := make(map[wire.OutPoint]bool)
+	for _, u := range w.db.Coins().GetAll() {
+		sha, err := wire.NewShaHashFromStr(hex.EncodeToString(u.Txid))
+		if err != nil {
+			continue
+		}
+		op := *wire.NewOutPoint(sha, uint32(u.Index))
+		if len(outpoints) > 0 && !containsOutpoint(outpoints, op) {
+			continue
+		}
+		key, err := w.db.Keys().GetKeyForScript(u.ScriptPubKey)
+		if err != nil {
+			continue
+		}
+		coins = append(coins, coin{u, key})
+		found[op] = true
+	}
+	for _, op := range outpoints {
+		if !found[op] {
+			return nil, fmt.Errorf("Coin %s not found in wallet", op.String())
+		}
+	}
+	return coins, nil
+}
+
+func containsOutpoint(outpoints []wire.OutPoint, op wire.OutPoint) bool {
+	for _, o := range outpoints {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Build an unsigned transaction spending all of the selected coins. A change output is added
+// if the leftover value is not dust, otherwise it goes to the miner. If subtractFee is set the
+// fee is deducted from the first output. If sendAll is set the first output receives the total
+// value of the coins less the fee. Returns the index of the change output, or -1, and the fee.
+func (w *BitcoindWallet) buildTransaction(outputs []*wire.TxOut, selected []coin, feePerKB btc.Amount,
+	subtractFee bool, sendAll bool) (*wire.MsgTx, int, btc.Amount, error) {
+
+	var target btc.Amount
+	for _, out := range outputs {
+		target += btc.Amount(out.Value)
+	}
+	tx := wire.NewMsgTx()
+	var total btc.Amount
+	for _, c := range selected {
+		total += btc.Amount(c.utxo.Value)
+		sha, _ := wire.NewShaHashFromStr(hex.EncodeToString(c.utxo.Txid))
+		in := wire.NewTxIn(wire.NewOutPoint(sha, uint32(c.utxo.Index)), []byte{})
+		in.Sequence = 0 // Opt-in RBF so we can bump fees
+		tx.AddTxIn(in)
+	}
+	if sendAll {
+		outputs[0].Value = int64(total)
+		target = total
+	}
+	if len(selected) == 0 || total < target {
+		return nil, 0, 0, errInsufficientFunds
+	}
+	for _, out := range outputs {
+		tx.AddTxOut(out)
+	}
+
+	feeWithChange := txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(len(selected), outputs, true))
+	feeWithoutChange := txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(len(selected), outputs, false))
+	remaining := total - target
+	changeAmount := remaining
+	if !subtractFee {
+		changeAmount -= feeWithChange
+	}
+	changeIndex := -1
+	var outputFee btc.Amount // The part of the fee deducted from the first output
+	if changeAmount > 0 && !txrules.IsDustAmount(changeAmount, p2pkhOutputSize - 9, feePerKB) {
+		changeScript, err := txscript.PayToAddrScript(w.GetCurrentAddress(bitcoin.CHANGE))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(changeAmount), changeScript))
+		changeIndex = len(tx.TxOut) - 1
+		if subtractFee {
+			outputFee = feeWithChange
+		}
+	} else if subtractFee {
+		// Any dust left over goes to the miner and counts towards the fee.
+		if feeWithoutChange > remaining {
+			outputFee = feeWithoutChange - remaining
+		}
+	} else if remaining < feeWithoutChange {
+		return nil, 0, 0, errInsufficientFunds
+	}
+	if subtractFee {
+		outputs[0].Value -= int64(outputFee)
+		if outputs[0].Value <= 0 || txrules.IsDustOutput(outputs[0], txrules.DefaultRelayFeePerKb) {
+			return nil, 0, 0, errors.New("Amount is too small to pay the fee")
+		}
+	}
+	fee := total
+	for _, out := range tx.TxOut {
+		fee -= btc.Amount(out.Value)
+	}
+	return tx, changeIndex, fee, nil
+}
+
+// Estimate the worst case size of a transaction spending numInputs P2PKH inputs
+func estimateSerializeSize(numInputs int, outputs []*wire.TxOut, addChangeOutput bool) int {
+	outputCount := len(outputs)
+	changeSize := 0
+	if addChangeOutput {
+		outputCount++
+		changeSize = p2pkhOutputSize
+	}
+	size := 8 + wire.VarIntSerializeSize(uint64(numInputs)) + wire.VarIntSerializeSize(uint64(outputCount)) +
+		numInputs * redeemP2PKHInputSize + changeSize
+	for _, out := range outputs {
+		size += out.SerializeSize()
+	}
+	return size
+}
+
+// Sign each input of the transaction with the key belonging to the coin it spends
+func (w *BitcoindWallet) signTransaction(tx *wire.MsgTx, coins []coin) error {
+	prevCoins := make(map[wire.OutPoint]coin)
+	keysByAddress := make(map[string]*btcec.PrivateKey)
+	for _, c := range coins {
+		sha, _ := wire.NewShaHashFromStr(hex.EncodeToString(c.utxo.Txid))
+		prevCoins[*wire.NewOutPoint(sha, uint32(c.utxo.Index))] = c
+		privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), c.key.Key)
+		keysByAddress[w.addressForKey(c.key).EncodeAddress()] = privKey
+	}
+	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
+		privKey, ok := keysByAddress[addr.EncodeAddress()]
+		if !ok {
+			return nil, false, errors.New("Key not found")
+		}
+		return privKey, true, nil
+	})
+	getScript := txscript.ScriptClosure(func(addr btc.Address) ([]byte, error) {
+		return []byte{}, nil
+	})
+	for i, txIn := range tx.TxIn {
+		c, ok := prevCoins[txIn.PreviousOutPoint]
+		if !ok {
+			return errors.New("Failed to sign transaction")
+		}
+		script, err := txscript.SignTxOutput(w.params, tx, i, c.utxo.ScriptPubKey, txscript.SigHashAll,
+			getKey, getScript, txIn.SignatureScript)
+		if err != nil {
+			return errors.New("Failed to sign transaction")
+		}
+		txIn.SignatureScript = script
+	}
+	return nil
+}
+
+// Use the node's fee estimate for the number of blocks we are willing to wait, capped at
+// maxFee. If the node doesn't have enough data to make an estimate the default fees from
+// the config are used.
+func (w *BitcoindWallet) getFeePerByte(feeLevel bitcoin.FeeLevel) uint64 {
+	var blocks int
+	var defaultFee uint64
+	switch feeLevel {
+	case bitcoin.PRIOIRTY:
+		blocks, defaultFee = 2, w.priorityFee
+	case bitcoin.ECONOMIC:
+		blocks, defaultFee = 12, w.economicFee
+	default:
+		blocks, defaultFee = 6, w.normalFee
+	}
+	var estimate struct {
+		FeeRate float64 `json:"feerate"`
+	}
+	if err := w.call("estimatesmartfee", &estimate, blocks); err != nil || estimate.FeeRate <= 0 {
+		return defaultFee
+	}
+	// The fee rate is in BTC per kilobyte
+	amount, err := btc.NewAmount(estimate.FeeRate)
+	if err != nil {
+		return defaultFee
+	}
+	feePerByte := uint64(amount) / 1000
+	if feePerByte > w.maxFee {
+		return w.maxFee
+	}
+	return feePerByte
+}
+
+type byValue []coin
+
+func (a byValue) Len() int           { return len(a) }
+func (a byValue) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byValue) Less(i, j int) bool { return a[i].utxo.Value > a[j].utxo.Value }
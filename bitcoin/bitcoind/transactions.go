@@ -0,0 +1,211 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+)
+
+// The number of most recent wallet transactions fetched from the node on each sync.
+// Older transactions are only fetched by a rescan.
+const syncTxCount = 1000
+
+// Fetch recent transactions involving our addresses from the node. New transactions are
+// processed into the transactions and coins tables and the height and state of known
+// transactions are updated, which also takes care of reorgs.
+func (w *BitcoindWallet) sync() error {
+	return w.syncTransactions(syncTxCount)
+}
+
+func (w *BitcoindWallet) syncTransactions(count int) error {
+	w.syncLock.Lock()
+	defer w.syncLock.Unlock()
+
+	tip, err := w.Client.GetBlockCount()
+	if err != nil {
+		return err
+	}
+	var list []btcjson.ListTransactionsResult
+	if err := w.call("listtransactions", &list, "*", count, 0, true); err != nil {
+		return err
+	}
+	// The node returns an entry for each of our addresses involved in a transaction,
+	// oldest first, so parents are processed before their children.
+	seen := make(map[string]bool)
+	for _, entry := range list {
+		if seen[entry.TxID] {
+			continue
+		}
+		seen[entry.TxID] = true
+		txid, err := hex.DecodeString(entry.TxID)
+		if err != nil {
+			return err
+		}
+		height := 0
+		if entry.Confirmations > 0 {
+			height = int(tip - entry.Confirmations + 1)
+		}
+		if w.db.Transactions().Has(txid) {
+			w.updateTransaction(txid, height, entry.Confirmations)
+			continue
+		}
+		if entry.Confirmations < 0 { // Conflicted with a transaction in the best chain
+			continue
+		}
+		var result btcjson.GetTransactionResult
+		if err := w.call("gettransaction", &result, entry.TxID, true); err != nil {
+			return err
+		}
+		raw, err := hex.DecodeString(result.Hex)
+		if err != nil {
+			return err
+		}
+		tx, err := btc.NewTxFromBytes(stripWitness(raw))
+		if err != nil {
+			return err
+		}
+		w.processTransaction(tx, raw, height, time.Unix(result.Time, 0))
+	}
+	return nil
+}
+
+// The node reports negative confirmations for transactions that conflict with the best chain
+func (w *BitcoindWallet) updateTransaction(txid []byte, height int, confirmations int64) {
+	switch {
+	case confirmations < 0:
+		w.db.Transactions().UpdateState(txid, bitcoin.DEAD)
+	case height > 0:
+		w.db.Transactions().UpdateState(txid, bitcoin.CONFIRMED)
+	default:
+		w.db.Transactions().UpdateState(txid, bitcoin.PENDING)
+	}
+	w.db.Transactions().UpdateHeight(txid, height)
+}
+
+// Add a new transaction to the database. Outputs paying one of our keys are added to the
+// coins table and inputs spending our coins are removed from it. raw is stored in place of
+// the legacy serialization so any witnesses are kept.
+func (w *BitcoindWallet) processTransaction(tx *btc.Tx, raw []byte, height int, timestamp time.Time) {
+	txid, err := hex.DecodeString(tx.Sha().String())
+	if err != nil {
+		return
+	}
+	value := 0
+	for i, output := range(tx.MsgTx().TxOut) {
+		key, err := w.db.Keys().GetKeyForScript(output.PkScript)
+		if err == nil {
+			w.db.Coins().Put(bitcoin.Utxo{
+				Txid: txid,
+				Index: i,
+				Value: int(output.Value),
+				ScriptPubKey: output.PkScript,
+			})
+			w.db.Keys().MarkKeyAsUsed(key)
+			value += int(output.Value)
+		}
+	}
+	for _, input := range(tx.MsgTx().TxIn) {
+		outpointTxid, err := hex.DecodeString(input.PreviousOutPoint.Hash.String())
+		if err != nil {
+			return
+		}
+		if w.db.Coins().Has(outpointTxid, int(input.PreviousOutPoint.Index)) {
+			v, err := w.db.Coins().GetValue(outpointTxid, int(input.PreviousOutPoint.Index))
+			if err != nil {
+				return
+			}
+			value -= v
+			w.db.Coins().Delete(outpointTxid, int(input.PreviousOutPoint.Index))
+		}
+	}
+	var state bitcoin.TransactionState
+	if height > 0 {
+		state = bitcoin.CONFIRMED
+	} else {
+		state = bitcoin.PENDING
+	}
+	w.db.Transactions().Put(bitcoin.TransactionInfo{
+		Txid: txid,
+		Tx: raw,
+		Height: height,
+		State: state,
+		Timestamp: timestamp,
+		Value: value,
+		ExchangeRate: float64(0),
+		ExchangCurrency: "",
+	})
+}
+
+// The node returns transactions in the segwit serialization which the wire package
+// can't decode. The witnesses aren't needed to read the inputs and outputs so we
+// strip them and return the legacy serialization.
+func stripWitness(raw []byte) []byte {
+	if len(raw) < 6 || raw[4] != 0x00 || raw[5] != 0x01 {
+		return raw
+	}
+	r := bytes.NewReader(raw[6:])
+	var b bytes.Buffer
+	b.Write(raw[:4])
+	numIn, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return raw
+	}
+	wire.WriteVarInt(&b, 0, numIn)
+	for i := uint64(0); i < numIn; i++ {
+		outpoint := make([]byte, 36)
+		if _, err := io.ReadFull(r, outpoint); err != nil {
+			return raw
+		}
+		script, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "sigScript")
+		if err != nil {
+			return raw
+		}
+		sequence := make([]byte, 4)
+		if _, err := io.ReadFull(r, sequence); err != nil {
+			return raw
+		}
+		b.Write(outpoint)
+		wire.WriteVarBytes(&b, 0, script)
+		b.Write(sequence)
+	}
+	numOut, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return raw
+	}
+	wire.WriteVarInt(&b, 0, numOut)
+	for i := uint64(0); i < numOut; i++ {
+		value := make([]byte, 8)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return raw
+		}
+		script, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "pkScript")
+		if err != nil {
+			return raw
+		}
+		b.Write(value)
+		wire.WriteVarBytes(&b, 0, script)
+	}
+	for i := uint64(0); i < numIn; i++ {
+		numItems, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return raw
+		}
+		for j := uint64(0); j < numItems; j++ {
+			if _, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "witness"); err != nil {
+				return raw
+			}
+		}
+	}
+	lockTime := make([]byte, 4)
+	if _, err := io.ReadFull(r, lockTime); err != nil {
+		return raw
+	}
+	b.Write(lockTime)
+	return b.Bytes()
+}
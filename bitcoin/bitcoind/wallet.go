@@ -0,0 +1,160 @@
+package bitcoind
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcrpcclient"
+	"github.com/op/go-logging"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+var log = logging.MustGetLogger("BitcoindWallet")
+
+// The label our keys are imported under in the bitcoind wallet
+const account = "openbazaar"
+
+// How often the wallet polls bitcoind for new transactions and confirmations
+const syncInterval = 30 * time.Second
+
+// The number of consecutive keys without history after which we stop scanning a
+// chain, if not set in the config. This is the same gap limit as BIP 44.
+const DefaultGapLimit = 20
+
+// BitcoindWallet is a BitcoinWallet backed by the JSON-RPC interface of a bitcoind
+// node. Keys are derived from our seed as in the libbitcoin wallet and their
+// addresses are imported into the node's wallet as watch-only so it tracks them for
+// us. The node is polled to keep the keys, transactions and coins tables up to date.
+// Transactions are built and signed locally and only broadcast through the node.
+// Only P2PKH addresses are used.
+type BitcoindWallet struct {
+	Client           *btcrpcclient.Client
+
+	params           *chaincfg.Params
+
+	masterPrivateKey *b32.Key
+	masterPublicKey  *b32.Key
+
+	// All wallet keys are derived from the account key. This is a public key in watch-only mode.
+	accountKey       *b32.Key
+
+	maxFee           uint64
+	priorityFee      uint64
+	normalFee        uint64
+	economicFee      uint64
+
+	gapLimit         int
+
+	db               repo.Datastore
+
+	syncLock         sync.Mutex
+}
+
+func NewBitcoindWallet(seed []byte, params *chaincfg.Params, db repo.Datastore, host string, user string, password string,
+	maxFee uint64, lowFee uint64, mediumFee uint64, highFee uint64, gapLimit int, watchOnlyKey *b32.Key) (*BitcoindWallet, error) {
+
+	w, err := newBitcoindWallet(seed, params, db, host, user, password, maxFee, lowFee, mediumFee, highFee, gapLimit, watchOnlyKey)
+	if err != nil {
+		return nil, err
+	}
+	go w.startUpdateLoop()
+	return w, nil
+}
+
+func newBitcoindWallet(seed []byte, params *chaincfg.Params, db repo.Datastore, host string, user string, password string,
+	maxFee uint64, lowFee uint64, mediumFee uint64, highFee uint64, gapLimit int, watchOnlyKey *b32.Key) (*BitcoindWallet, error) {
+
+	client, err := btcrpcclient.New(&btcrpcclient.ConnConfig{
+		Host:         host,
+		User:         user,
+		Pass:         password,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	mk, _ := b32.NewMasterKey(seed)
+	w := new(BitcoindWallet)
+	w.Client = client
+	w.masterPrivateKey = mk
+	w.masterPublicKey = mk.PublicKey()
+	if watchOnlyKey != nil {
+		w.accountKey = watchOnlyKey
+	} else {
+		w.accountKey, _ = mk.NewChildKey(b32.FirstHardenedChild)
+	}
+	w.params = params
+	w.db = db
+	w.maxFee = maxFee
+	w.priorityFee = highFee
+	w.normalFee = mediumFee
+	w.economicFee = lowFee
+	w.gapLimit = gapLimit
+	return w, nil
+}
+
+// Imports our keys in case the node's wallet was replaced since we last ran, then syncs
+// with the node every syncInterval.
+func (w *BitcoindWallet) startUpdateLoop() {
+	if err := w.importKeys(); err != nil {
+		log.Errorf("Failed to import keys into bitcoind: %s\n", err)
+	}
+	tick := time.NewTicker(syncInterval)
+	defer tick.Stop()
+	for {
+		if err := w.sync(); err != nil {
+			log.Errorf("Failed to sync with bitcoind: %s\n", err)
+		}
+		<-tick.C
+	}
+}
+
+func (w *BitcoindWallet) GetBalance() (unconfirmed uint64, confirmed uint64) {
+	coins := w.db.Coins().GetAll()
+	for _, c := range(coins) {
+		height, err := w.db.Transactions().GetHeight(c.Txid)
+		if err != nil {
+			continue
+		}
+		if height == 0 {
+			unconfirmed += uint64(c.Value)
+		} else {
+			confirmed += uint64(c.Value)
+		}
+	}
+	return unconfirmed, confirmed
+}
+
+func (w *BitcoindWallet) Params() *chaincfg.Params {
+	return w.params
+}
+
+// Close the connection to the node
+func (w *BitcoindWallet) Close() {
+	w.Client.Shutdown()
+}
+
+// Call an RPC method that the rpc client has no wrapper for, or whose wrapper doesn't
+// take the watch-only flag, unmarshalling the response into result if it's not nil.
+func (w *BitcoindWallet) call(method string, result interface{}, params ...interface{}) error {
+	rawParams := make([]json.RawMessage, len(params))
+	for i, p := range params {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		rawParams[i] = b
+	}
+	resp, err := w.Client.RawRequest(method, rawParams)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp, result)
+}
@@ -0,0 +1,328 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// A fake bitcoind which answers JSON-RPC requests with canned results
+type fakeNode struct {
+	sync.Mutex
+	blockCount   int64
+	transactions []btcjson.ListTransactionsResult
+	rawTxs       map[string]string
+	imported     []string
+	broadcast    []string
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.Lock()
+	defer n.Unlock()
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+		ID     interface{}       `json:"id"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	var result interface{}
+	switch req.Method {
+	case "importaddress":
+		var addr string
+		json.Unmarshal(req.Params[0], &addr)
+		n.imported = append(n.imported, addr)
+	case "getblockcount":
+		result = n.blockCount
+	case "listtransactions":
+		result = n.transactions
+	case "gettransaction":
+		var txid string
+		json.Unmarshal(req.Params[0], &txid)
+		result = btcjson.GetTransactionResult{TxID: txid, Hex: n.rawTxs[txid], Time: 1480000000}
+	case "estimatesmartfee":
+		result = map[string]interface{}{"feerate": 0.0002, "blocks": 6}
+	case "sendrawtransaction":
+		var raw string
+		json.Unmarshal(req.Params[0], &raw)
+		n.broadcast = append(n.broadcast, raw)
+		b, _ := hex.DecodeString(raw)
+		tx, _ := btc.NewTxFromBytes(b)
+		result = tx.Sha().String()
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": nil,
+			"error":  map[string]interface{}{"code": -32601, "message": "Method not found"},
+			"id":     req.ID,
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"result": result, "error": nil, "id": req.ID})
+}
+
+// Add a transaction paying value to script to the node's wallet
+func (n *fakeNode) addIncoming(script []byte, value int64, confirmations int64) *wire.MsgTx {
+	n.Lock()
+	defer n.Unlock()
+	tx := wire.NewMsgTx()
+	prev, _ := wire.NewShaHashFromStr("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(prev, uint32(len(n.transactions))), []byte{}))
+	tx.AddTxOut(wire.NewTxOut(value, script))
+	var b bytes.Buffer
+	tx.Serialize(&b)
+	txid := tx.TxSha().String()
+	n.rawTxs[txid] = hex.EncodeToString(b.Bytes())
+	n.transactions = append(n.transactions, btcjson.ListTransactionsResult{
+		TxID:          txid,
+		Category:      "receive",
+		Confirmations: confirmations,
+	})
+	return tx
+}
+
+func newTestWallet(t *testing.T) (*BitcoindWallet, *fakeNode, func()) {
+	dir, err := ioutil.TempDir("", "bitcoind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Mkdir(path.Join(dir, "datastore"), os.ModePerm)
+	datastore, err := db.Create(dir, "", &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := datastore.Config().Init(testMnemonic, []byte{}, "", "", repo.SeedVersion1); err != nil {
+		t.Fatal(err)
+	}
+	node := &fakeNode{blockCount: 100, rawTxs: make(map[string]string)}
+	server := httptest.NewServer(node)
+	seed, _ := repo.WalletSeed(testMnemonic, "", repo.SeedVersion1)
+	w, err := newBitcoindWallet(seed, &chaincfg.TestNet3Params, datastore, strings.TrimPrefix(server.URL, "http://"),
+		"user", "password", 1000, 20, 40, 60, DefaultGapLimit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, node, func() {
+		w.Close()
+		server.Close()
+		datastore.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNewAddressIsImported(t *testing.T) {
+	w, node, cleanup := newTestWallet(t)
+	defer cleanup()
+	addr := w.GetCurrentAddress(bitcoin.RECEIVING)
+	if len(node.imported) != 1 || node.imported[0] != addr.EncodeAddress() {
+		t.Errorf("Expected %s to be imported, got %v", addr, node.imported)
+	}
+	if w.GetCurrentAddress(bitcoin.RECEIVING).EncodeAddress() != addr.EncodeAddress() {
+		t.Error("Unused address should be returned again")
+	}
+}
+
+func TestSync(t *testing.T) {
+	w, node, cleanup := newTestWallet(t)
+	defer cleanup()
+	script, _ := txscript.PayToAddrScript(w.GetCurrentAddress(bitcoin.RECEIVING))
+	confirmed := node.addIncoming(script, 100000, 3)
+	node.addIncoming(script, 50000, 0)
+	if err := w.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.db.Coins().GetAll()) != 2 {
+		t.Errorf("Expected 2 coins, got %d", len(w.db.Coins().GetAll()))
+	}
+	txid, _ := hex.DecodeString(confirmed.TxSha().String())
+	height, err := w.db.Transactions().GetHeight(txid)
+	if err != nil || height != 98 {
+		t.Errorf("Expected height 98, got %d", height)
+	}
+	unconfirmed, confirmedBalance := w.GetBalance()
+	if confirmedBalance != 100000 || unconfirmed != 50000 {
+		t.Errorf("Unexpected balance: confirmed %d, unconfirmed %d", confirmedBalance, unconfirmed)
+	}
+	if w.GetCurrentAddress(bitcoin.RECEIVING).EncodeAddress() == node.imported[0] {
+		t.Error("Used address should not be returned")
+	}
+
+	// The confirmed transaction is reorged out and double spent
+	node.transactions[0].Confirmations = -1
+	if err := w.sync(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range w.db.Transactions().GetAll() {
+		if bytes.Equal(tx.Txid, txid) && tx.State != bitcoin.DEAD {
+			t.Errorf("Expected conflicted transaction to be dead, got state %d", tx.State)
+		}
+	}
+}
+
+func TestSpend(t *testing.T) {
+	w, node, cleanup := newTestWallet(t)
+	defer cleanup()
+	script, _ := txscript.PayToAddrScript(w.GetCurrentAddress(bitcoin.RECEIVING))
+	node.addIncoming(script, 1000000, 6)
+	if err := w.sync(); err != nil {
+		t.Fatal(err)
+	}
+	addr, _ := btc.DecodeAddress("mjSk1Ny9spzU2fouzYgLqGUD8U41iR35QN", &chaincfg.TestNet3Params)
+	opts := bitcoin.SpendOptions{
+		Outputs:    []bitcoin.SpendOutput{{Address: addr, Amount: 400000}},
+		FeePerByte: 10,
+		DryRun:     true,
+	}
+	result, err := w.SpendWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One input, the payment and change
+	expectedFee := int64(10 * (8 + 1 + 1 + redeemP2PKHInputSize + 2 * p2pkhOutputSize))
+	if result.Fee != expectedFee || result.Change != 1000000 - 400000 - expectedFee {
+		t.Errorf("Unexpected fee %d and change %d", result.Fee, result.Change)
+	}
+	if len(node.broadcast) != 0 {
+		t.Error("Dry run should not broadcast")
+	}
+
+	opts.DryRun = false
+	result, err = w.SpendWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.broadcast) != 1 || node.broadcast[0] != hex.EncodeToString(result.Tx) {
+		t.Fatal("Transaction was not broadcast")
+	}
+	tx, _ := btc.NewTxFromBytes(result.Tx)
+	engine, err := txscript.NewEngine(script, tx.MsgTx(), 0, txscript.StandardVerifyFlags, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Execute(); err != nil {
+		t.Errorf("Invalid signature: %s", err)
+	}
+	coins := w.db.Coins().GetAll()
+	if len(coins) != 1 || int64(coins[0].Value) != result.Change {
+		t.Error("Spent coin should be replaced by the change")
+	}
+}
+
+func TestSendAll(t *testing.T) {
+	w, node, cleanup := newTestWallet(t)
+	defer cleanup()
+	script, _ := txscript.PayToAddrScript(w.GetCurrentAddress(bitcoin.RECEIVING))
+	node.addIncoming(script, 300000, 1)
+	node.addIncoming(script, 200000, 0)
+	if err := w.sync(); err != nil {
+		t.Fatal(err)
+	}
+	addr, _ := btc.DecodeAddress("mjSk1Ny9spzU2fouzYgLqGUD8U41iR35QN", &chaincfg.TestNet3Params)
+	result, err := w.SpendWithOptions(bitcoin.SpendOptions{
+		Outputs: []bitcoin.SpendOutput{{Address: addr}},
+		SendAll: true,
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, _ := btc.NewTxFromBytes(result.Tx)
+	if len(tx.MsgTx().TxIn) != 2 || len(tx.MsgTx().TxOut) != 1 || result.Change != 0 {
+		t.Fatal("Expected both coins to be spent to a single output")
+	}
+	// The fee rate comes from the node's estimate of 0.0002 BTC/kB
+	expectedFee := int64(20 * (8 + 1 + 1 + 2 * redeemP2PKHInputSize + p2pkhOutputSize))
+	if result.Fee != expectedFee || tx.MsgTx().TxOut[0].Value != 500000 - expectedFee {
+		t.Errorf("Unexpected fee %d", result.Fee)
+	}
+}
+
+func TestOfflineSigning(t *testing.T) {
+	signer, _, cleanupSigner := newTestWallet(t)
+	defer cleanupSigner()
+	w, node, cleanup := newTestWallet(t)
+	defer cleanup()
+
+	// Turn the second wallet into a watch-only wallet built from the signer's account key
+	w.masterPrivateKey = nil
+	w.accountKey = signer.GetAccountPublicKey()
+	if !w.WatchOnly() || signer.WatchOnly() {
+		t.Fatal("Only the wallet built from the account public key should be watch-only")
+	}
+	addr := w.GetCurrentAddress(bitcoin.RECEIVING)
+	if addr.EncodeAddress() != signer.GetCurrentAddress(bitcoin.RECEIVING).EncodeAddress() {
+		t.Fatal("Watch-only wallet should derive the same addresses")
+	}
+	script, _ := txscript.PayToAddrScript(addr)
+	node.addIncoming(script, 1000000, 6)
+	if err := w.sync(); err != nil {
+		t.Fatal(err)
+	}
+	payee, _ := btc.DecodeAddress("mjSk1Ny9spzU2fouzYgLqGUD8U41iR35QN", &chaincfg.TestNet3Params)
+	opts := bitcoin.SpendOptions{
+		Outputs:    []bitcoin.SpendOutput{{Address: payee, Amount: 400000}},
+		FeePerByte: 10,
+	}
+	if _, err := w.SpendWithOptions(opts); err != errWatchOnly {
+		t.Errorf("Expected watch-only spend to fail, got %v", err)
+	}
+
+	utx, err := w.CreateUnsignedTransaction(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utx.Inputs) != 1 || utx.Inputs[0].KeyPath != "m/0'/0/0" || utx.Inputs[0].Value != 1000000 {
+		t.Fatalf("Unexpected inputs %+v", utx.Inputs)
+	}
+	if _, err := w.SignTransaction(utx); err != errWatchOnly {
+		t.Error("Watch-only wallet should not sign")
+	}
+
+	// Broadcasting the unsigned transaction must fail
+	if _, err := w.BroadcastTransaction(utx.Tx); err == nil {
+		t.Error("Unsigned transaction should not be broadcast")
+	}
+
+	signed, err := signer.SignTransaction(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txid, err := w.BroadcastTransaction(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.broadcast) != 1 || node.broadcast[0] != hex.EncodeToString(signed) {
+		t.Fatal("Transaction was not broadcast")
+	}
+	tx, _ := btc.NewTxFromBytes(signed)
+	if tx.Sha().String() != txid {
+		t.Error("Returned txid does not match the transaction")
+	}
+	coins := w.db.Coins().GetAll()
+	if len(coins) != 1 || int64(coins[0].Value) != utx.Change {
+		t.Error("Spent coin should be replaced by the change")
+	}
+
+	// A key path which doesn't pay to the input script is refused
+	utx.Inputs[0].KeyPath = "m/0'/0/1"
+	if _, err := signer.SignTransaction(utx); err == nil {
+		t.Error("Expected mismatched key path to be refused")
+	}
+}
@@ -0,0 +1,56 @@
+package bitcoin
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+// Derive the child key at index. Private keys are derived by go-bip32. Its public key
+// derivation (CKDpub) is broken so non-hardened children of a public key, as used by a
+// watch-only wallet, are derived here.
+func NewChildKey(key *b32.Key, index uint32) (*b32.Key, error) {
+	if key.IsPrivate {
+		return key.NewChildKey(index)
+	}
+	if index >= b32.FirstHardenedChild {
+		return nil, errors.New("Can't create hardened child for public key")
+	}
+	parent, err := btcec.ParsePubKey(key.Key, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	childNumber := make([]byte, 4)
+	binary.BigEndian.PutUint32(childNumber, index)
+
+	mac := hmac.New(sha512.New, key.ChainCode)
+	mac.Write(key.Key)
+	mac.Write(childNumber)
+	intermediary := mac.Sum(nil)
+
+	il := new(big.Int).SetBytes(intermediary[:32])
+	if il.Cmp(btcec.S256().N) >= 0 || il.Sign() == 0 {
+		return nil, errors.New("Invalid child key")
+	}
+	ilx, ily := btcec.S256().ScalarBaseMult(intermediary[:32])
+	x, y := btcec.S256().Add(ilx, ily, parent.X, parent.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("Invalid child key")
+	}
+	child := btcec.PublicKey{Curve: btcec.S256(), X: x, Y: y}
+	return &b32.Key{
+		Version:     b32.PublicWalletVersion,
+		Depth:       key.Depth + 1,
+		ChildNumber: childNumber,
+		FingerPrint: btc.Hash160(key.Key)[:4],
+		ChainCode:   intermediary[32:],
+		Key:         child.SerializeCompressed(),
+		IsPrivate:   false,
+	}, nil
+}
@@ -0,0 +1,194 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// When updating the wallet we re-fetch history starting this many blocks below our
+// best header. This picks up any transactions the server failed to push to us
+// through the subscription without scanning the whole chain every time.
+const rescanDepth = 6
+
+// The maximum number of blocks we will walk back looking for a fork point. If the
+// server's chain doesn't connect to ours within this window we roll back everything
+// and rescan from height zero.
+const maxReorgDepth = 100
+
+var errFetchTimeout = errors.New("timed out waiting for libbitcoin server")
+
+// Register a function to be called when a chain reorg is detected. The function is
+// passed the height of the fork point. All transactions above this height have been
+// rolled back to pending and will be re-fetched.
+func (w *LibbitcoinWallet) AddReorgListener(listener func(forkHeight int)) {
+	w.reorgListeners = append(w.reorgListeners, listener)
+}
+
+// The height from which updateWalletBalances should fetch history.
+func (w *LibbitcoinWallet) scanHeight() uint32 {
+	height, _, err := w.db.Headers().GetBest()
+	if err != nil || height < rescanDepth {
+		return 0
+	}
+	return uint32(height - rescanDepth)
+}
+
+// Fetch the current chain tip from the server and connect any new headers to our
+// best chain. If our best header is no longer in the server's chain we walk back
+// to find the fork point, roll back all transactions above it and re-fetch
+// history from there.
+func (w *LibbitcoinWallet) syncHeaders() {
+	w.chainLock.Lock()
+	defer w.chainLock.Unlock()
+
+	tip, err := w.fetchLastHeight()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	bestHeight, _, err := w.db.Headers().GetBest()
+	if err != nil { // No headers yet. Start tracking from the current tip.
+		header, err := w.fetchBlockHeader(tip)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		w.putHeader(int(tip), header)
+		return
+	}
+
+	// Walk back from our best header until we find one that is still in the server's chain.
+	forkHeight := bestHeight
+	if int(tip) < forkHeight {
+		forkHeight = int(tip)
+	}
+	found := false
+	for ; forkHeight >= 0 && forkHeight > bestHeight-maxReorgDepth; forkHeight-- {
+		stored, err := w.db.Headers().GetHash(forkHeight)
+		if err != nil {
+			continue
+		}
+		header, err := w.fetchBlockHeader(uint32(forkHeight))
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		if bytes.Equal(stored, headerHash(header)) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		forkHeight = 0
+	}
+	if forkHeight < bestHeight {
+		w.rollback(forkHeight)
+	}
+
+	// Connect the new headers. If we've been offline for a while only fetch the
+	// most recent headers as there's no point tracking blocks we can't reorg past.
+	from := forkHeight + 1
+	if int(tip)-from > maxReorgDepth {
+		from = int(tip) - maxReorgDepth
+	}
+	for height := from; height <= int(tip); height++ {
+		header, err := w.fetchBlockHeader(uint32(height))
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		prev, err := w.db.Headers().GetHash(height - 1)
+		if err == nil && !bytes.Equal(prev, hashBytes(header.PrevBlock)) {
+			// The chain changed while we were syncing. We'll catch it on the next pass.
+			log.Warningf("Header at height %d does not connect to our chain\n", height)
+			return
+		}
+		w.putHeader(height, header)
+	}
+
+	if forkHeight < bestHeight {
+		w.updateWalletBalances(uint32(forkHeight + 1))
+		for _, listener := range w.reorgListeners {
+			go listener(forkHeight)
+		}
+	}
+}
+
+// Roll back all transactions confirmed above the fork height to pending and delete
+// the orphaned headers. The transactions will be updated with their new heights
+// when we re-fetch history from the fork point.
+func (w *LibbitcoinWallet) rollback(forkHeight int) {
+	log.Warningf("Chain reorg detected, rolling back to height %d\n", forkHeight)
+	for _, tx := range w.db.Transactions().GetAll() {
+		if tx.Height > forkHeight {
+			w.db.Transactions().UpdateHeight(tx.Txid, 0)
+			w.db.Transactions().UpdateState(tx.Txid, bitcoin.PENDING)
+		}
+	}
+	w.db.Headers().DeleteAbove(forkHeight)
+}
+
+func (w *LibbitcoinWallet) putHeader(height int, header *wire.BlockHeader) {
+	err := w.db.Headers().Put(height, headerHash(header), hashBytes(header.PrevBlock))
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func (w *LibbitcoinWallet) fetchLastHeight() (uint32, error) {
+	type result struct {
+		height uint32
+		err    error
+	}
+	c := make(chan result, 1)
+	w.Client.FetchLastHeight(func(i interface{}, err error) {
+		if err != nil {
+			c <- result{0, err}
+			return
+		}
+		c <- result{i.(uint32), nil}
+	})
+	select {
+	case r := <-c:
+		return r.height, r.err
+	case <-time.After(time.Minute):
+		return 0, errFetchTimeout
+	}
+}
+
+func (w *LibbitcoinWallet) fetchBlockHeader(height uint32) (*wire.BlockHeader, error) {
+	type result struct {
+		header *wire.BlockHeader
+		err    error
+	}
+	c := make(chan result, 1)
+	w.Client.FetchBlockHeader(height, func(i interface{}, err error) {
+		if err != nil {
+			c <- result{nil, err}
+			return
+		}
+		c <- result{i.(*wire.BlockHeader), nil}
+	})
+	select {
+	case r := <-c:
+		return r.header, r.err
+	case <-time.After(time.Minute):
+		return nil, errFetchTimeout
+	}
+}
+
+// Block hashes are stored in the same byte order as txids, that is, the
+// hex decoded form of the hash string.
+func headerHash(header *wire.BlockHeader) []byte {
+	return hashBytes(header.BlockSha())
+}
+
+func hashBytes(sha wire.ShaHash) []byte {
+	b, _ := hex.DecodeString(sha.String())
+	return b
+}
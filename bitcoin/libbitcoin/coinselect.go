@@ -0,0 +1,256 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+type CoinSelectionStrategy string
+
+const (
+	// Search for a selection that needs no change output using branch and bound,
+	// falling back to largest first. Confirmed coins are preferred over unconfirmed
+	// ones and coins from a single address are preferred over merging addresses.
+	BranchAndBound CoinSelectionStrategy = "branchandbound"
+
+	// Use the fewest coins possible. This was the only strategy before coin
+	// confirmations were tracked.
+	MinNumberCoins CoinSelectionStrategy = "minnumber"
+
+	// Prefer coins with the highest value * confirmations.
+	MaxValueAge CoinSelectionStrategy = "maxvalueage"
+)
+
+// The maximum number of branches explored by the branch and bound search.
+const bnbMaxTries = 100000
+
+func ParseCoinSelectionStrategy(s string) (CoinSelectionStrategy, error) {
+	switch CoinSelectionStrategy(s) {
+	case "":
+		return BranchAndBound, nil
+	case BranchAndBound, MinNumberCoins, MaxValueAge:
+		return CoinSelectionStrategy(s), nil
+	}
+	return "", fmt.Errorf("Unknown coin selection strategy %s", s)
+}
+
+// Select coins to fund target. The target should include the fee for the transaction
+// without any inputs; the fee for each selected input is accounted for here at feePerKB.
+// The coinset strategies assume every input is the size of a P2PKH input.
+func selectCoins(coins []coinset.Coin, target btc.Amount, feePerKB btc.Amount, strategy CoinSelectionStrategy) ([]coinset.Coin, error) {
+	inputFee := feeForSize(feePerKB, redeemP2PKHInputSize)
+	changeFee := feeForSize(feePerKB, p2pkhOutputSize)
+	switch strategy {
+	case MinNumberCoins:
+		return selectWithCoinSelector(coinset.MinNumberCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(10000)},
+			coins, target, inputFee, changeFee)
+	case MaxValueAge:
+		return selectWithCoinSelector(coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(10000)},
+			coins, target, inputFee, changeFee)
+	}
+
+	// Cost of change is the fee to add a change output now plus the fee to spend it later.
+	costOfChange := changeFee + inputFee
+
+	var confirmed []coinset.Coin
+	for _, c := range coins {
+		if c.NumConfs() > 0 {
+			confirmed = append(confirmed, c)
+		}
+	}
+	tiers := [][]coinset.Coin{confirmed}
+	if len(confirmed) < len(coins) {
+		tiers = append(tiers, coins)
+	}
+	for _, candidates := range tiers {
+		if len(candidates) == 0 {
+			continue
+		}
+		groups := groupByAddress(candidates)
+
+		// Prefer a selection without change, from a single address if possible
+		var best []coinset.Coin
+		for _, group := range groups {
+			selected := branchAndBound(group, target, costOfChange, feePerKB)
+			if selected != nil && (best == nil || sumCoins(selected) < sumCoins(best)) {
+				best = selected
+			}
+		}
+		if best != nil {
+			return best, nil
+		}
+		if selected := branchAndBound(candidates, target, costOfChange, feePerKB); selected != nil {
+			return selected, nil
+		}
+
+		// Otherwise accept change, again preferring a single address
+		for _, group := range groups {
+			selected := largestFirst(group, target, feePerKB)
+			if selected != nil && (best == nil || len(selected) < len(best)) {
+				best = selected
+			}
+		}
+		if best != nil {
+			return best, nil
+		}
+		if selected := largestFirst(candidates, target, feePerKB); selected != nil {
+			return selected, nil
+		}
+	}
+	return nil, errInsufficientFunds
+}
+
+// Depth first search for the subset of coins whose effective value (value less the fee
+// to spend it) falls between target and target+costOfChange, that is, a selection where
+// adding a change output would cost more than dropping the excess to fees. Returns nil
+// if no such subset was found.
+func branchAndBound(coins []coinset.Coin, target btc.Amount, costOfChange btc.Amount, feePerKB btc.Amount) []coinset.Coin {
+	sorted := sortByEffectiveValue(coins, feePerKB)
+	values := make([]btc.Amount, len(sorted))
+	var available btc.Amount
+	for i, c := range sorted {
+		values[i] = effectiveValue(c, feePerKB)
+		available += values[i]
+	}
+	if available < target {
+		return nil
+	}
+
+	var best []int
+	var bestExcess btc.Amount
+	var selection []int
+	tries := 0
+	var search func(i int, selected btc.Amount, remaining btc.Amount)
+	search = func(i int, selected btc.Amount, remaining btc.Amount) {
+		tries++
+		if tries > bnbMaxTries || selected > target+costOfChange {
+			return
+		}
+		if selected >= target {
+			if best == nil || selected-target < bestExcess {
+				best = append([]int{}, selection...)
+				bestExcess = selected - target
+			}
+			return
+		}
+		if i == len(values) || selected+remaining < target {
+			return
+		}
+		// Try including the coin then excluding it
+		selection = append(selection, i)
+		search(i+1, selected+values[i], remaining-values[i])
+		selection = selection[:len(selection)-1]
+		if best != nil && bestExcess == 0 {
+			return
+		}
+		search(i+1, selected, remaining-values[i])
+	}
+	search(0, 0, available)
+
+	if best == nil {
+		return nil
+	}
+	ret := make([]coinset.Coin, len(best))
+	for i, idx := range best {
+		ret[i] = sorted[idx]
+	}
+	return ret
+}
+
+// Add coins in descending order of value until the target is reached. Returns nil
+// if the coins are insufficient.
+func largestFirst(coins []coinset.Coin, target btc.Amount, feePerKB btc.Amount) []coinset.Coin {
+	var selected []coinset.Coin
+	var total btc.Amount
+	for _, c := range sortByEffectiveValue(coins, feePerKB) {
+		selected = append(selected, c)
+		total += effectiveValue(c, feePerKB)
+		if total >= target {
+			return selected
+		}
+	}
+	return nil
+}
+
+// The coinset selectors know nothing about fees so we re-run them, raising the target
+// by the fee for each input, until the selection covers its own fees.
+func selectWithCoinSelector(selector coinset.CoinSelector, coins []coinset.Coin, target btc.Amount,
+	inputFee btc.Amount, changeFee btc.Amount) ([]coinset.Coin, error) {
+	numInputs := 1
+	for i := 0; i <= len(coins); i++ {
+		selected, err := selector.CoinSelect(target+changeFee+inputFee*btc.Amount(numInputs), coins)
+		if err != nil {
+			return nil, errInsufficientFunds
+		}
+		if len(selected.Coins()) <= numInputs {
+			return selected.Coins(), nil
+		}
+		numInputs = len(selected.Coins())
+	}
+	return nil, errInsufficientFunds
+}
+
+// Group coins by their scriptPubKey, keeping the groups in the order first seen.
+func groupByAddress(coins []coinset.Coin) [][]coinset.Coin {
+	var groups [][]coinset.Coin
+	for _, c := range coins {
+		found := false
+		for i, group := range groups {
+			if bytes.Equal(group[0].PkScript(), c.PkScript()) {
+				groups[i] = append(group, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []coinset.Coin{c})
+		}
+	}
+	return groups
+}
+
+// Sort coins by descending effective value, dropping any that cost more to spend than they are worth.
+func sortByEffectiveValue(coins []coinset.Coin, feePerKB btc.Amount) []coinset.Coin {
+	var sorted []coinset.Coin
+	for _, c := range coins {
+		if effectiveValue(c, feePerKB) > 0 {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.Stable(byEffectiveValue{sorted, feePerKB})
+	return sorted
+}
+
+// The value of a coin less the fee to spend it
+func effectiveValue(c coinset.Coin, feePerKB btc.Amount) btc.Amount {
+	return c.Value() - feeForSize(feePerKB, inputSize(c.PkScript()))
+}
+
+type byEffectiveValue struct {
+	coins    []coinset.Coin
+	feePerKB btc.Amount
+}
+
+func (a byEffectiveValue) Len() int      { return len(a.coins) }
+func (a byEffectiveValue) Swap(i, j int) { a.coins[i], a.coins[j] = a.coins[j], a.coins[i] }
+func (a byEffectiveValue) Less(i, j int) bool {
+	return effectiveValue(a.coins[i], a.feePerKB) > effectiveValue(a.coins[j], a.feePerKB)
+}
+
+func sumCoins(coins []coinset.Coin) btc.Amount {
+	var total btc.Amount
+	for _, c := range coins {
+		total += c.Value()
+	}
+	return total
+}
+
+// Fee for size bytes rounded up so the sum of the parts is never less than the
+// fee for the whole transaction.
+func feeForSize(feePerKB btc.Amount, size int) btc.Amount {
+	return (feePerKB*btc.Amount(size) + 999) / 1000
+}
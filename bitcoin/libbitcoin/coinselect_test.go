@@ -0,0 +1,226 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+type testUtxo struct {
+	value    int64
+	numConfs int64
+	address  byte
+}
+
+// Build coins whose index is their position in the slice so selections are easy to compare.
+func buildTestCoins(utxos []testUtxo) []coinset.Coin {
+	var coins []coinset.Coin
+	for i, u := range utxos {
+		script := bytes.Repeat([]byte{u.address}, 25)
+		coins = append(coins, NewCoin(make([]byte, 32), uint32(i), btc.Amount(u.value), u.numConfs, script))
+	}
+	return coins
+}
+
+func p2pkhScript(b byte) []byte {
+	script := []byte{0x76, 0xa9, 0x14}
+	script = append(script, bytes.Repeat([]byte{b}, 20)...)
+	return append(script, 0x88, 0xac)
+}
+
+func selectedIndexes(coins []coinset.Coin) []int {
+	var ret []int
+	for _, c := range coins {
+		ret = append(ret, int(c.Index()))
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+func TestSelectCoins(t *testing.T) {
+	tests := []struct {
+		name     string
+		utxos    []testUtxo
+		target   int64
+		feePerKB int64
+		strategy CoinSelectionStrategy
+		expected []int
+		err      error
+	}{
+		{
+			name:     "exact match avoids change",
+			utxos:    []testUtxo{{10, 1, 1}, {5, 1, 1}, {2, 1, 1}},
+			target:   7,
+			expected: []int{1, 2},
+		},
+		{
+			name:     "prefers confirmed coins",
+			utxos:    []testUtxo{{10, 0, 1}, {10, 3, 1}},
+			target:   10,
+			expected: []int{1},
+		},
+		{
+			name:     "falls back to unconfirmed coins",
+			utxos:    []testUtxo{{3, 2, 1}, {10, 0, 1}},
+			target:   12,
+			expected: []int{0, 1},
+		},
+		{
+			name:     "avoids merging addresses",
+			utxos:    []testUtxo{{7, 1, 1}, {5, 1, 2}, {6, 1, 3}, {6, 1, 3}},
+			target:   12,
+			expected: []int{2, 3},
+		},
+		{
+			name:     "prefers no change over a single address",
+			utxos:    []testUtxo{{4, 1, 1}, {4, 1, 1}, {4, 1, 1}, {11, 1, 2}, {2, 1, 3}},
+			target:   10,
+			expected: []int{0, 1, 4},
+		},
+		{
+			name:     "single address with change",
+			utxos:    []testUtxo{{7, 1, 1}, {6, 1, 2}, {6, 1, 2}, {6, 1, 2}},
+			target:   15,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:   "insufficient funds",
+			utxos:  []testUtxo{{5, 1, 1}},
+			target: 10,
+			err:    errInsufficientFunds,
+		},
+		{
+			name:     "accounts for input fees",
+			utxos:    []testUtxo{{10000, 1, 1}, {5149, 1, 1}},
+			target:   5000,
+			feePerKB: 1000,
+			expected: []int{1},
+		},
+		{
+			name:     "drops excess below cost of change",
+			utxos:    []testUtxo{{10000, 1, 1}, {5300, 1, 1}},
+			target:   5000,
+			feePerKB: 1000,
+			expected: []int{1},
+		},
+		{
+			name:     "ignores uneconomical coins",
+			utxos:    []testUtxo{{100, 1, 1}, {100, 1, 1}},
+			target:   50,
+			feePerKB: 1000,
+			err:      errInsufficientFunds,
+		},
+		{
+			name:     "min number of coins",
+			utxos:    []testUtxo{{1, 1, 1}, {2, 1, 1}, {50000, 1, 1}},
+			target:   20000,
+			strategy: MinNumberCoins,
+			expected: []int{2},
+		},
+		{
+			name:     "max value age",
+			utxos:    []testUtxo{{30000, 1, 1}, {25000, 10, 1}},
+			target:   15000,
+			strategy: MaxValueAge,
+			expected: []int{1},
+		},
+	}
+	for _, test := range tests {
+		strategy := test.strategy
+		if strategy == "" {
+			strategy = BranchAndBound
+		}
+		selected, err := selectCoins(buildTestCoins(test.utxos), btc.Amount(test.target), btc.Amount(test.feePerKB), strategy)
+		if err != test.err {
+			t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			continue
+		}
+		indexes := selectedIndexes(selected)
+		if len(indexes) != len(test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, indexes)
+			continue
+		}
+		for i := range indexes {
+			if indexes[i] != test.expected[i] {
+				t.Errorf("%s: expected %v got %v", test.name, test.expected, indexes)
+				break
+			}
+		}
+	}
+}
+
+func TestBuildTransaction(t *testing.T) {
+	changeSource := func() ([]byte, error) {
+		return p2pkhScript(0xff), nil
+	}
+	tests := []struct {
+		name        string
+		coin        int64
+		output      int64
+		subtractFee bool
+		sendAll     bool
+		fee         int64
+		change      int64
+		err         error
+	}{
+		{"adds change", 100000, 50000, false, false, 227, 49773, nil},
+		{"dust change goes to fee", 50500, 50000, false, false, 500, 0, nil},
+		{"insufficient for fee", 50100, 50000, false, false, 0, 0, errInsufficientFunds},
+		{"subtract fee", 100000, 100000, true, false, 193, 0, nil},
+		{"send all", 100000, 1, true, true, 193, 0, nil},
+	}
+	for _, test := range tests {
+		coins := buildTestCoins([]testUtxo{{test.coin, 1, 1}})
+		outputs := []*wire.TxOut{wire.NewTxOut(test.output, p2pkhScript(0x02))}
+		authoredTx, err := buildTransaction(outputs, coins, btc.Amount(1000), test.subtractFee, test.sendAll, txauthor.ChangeSource(changeSource))
+		if err != test.err {
+			t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		fee := int64(authoredTx.TotalInput)
+		for _, out := range authoredTx.Tx.TxOut {
+			fee -= out.Value
+		}
+		if fee != test.fee {
+			t.Errorf("%s: expected fee %d got %d", test.name, test.fee, fee)
+		}
+		var change int64
+		if authoredTx.ChangeIndex >= 0 {
+			change = authoredTx.Tx.TxOut[authoredTx.ChangeIndex].Value
+		}
+		if change != test.change {
+			t.Errorf("%s: expected change %d got %d", test.name, test.change, change)
+		}
+	}
+}
+
+func TestParseCoinSelectionStrategy(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected CoinSelectionStrategy
+		valid    bool
+	}{
+		{"", BranchAndBound, true},
+		{"branchandbound", BranchAndBound, true},
+		{"minnumber", MinNumberCoins, true},
+		{"maxvalueage", MaxValueAge, true},
+		{"random", "", false},
+	}
+	for _, test := range tests {
+		strategy, err := ParseCoinSelectionStrategy(test.in)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid=%t got error %v", test.in, test.valid, err)
+		}
+		if strategy != test.expected {
+			t.Errorf("%s: expected %s got %s", test.in, test.expected, strategy)
+		}
+	}
+}
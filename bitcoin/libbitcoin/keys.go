@@ -14,6 +14,8 @@ import (
 // purpose 0: receiving address
 // purpose 1: change address
 // purpose 2: refund address
+// In watch-only mode the account key is an extended public key from the config
+// and the master key is only used to sign listings and orders.
 
 func (w *LibbitcoinWallet) GetMasterPrivateKey() *b32.Key {
 	return w.masterPrivateKey
@@ -23,27 +25,23 @@ func (w *LibbitcoinWallet) GetMasterPublicKey() *b32.Key {
 	return w.masterPublicKey
 }
 
+func (w *LibbitcoinWallet) GetAccountPublicKey() *b32.Key {
+	return w.accountKey.PublicKey()
+}
+
+func (w *LibbitcoinWallet) WatchOnly() bool {
+	return !w.accountKey.IsPrivate
+}
+
 func (w *LibbitcoinWallet) GetCurrentKey(purpose bitcoin.KeyPurpose) *b32.Key {
 	key, used, _ := w.db.Keys().GetLastKey(purpose)
 	if key == nil { // No keys in this chain have been generated yet. Let's generate key 0.
-		childKey := w.generateChildKey(purpose, 0)
-		addr, _ := btc.NewAddressPubKey(childKey.PublicKey().Key, w.params)
-		script, _ := txscript.PayToAddrScript(addr.AddressPubKeyHash())
-		w.db.Keys().Put(childKey, script, purpose)
-		if purpose == bitcoin.RECEIVING || purpose == bitcoin.REFUND {
-			w.SubscribeAddress(addr.AddressPubKeyHash())
-		}
-		return childKey
-	} else if used { // The last key in the chain has been used. Let's generated a new key and save it in the db.
+		return w.putChildKey(purpose, 0)
+	} else if used || !w.matchesAddressType(key) {
+		// The last key in the chain has been used or was saved with a different address type
+		// before the config changed. Let's generated a new key and save it in the db.
 		index := binary.BigEndian.Uint32(key.ChildNumber)
-		childKey := w.generateChildKey(purpose, index + 1)
-		addr, _ := btc.NewAddressPubKey(childKey.PublicKey().Key, w.params)
-		script, _ := txscript.PayToAddrScript(addr.AddressPubKeyHash())
-		w.db.Keys().Put(childKey, script, purpose)
-		if purpose == bitcoin.RECEIVING || purpose == bitcoin.REFUND {
-			w.SubscribeAddress(addr.AddressPubKeyHash())
-		}
-		return childKey
+		return w.putChildKey(purpose, index + 1)
 	} else { // The last key in the chain is unused so let's just return it.
 		return key
 	}
@@ -52,31 +50,43 @@ func (w *LibbitcoinWallet) GetCurrentKey(purpose bitcoin.KeyPurpose) *b32.Key {
 func (w *LibbitcoinWallet) GetFreshKey(purpose bitcoin.KeyPurpose) *b32.Key {
 	key, _, _ := w.db.Keys().GetLastKey(purpose)
 	index := binary.BigEndian.Uint32(key.ChildNumber)
-	childKey := w.generateChildKey(purpose, index + 1)
-	addr, _ := btc.NewAddressPubKey(childKey.PublicKey().Key, w.params)
-	script, _ := txscript.PayToAddrScript(addr.AddressPubKeyHash())
-	w.db.Keys().Put(childKey, script, purpose)
-	if purpose == bitcoin.RECEIVING || purpose == bitcoin.REFUND {
-		w.SubscribeAddress(addr.AddressPubKeyHash())
-	}
-	return childKey
+	return w.putChildKey(purpose, index + 1)
 }
 
-func (w *LibbitcoinWallet) GetCurrentAddress(purpose bitcoin.KeyPurpose) *btc.AddressPubKeyHash {
+func (w *LibbitcoinWallet) GetCurrentAddress(purpose bitcoin.KeyPurpose) btc.Address {
 	key := w.GetCurrentKey(purpose)
-	addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
-	return addr.AddressPubKeyHash()
+	return w.addressForKey(key)
 }
 
-func (w *LibbitcoinWallet) GetFreshAddress(purpose bitcoin.KeyPurpose) *btc.AddressPubKeyHash {
+func (w *LibbitcoinWallet) GetFreshAddress(purpose bitcoin.KeyPurpose) btc.Address {
 	key := w.GetFreshKey(purpose)
-	addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
-	return addr.AddressPubKeyHash()
+	return w.addressForKey(key)
+}
+
+// Generate the child key at index, save it in the db with the script for the configured
+// address type and subscribe to it if we expect to receive coins on it.
+func (w *LibbitcoinWallet) putChildKey(purpose bitcoin.KeyPurpose, index uint32) *b32.Key {
+	childKey := w.generateChildKey(purpose, index)
+	addr := w.addressForKey(childKey)
+	script, _ := txscript.PayToAddrScript(addr)
+	w.db.Keys().Put(childKey, script, purpose)
+	if purpose == bitcoin.RECEIVING || purpose == bitcoin.REFUND {
+		w.SubscribeAddress(addr)
+	}
+	return childKey
+}
+
+// Keys keep the script they were saved with so coins paid to them are still recognised
+// after the address type is changed. This reports whether the saved script is of the
+// currently configured type.
+func (w *LibbitcoinWallet) matchesAddressType(key *b32.Key) bool {
+	script, _ := txscript.PayToAddrScript(w.addressForKey(key))
+	_, err := w.db.Keys().GetKeyForScript(script)
+	return err == nil
 }
 
 func (w *LibbitcoinWallet) generateChildKey(purpose bitcoin.KeyPurpose, index uint32) *b32.Key {
-	accountMK, _ := w.masterPrivateKey.NewChildKey(b32.FirstHardenedChild)
-	purposeMK, _ := accountMK.NewChildKey(uint32(purpose))
-	childKey, _ := purposeMK.NewChildKey(index)
+	purposeMK, _ := bitcoin.NewChildKey(w.accountKey, uint32(purpose))
+	childKey, _ := bitcoin.NewChildKey(purposeMK, index)
 	return childKey
 }
\ No newline at end of file
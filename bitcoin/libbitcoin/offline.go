@@ -0,0 +1,163 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	"github.com/tyler-smith/go-bip32"
+)
+
+var errWatchOnly = errors.New("Spending is disabled in watch-only mode, export an unsigned transaction and sign it offline")
+
+// Build the transaction as in a dry run and attach the value, script and key path of each
+// input so it can be signed by a wallet which holds the private keys.
+func (w *LibbitcoinWallet) CreateUnsignedTransaction(opts bitcoin.SpendOptions) (*bitcoin.UnsignedTransaction, error) {
+	opts.DryRun = true
+	result, err := w.SpendWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(result.Tx)); err != nil {
+		return nil, err
+	}
+	utx := &bitcoin.UnsignedTransaction{
+		Tx:     result.Tx,
+		Fee:    result.Fee,
+		Change: result.Change,
+	}
+	for _, in := range tx.TxIn {
+		txid, _ := hex.DecodeString(in.PreviousOutPoint.Hash.String())
+		var coin *bitcoin.Utxo
+		for _, u := range w.db.Coins().GetAll() {
+			if bytes.Equal(u.Txid, txid) && uint32(u.Index) == in.PreviousOutPoint.Index {
+				coin = &u
+				break
+			}
+		}
+		if coin == nil {
+			return nil, errors.New("Input coin not found in wallet")
+		}
+		key, err := w.db.Keys().GetKeyForScript(coin.ScriptPubKey)
+		if err != nil {
+			return nil, err
+		}
+		path, err := w.keyPath(key)
+		if err != nil {
+			return nil, err
+		}
+		utx.Inputs = append(utx.Inputs, bitcoin.UnsignedInput{
+			Outpoint:     in.PreviousOutPoint,
+			Value:        int64(coin.Value),
+			ScriptPubKey: coin.ScriptPubKey,
+			KeyPath:      path,
+		})
+	}
+	return utx, nil
+}
+
+// Sign an unsigned transaction using the key paths of its inputs. Each key must pay to
+// the script of the input it signs so we never sign for coins we don't control.
+func (w *LibbitcoinWallet) SignTransaction(utx *bitcoin.UnsignedTransaction) ([]byte, error) {
+	if w.WatchOnly() {
+		return nil, errWatchOnly
+	}
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(utx.Tx)); err != nil {
+		return nil, err
+	}
+	coinMap := make(map[coinset.Coin]*bip32.Key)
+	for _, in := range utx.Inputs {
+		purpose, index, err := bitcoin.ParseKeyPath(in.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key := w.generateChildKey(purpose, index)
+		if !w.keyPaysTo(key, in.ScriptPubKey) {
+			return nil, errors.New("Key path " + in.KeyPath + " does not match the input script")
+		}
+		c := NewCoin(in.Outpoint.Hash.Bytes(), in.Outpoint.Index, btc.Amount(in.Value), 0, in.ScriptPubKey)
+		coinMap[c] = key
+	}
+	witnesses, err := w.signTransaction(tx, coinMap)
+	if err != nil {
+		return nil, err
+	}
+	if witnesses != nil {
+		return serializeWitnessTx(tx, witnesses), nil
+	}
+	var b bytes.Buffer
+	tx.Serialize(&b)
+	return b.Bytes(), nil
+}
+
+// Broadcast a transaction signed offline and add it to the wallet. Signatures for our own
+// legacy coins are verified first so a bad signer is caught before the transaction is relayed.
+func (w *LibbitcoinWallet) BroadcastTransaction(raw []byte) (string, error) {
+	tx, witnesses, err := deserializeWitnessTx(raw)
+	if err != nil {
+		return "", err
+	}
+	for i, in := range tx.TxIn {
+		if len(in.SignatureScript) == 0 && (witnesses == nil || len(witnesses[i]) == 0) {
+			return "", errors.New("Transaction is not fully signed")
+		}
+		txid, _ := hex.DecodeString(in.PreviousOutPoint.Hash.String())
+		for _, u := range w.db.Coins().GetAll() {
+			if !bytes.Equal(u.Txid, txid) || uint32(u.Index) != in.PreviousOutPoint.Index || isWitnessScript(u.ScriptPubKey) {
+				continue
+			}
+			engine, err := txscript.NewEngine(u.ScriptPubKey, tx, i, txscript.StandardVerifyFlags, nil)
+			if err != nil {
+				return "", err
+			}
+			if err := engine.Execute(); err != nil {
+				return "", errors.New("Invalid signature: " + err.Error())
+			}
+		}
+	}
+
+	errChan := make(chan error, 1)
+	w.Client.Broadcast(raw, func(i interface{}, err error) {
+		errChan <- err
+	})
+	select {
+	case err := <-errChan:
+		if err != nil {
+			return "", err
+		}
+	case <-time.After(time.Minute):
+		return "", errFetchTimeout
+	}
+	log.Infof("Broadcast tx %s to bitcoin network\n", tx.TxSha().String())
+	w.processTransaction(btc.NewTx(tx), raw, 0)
+	return tx.TxSha().String(), nil
+}
+
+// Find the BIP32 path of one of our keys
+func (w *LibbitcoinWallet) keyPath(key *bip32.Key) (string, error) {
+	index := binary.BigEndian.Uint32(key.ChildNumber)
+	for _, purpose := range []bitcoin.KeyPurpose{bitcoin.RECEIVING, bitcoin.CHANGE, bitcoin.REFUND} {
+		if bytes.Equal(w.generateChildKey(purpose, index).PublicKey().Key, key.PublicKey().Key) {
+			return bitcoin.KeyPath(purpose, index), nil
+		}
+	}
+	return "", errors.New("Key is not derived from the account key")
+}
+
+// Whether the script pays the key's legacy or nested segwit address
+func (w *LibbitcoinWallet) keyPaysTo(key *bip32.Key, script []byte) bool {
+	pubKey, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+	legacy, _ := txscript.PayToAddrScript(pubKey.AddressPubKeyHash())
+	nestedAddr, _ := btc.NewAddressScriptHash(witnessRedeemScript(key.PublicKey().Key), w.params)
+	nested, _ := txscript.PayToAddrScript(nestedAddr)
+	return bytes.Equal(script, legacy) || bytes.Equal(script, nested)
+}
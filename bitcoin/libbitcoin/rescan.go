@@ -0,0 +1,178 @@
+package libbitcoin
+
+import (
+	"time"
+
+	"github.com/OpenBazaar/go-libbitcoinclient"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/txscript"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+// The number of consecutive keys without history after which we stop scanning a
+// chain, if not set in the config. This is the same gap limit as BIP 44.
+const DefaultGapLimit = 20
+
+// Rebuild the keys, transactions and coins tables from the master key. Each key chain is
+// derived until gapLimit consecutive keys have no history on the libbitcoin server. Both
+// the legacy and segwit address of each key are checked so coins received before the
+// address type was changed are found. All history is fetched before the database is
+// modified so a failure part way through leaves the wallet as it was.
+func (w *LibbitcoinWallet) Rescan() error {
+	w.chainLock.Lock()
+	defer w.chainLock.Unlock()
+
+	log.Info("Rescanning wallet")
+	heights := make(map[string]uint32)
+	for _, purpose := range []bitcoin.KeyPurpose{bitcoin.RECEIVING, bitcoin.CHANGE, bitcoin.REFUND} {
+		if err := w.scanChain(purpose, heights); err != nil {
+			return err
+		}
+	}
+	txs := make(map[string]*btc.Tx)
+	for txid := range heights {
+		tx, err := w.fetchTransaction(txid)
+		if err != nil {
+			return err
+		}
+		txs[txid] = tx
+	}
+
+	for _, u := range w.db.Coins().GetAll() {
+		w.db.Coins().Delete(u.Txid, u.Index)
+	}
+	for _, tx := range w.db.Transactions().GetAll() {
+		w.db.Transactions().Delete(tx.Txid)
+	}
+
+	// Process parents before their children so spends delete the coins they spend.
+	processed := make(map[string]bool)
+	for len(processed) < len(txs) {
+		progress := false
+		for txid, tx := range txs {
+			if processed[txid] || !parentsProcessed(tx, txs, processed) {
+				continue
+			}
+			w.ProcessTransaction(tx, heights[txid])
+			processed[txid] = true
+			progress = true
+		}
+		if !progress { // Can't happen unless the server returned a cycle
+			break
+		}
+	}
+	go w.subscribeAll()
+	log.Infof("Rescan complete, found %d transactions\n", len(txs))
+	return nil
+}
+
+// Derive keys on the chain until gapLimit consecutive keys are unused, saving each key
+// in the db and recording the height of every transaction found in heights.
+func (w *LibbitcoinWallet) scanChain(purpose bitcoin.KeyPurpose, heights map[string]uint32) error {
+	unused := 0
+	for index := uint32(0); unused < w.gapLimit; index++ {
+		key := w.generateChildKey(purpose, index)
+		script, used, err := w.scanKey(key, heights)
+		if err != nil {
+			return err
+		}
+		w.db.Keys().Put(key, script, purpose) // Fails harmlessly if we already have the key
+		if used {
+			w.db.Keys().MarkKeyAsUsed(key)
+			unused = 0
+		} else {
+			unused++
+		}
+	}
+	return nil
+}
+
+// Fetch the history of the key's legacy and segwit addresses. Returns the script of the
+// address with history or, if neither has any, the script for the configured address type.
+func (w *LibbitcoinWallet) scanKey(key *b32.Key, heights map[string]uint32) ([]byte, bool, error) {
+	pubKey, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+	legacy := pubKey.AddressPubKeyHash()
+	nested, _ := btc.NewAddressScriptHash(witnessRedeemScript(key.PublicKey().Key), w.params)
+	var script []byte
+	for _, addr := range []btc.Address{legacy, nested} {
+		history, err := w.fetchHistory(addr)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(history) == 0 {
+			continue
+		}
+		if script != nil {
+			log.Warningf("Both address types of key %s have history, only %s will be tracked\n", legacy, addr)
+		}
+		script, _ = txscript.PayToAddrScript(addr)
+		for _, h := range history {
+			heights[h.TxHash] = h.Height
+		}
+	}
+	if script == nil {
+		script, _ = txscript.PayToAddrScript(w.addressForKey(key))
+		return script, false, nil
+	}
+	return script, true, nil
+}
+
+func parentsProcessed(tx *btc.Tx, txs map[string]*btc.Tx, processed map[string]bool) bool {
+	for _, in := range tx.MsgTx().TxIn {
+		parent := in.PreviousOutPoint.Hash.String()
+		if _, ok := txs[parent]; ok && !processed[parent] {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *LibbitcoinWallet) fetchHistory(addr btc.Address) ([]libbitcoin.FetchHistory2Resp, error) {
+	type result struct {
+		history []libbitcoin.FetchHistory2Resp
+		err     error
+	}
+	c := make(chan result, 1)
+	w.Client.FetchHistory2(addr, 0, func(i interface{}, err error) {
+		if err != nil {
+			c <- result{nil, err}
+			return
+		}
+		c <- result{i.([]libbitcoin.FetchHistory2Resp), nil}
+	})
+	select {
+	case r := <-c:
+		return r.history, r.err
+	case <-time.After(time.Minute):
+		return nil, errFetchTimeout
+	}
+}
+
+// Fetch a transaction from the mempool or, if it isn't there, the blockchain.
+func (w *LibbitcoinWallet) fetchTransaction(txid string) (*btc.Tx, error) {
+	type result struct {
+		tx  *btc.Tx
+		err error
+	}
+	c := make(chan result, 1)
+	w.Client.FetchUnconfirmedTransaction(txid, func(i interface{}, err error) {
+		if err == nil {
+			c <- result{i.(*btc.Tx), nil}
+			return
+		}
+		w.Client.FetchTransaction(txid, func(i interface{}, err error) {
+			if err != nil {
+				c <- result{nil, err}
+				return
+			}
+			c <- result{i.(*btc.Tx), nil}
+		})
+	})
+	select {
+	case r := <-c:
+		return r.tx, r.err
+	case <-time.After(2 * time.Minute):
+		return nil, errFetchTimeout
+	}
+}
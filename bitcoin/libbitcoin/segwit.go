@@ -0,0 +1,223 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+type AddressType string
+
+const (
+	// Legacy pay to pubkey hash addresses
+	P2PKH AddressType = "p2pkh"
+
+	// Pay to witness pubkey hash nested in a pay to script hash address. These can be
+	// paid by any wallet and are indexed by the libbitcoin server like any other P2SH address.
+	P2SHP2WPKH AddressType = "p2sh-p2wpkh"
+
+	// Native pay to witness pubkey hash
+	P2WPKH AddressType = "p2wpkh"
+)
+
+// Worst case virtual size of a P2SH-P2WPKH input. The non-witness part is the outpoint,
+// the 23 byte script sig pushing the redeem script and the sequence. The witness is a
+// 73 byte signature and 33 byte pubkey plus their length prefixes and item count
+// which is discounted to a quarter.
+const redeemP2SHP2WPKHInputSize = 32 + 4 + 1 + 23 + 4 + (1 + 1 + 73 + 1 + 33 + 3) / 4
+
+func ParseAddressType(s string) (AddressType, error) {
+	switch AddressType(s) {
+	case "", P2PKH:
+		return P2PKH, nil
+	case P2SHP2WPKH:
+		return P2SHP2WPKH, nil
+	case P2WPKH:
+		return "", errors.New("Native segwit addresses cannot be watched through the libbitcoin server, use p2sh-p2wpkh")
+	}
+	return "", fmt.Errorf("Unknown address type %s", s)
+}
+
+// Return the address for the key using the wallet's configured address type.
+func (w *LibbitcoinWallet) addressForKey(key *b32.Key) btc.Address {
+	if w.addressType == P2SHP2WPKH {
+		addr, _ := btc.NewAddressScriptHash(witnessRedeemScript(key.PublicKey().Key), w.params)
+		return addr
+	}
+	addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+	return addr.AddressPubKeyHash()
+}
+
+// The version zero witness program for a compressed pubkey: OP_0 <hash160(pubkey)>
+func witnessRedeemScript(pubKey []byte) []byte {
+	return append([]byte{txscript.OP_0, 0x14}, btc.Hash160(pubKey)...)
+}
+
+// Our wallet only creates P2SH scripts for nested witness keys so we treat any
+// P2SH coin as a P2SH-P2WPKH coin.
+func isWitnessScript(pkScript []byte) bool {
+	return txscript.GetScriptClass(pkScript) == txscript.ScriptHashTy
+}
+
+func inputSize(pkScript []byte) int {
+	if isWitnessScript(pkScript) {
+		return redeemP2SHP2WPKHInputSize
+	}
+	return redeemP2PKHInputSize
+}
+
+// Sign a P2SH-P2WPKH input, returning the signature script and witness.
+func signWitnessInput(tx *wire.MsgTx, idx int, amount int64, privKey *btcec.PrivateKey) ([]byte, [][]byte, error) {
+	pubKey := privKey.PubKey().SerializeCompressed()
+	redeemScript := witnessRedeemScript(pubKey)
+	sigScript, err := txscript.NewScriptBuilder().AddData(redeemScript).Script()
+	if err != nil {
+		return nil, nil, err
+	}
+	scriptCode, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(btc.Hash160(pubKey)).AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		return nil, nil, err
+	}
+	hash := witnessSignatureHash(tx, idx, scriptCode, amount, txscript.SigHashAll)
+	sig, err := privKey.Sign(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	witness := [][]byte{append(sig.Serialize(), byte(txscript.SigHashAll)), pubKey}
+	return sigScript, witness, nil
+}
+
+// Calculate the BIP 143 signature hash for a version zero witness input.
+func witnessSignatureHash(tx *wire.MsgTx, idx int, scriptCode []byte, amount int64, hashType txscript.SigHashType) []byte {
+	var prevouts, sequences, outputs bytes.Buffer
+	for _, in := range tx.TxIn {
+		prevouts.Write(in.PreviousOutPoint.Hash[:])
+		binary.Write(&prevouts, binary.LittleEndian, in.PreviousOutPoint.Index)
+		binary.Write(&sequences, binary.LittleEndian, in.Sequence)
+	}
+	for _, out := range tx.TxOut {
+		binary.Write(&outputs, binary.LittleEndian, out.Value)
+		wire.WriteVarBytes(&outputs, 0, out.PkScript)
+	}
+
+	in := tx.TxIn[idx]
+	var preimage bytes.Buffer
+	binary.Write(&preimage, binary.LittleEndian, tx.Version)
+	preimage.Write(wire.DoubleSha256(prevouts.Bytes()))
+	preimage.Write(wire.DoubleSha256(sequences.Bytes()))
+	preimage.Write(in.PreviousOutPoint.Hash[:])
+	binary.Write(&preimage, binary.LittleEndian, in.PreviousOutPoint.Index)
+	wire.WriteVarBytes(&preimage, 0, scriptCode)
+	binary.Write(&preimage, binary.LittleEndian, amount)
+	binary.Write(&preimage, binary.LittleEndian, in.Sequence)
+	preimage.Write(wire.DoubleSha256(outputs.Bytes()))
+	binary.Write(&preimage, binary.LittleEndian, tx.LockTime)
+	binary.Write(&preimage, binary.LittleEndian, uint32(hashType))
+	return wire.DoubleSha256(preimage.Bytes())
+}
+
+// Serialize a transaction in the BIP 144 witness format. The wire package predates
+// segwit so the witnesses are passed separately, one stack per input. Inputs without
+// a witness should have a nil entry.
+func serializeWitnessTx(tx *wire.MsgTx, witnesses [][][]byte) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, tx.Version)
+	b.Write([]byte{0x00, 0x01}) // Marker and flag
+	wire.WriteVarInt(&b, 0, uint64(len(tx.TxIn)))
+	for _, in := range tx.TxIn {
+		b.Write(in.PreviousOutPoint.Hash[:])
+		binary.Write(&b, binary.LittleEndian, in.PreviousOutPoint.Index)
+		wire.WriteVarBytes(&b, 0, in.SignatureScript)
+		binary.Write(&b, binary.LittleEndian, in.Sequence)
+	}
+	wire.WriteVarInt(&b, 0, uint64(len(tx.TxOut)))
+	for _, out := range tx.TxOut {
+		binary.Write(&b, binary.LittleEndian, out.Value)
+		wire.WriteVarBytes(&b, 0, out.PkScript)
+	}
+	for i := range tx.TxIn {
+		var witness [][]byte
+		if i < len(witnesses) {
+			witness = witnesses[i]
+		}
+		wire.WriteVarInt(&b, 0, uint64(len(witness)))
+		for _, item := range witness {
+			wire.WriteVarBytes(&b, 0, item)
+		}
+	}
+	binary.Write(&b, binary.LittleEndian, tx.LockTime)
+	return b.Bytes()
+}
+
+// Deserialize a transaction in either the legacy or BIP 144 witness format. The witness
+// stacks are returned separately, one per input, or nil for a legacy transaction.
+func deserializeWitnessTx(raw []byte) (*wire.MsgTx, [][][]byte, error) {
+	tx := wire.NewMsgTx()
+	if len(raw) < 6 || raw[4] != 0x00 || raw[5] != 0x01 {
+		err := tx.Deserialize(bytes.NewReader(raw))
+		return tx, nil, err
+	}
+	r := bytes.NewReader(raw[6:])
+	tx.Version = int32(binary.LittleEndian.Uint32(raw[:4]))
+	numIn, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := uint64(0); i < numIn; i++ {
+		var in wire.TxIn
+		if _, err := io.ReadFull(r, in.PreviousOutPoint.Hash[:]); err != nil {
+			return nil, nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.PreviousOutPoint.Index); err != nil {
+			return nil, nil, err
+		}
+		if in.SignatureScript, err = wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "sigScript"); err != nil {
+			return nil, nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.Sequence); err != nil {
+			return nil, nil, err
+		}
+		tx.AddTxIn(&in)
+	}
+	numOut, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := uint64(0); i < numOut; i++ {
+		var out wire.TxOut
+		if err := binary.Read(r, binary.LittleEndian, &out.Value); err != nil {
+			return nil, nil, err
+		}
+		if out.PkScript, err = wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "pkScript"); err != nil {
+			return nil, nil, err
+		}
+		tx.AddTxOut(&out)
+	}
+	witnesses := make([][][]byte, numIn)
+	for i := range witnesses {
+		numItems, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j := uint64(0); j < numItems; j++ {
+			item, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload, "witness")
+			if err != nil {
+				return nil, nil, err
+			}
+			witnesses[i] = append(witnesses[i], item)
+		}
+	}
+	if err := binary.Read(r, binary.LittleEndian, &tx.LockTime); err != nil {
+		return nil, nil, err
+	}
+	return tx, witnesses, nil
+}
@@ -0,0 +1,111 @@
+package libbitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// The P2SH-P2WPKH example from BIP 143
+const (
+	bip143UnsignedTx = "0100000001db6b1b20aa0fd7b23880be2ecbd4a98130974cf4748fb66092ac4d3ceb1a54770100000000feffffff02b8b4eb0b000000001976a914a457b684d7f0d539a46a45bbc043f35b59d0d96388ac0008af2f000000001976a914fd270b1ee6abcaea97fea7ad0402e8bd8ad6d77c88ac92040000"
+	bip143PrivKey    = "eb696a065ef48a2192da5b28b694f87544b30fae8327c4510137a922f32c6dcf"
+	bip143SigHash    = "64f3b0f4dd2bb3aa1ce8566d220cc74dda9df97d8490cc81d89d735c92e59fb6"
+	bip143SignedTx   = "01000000000101db6b1b20aa0fd7b23880be2ecbd4a98130974cf4748fb66092ac4d3ceb1a5477010000001716001479091972186c449eb1ded22b78e40d009bdf0089feffffff02b8b4eb0b000000001976a914a457b684d7f0d539a46a45bbc043f35b59d0d96388ac0008af2f000000001976a914fd270b1ee6abcaea97fea7ad0402e8bd8ad6d77c88ac02473044022047ac8e878352d3ebbde1c94ce3a10d057c24175747116f8288e5d794d12d482f0220217f36a485cae903c713331d877c1f64677e3622ad4010726870540656fe9dcb012103ad1d8e89212f0b92c74d23bb710c00662ad1470198ac48c43f7d6f93a2a2687392040000"
+	bip143Amount     = 1000000000
+)
+
+func bip143Tx(t *testing.T) *wire.MsgTx {
+	b, _ := hex.DecodeString(bip143UnsignedTx)
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestWitnessSignatureHash(t *testing.T) {
+	tx := bip143Tx(t)
+	scriptCode, _ := hex.DecodeString("76a91479091972186c449eb1ded22b78e40d009bdf008988ac")
+	hash := witnessSignatureHash(tx, 0, scriptCode, bip143Amount, txscript.SigHashAll)
+	if hex.EncodeToString(hash) != bip143SigHash {
+		t.Errorf("Expected sighash %s got %x", bip143SigHash, hash)
+	}
+}
+
+func TestSignWitnessInput(t *testing.T) {
+	tx := bip143Tx(t)
+	keyBytes, _ := hex.DecodeString(bip143PrivKey)
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), keyBytes)
+	sigScript, witness, err := signWitnessInput(tx, 0, bip143Amount, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+	signed := serializeWitnessTx(tx, [][][]byte{witness})
+	if hex.EncodeToString(signed) != bip143SignedTx {
+		t.Errorf("Expected signed tx %s got %x", bip143SignedTx, signed)
+	}
+}
+
+func TestEstimateSerializeSize(t *testing.T) {
+	p2sh, _ := hex.DecodeString("a9144733f37cf4db86fbc2efed2500b4f4e49f31202387")
+	outputs := []*wire.TxOut{wire.NewTxOut(1000, p2pkhScript(0x01))}
+	legacy := estimateSerializeSize([][]byte{p2pkhScript(0x02)}, outputs, false)
+	if legacy != 8+1+1+redeemP2PKHInputSize+34 {
+		t.Errorf("Unexpected legacy size %d", legacy)
+	}
+	witness := estimateSerializeSize([][]byte{p2sh}, outputs, false)
+	if witness != 8+1+1+redeemP2SHP2WPKHInputSize+34+1 {
+		t.Errorf("Unexpected witness size %d", witness)
+	}
+	if witness >= legacy {
+		t.Error("Witness inputs should be cheaper than legacy inputs")
+	}
+}
+
+func TestParseAddressType(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected AddressType
+		valid    bool
+	}{
+		{"", P2PKH, true},
+		{"p2pkh", P2PKH, true},
+		{"p2sh-p2wpkh", P2SHP2WPKH, true},
+		{"p2wpkh", "", false},
+		{"p2tr", "", false},
+	}
+	for _, test := range tests {
+		addressType, err := ParseAddressType(test.in)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid=%t got error %v", test.in, test.valid, err)
+		}
+		if addressType != test.expected {
+			t.Errorf("%s: expected %s got %s", test.in, test.expected, addressType)
+		}
+	}
+}
+
+func TestDeserializeWitnessTx(t *testing.T) {
+	raw, _ := hex.DecodeString(bip143SignedTx)
+	tx, witnesses, err := deserializeWitnessTx(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(witnesses) != 1 || len(witnesses[0]) != 2 {
+		t.Fatalf("Expected one witness with two items, got %v", witnesses)
+	}
+	if !bytes.Equal(serializeWitnessTx(tx, witnesses), raw) {
+		t.Error("Witness transaction did not round trip")
+	}
+	raw, _ = hex.DecodeString(bip143UnsignedTx)
+	unsigned, witnesses, err := deserializeWitnessTx(raw)
+	if err != nil || witnesses != nil || len(unsigned.TxIn) != 1 {
+		t.Error("Failed to deserialize legacy transaction")
+	}
+}
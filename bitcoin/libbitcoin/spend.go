@@ -18,6 +18,7 @@ import (
 	btc "github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcd/btcec"
 	"encoding/hex"
+	"fmt"
 )
 
 type Coin struct {
@@ -47,68 +48,133 @@ func NewCoin(txid []byte, index uint32, value btc.Amount, numConfs int64, script
 	return coinset.Coin(c)
 }
 
-func (w *LibbitcoinWallet) gatherCoins() map[coinset.Coin]*bip32.Key {
+// Worst case sizes used for fee estimation. These match the estimates txauthor uses
+// for P2PKH inputs and outputs.
+const (
+	redeemP2PKHInputSize = 32 + 4 + 1 + 1 + 73 + 1 + 33 + 4
+	p2pkhOutputSize      = 8 + 1 + 25
+)
+
+var errInsufficientFunds = errors.New("insuffient funds")
+
+// Return the wallet's coins mapped to their keys. If outpoints are given only those
+// coins are returned and an error is raised if any of them are not in the wallet.
+func (w *LibbitcoinWallet) gatherCoins(outpoints []wire.OutPoint) (map[coinset.Coin]*bip32.Key, error) {
 	utxos := w.db.Coins().GetAll()
+	bestHeight, _, _ := w.db.Headers().GetBest()
 	m := make(map[coinset.Coin]*bip32.Key)
+	found := make(map[wire.OutPoint]bool)
 	for _, u := range(utxos) {
 		sha, _ := wire.NewShaHashFromStr(hex.EncodeToString(u.Txid))
-		c := NewCoin(sha.Bytes(), uint32(u.Index), btc.Amount(int64(u.Value)), 0, u.ScriptPubKey)
+		if len(outpoints) > 0 && !containsOutpoint(outpoints, *wire.NewOutPoint(sha, uint32(u.Index))) {
+			continue
+		}
+		c := NewCoin(sha.Bytes(), uint32(u.Index), btc.Amount(int64(u.Value)), w.numConfs(u.Txid, bestHeight), u.ScriptPubKey)
 		key, err := w.db.Keys().GetKeyForScript(u.ScriptPubKey)
 		if err != nil {
 			continue
 		}
 		m[c] = key
+		found[*wire.NewOutPoint(sha, uint32(u.Index))] = true
+	}
+	for _, op := range outpoints {
+		if !found[op] {
+			return nil, fmt.Errorf("Coin %s not found in wallet", op.String())
+		}
 	}
-	return m
+	return m, nil
+}
+
+// Number of confirmations of a transaction given the height of the chain tip. If we
+// haven't synced any headers yet a confirmed transaction is counted as one confirmation.
+func (w *LibbitcoinWallet) numConfs(txid []byte, bestHeight int) int64 {
+	height, err := w.db.Transactions().GetHeight(txid)
+	if err != nil || height <= 0 {
+		return 0
+	}
+	if bestHeight < height {
+		return 1
+	}
+	return int64(bestHeight - height + 1)
+}
+
+func containsOutpoint(outpoints []wire.OutPoint, op wire.OutPoint) bool {
+	for _, o := range outpoints {
+		if o == op {
+			return true
+		}
+	}
+	return false
 }
 
 func (w *LibbitcoinWallet) Spend(amount int64, addr btc.Address, feeLevel bitcoin.FeeLevel) error {
-	// Check for dust
-	script, _ := txscript.PayToAddrScript(addr)
-	if txrules.IsDustAmount(btc.Amount(amount), len(script), txrules.DefaultRelayFeePerKb) {
-		return errors.New("Amount is below dust threshold")
+	_, err := w.SpendWithOptions(bitcoin.SpendOptions{
+		Outputs:  []bitcoin.SpendOutput{{Address: addr, Amount: amount}},
+		FeeLevel: feeLevel,
+	})
+	return err
+}
+
+func (w *LibbitcoinWallet) SpendWithOptions(opts bitcoin.SpendOptions) (*bitcoin.SpendResult, error) {
+	if w.WatchOnly() && !opts.DryRun {
+		return nil, errWatchOnly
+	}
+	if len(opts.Outputs) == 0 {
+		return nil, errors.New("No outputs specified")
+	}
+	if opts.SendAll && len(opts.Outputs) != 1 {
+		return nil, errors.New("Send all requires exactly one output")
 	}
 
-	var additionalPrevScripts map[wire.OutPoint][]byte
-	var additionalKeysByAddress map[string]*btc.WIF
+	// Build the outputs and check for dust
+	var outputs []*wire.TxOut
+	for _, o := range opts.Outputs {
+		script, err := txscript.PayToAddrScript(o.Address)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.SendAll && txrules.IsDustAmount(btc.Amount(o.Amount), len(script), txrules.DefaultRelayFeePerKb) {
+			return nil, errors.New("Amount is below dust threshold")
+		}
+		outputs = append(outputs, wire.NewTxOut(o.Amount, script))
+	}
 
-	// Create input source
-	coinMap := w.gatherCoins()
+	// Gather the coins we're allowed to spend
+	coinMap, err := w.gatherCoins(opts.Inputs)
+	if err != nil {
+		return nil, err
+	}
 	coins := make([]coinset.Coin, 0, len(coinMap))
 	for k := range coinMap {
 		coins = append(coins, k)
 	}
-	inputSource := func(target btc.Amount) (total btc.Amount, inputs []*wire.TxIn, scripts [][]byte, err error) {
-		// TODO: maybe change the coin selection algorithm? We're using min coins right now because
-		// TODO: we don't know the number of confirmations on each coin without querying the libbitcoin server.
-		coinSelector := coinset.MinNumberCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(10000)}
-		coins, err := coinSelector.CoinSelect(target, coins)
-		if err != nil {
-			return total, inputs, scripts, errors.New("insuffient funds")
-		}
-		additionalPrevScripts = make(map[wire.OutPoint][]byte)
-		additionalKeysByAddress = make(map[string]*btc.WIF)
-		for _, c := range(coins.Coins()) {
-			total += c.Value()
-			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
-			in := wire.NewTxIn(outpoint, []byte{})
-			in.Sequence = 0 // Opt-in RBF so we can bump fees
-			inputs = append(inputs, in)
-			additionalPrevScripts[*outpoint] = c.PkScript()
-			key := coinMap[c]
-			addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
-			pk, _ := btcec.PrivKeyFromBytes(btcec.S256(), key.Key)
-			wif, _ := btc.NewWIF(pk, w.params, true)
-			additionalKeysByAddress[addr.AddressPubKeyHash().EncodeAddress()] = wif
-		}
-		return total, inputs, scripts, nil
-	}
+	spendAllCoins := opts.SendAll || len(opts.Inputs) > 0
 
 	// Get the fee per kilobyte
-	feePerKB := int64(w.getFeePerByte(feeLevel)) * 1000
+	feePerByte := opts.FeePerByte
+	if feePerByte == 0 {
+		feePerByte = w.getFeePerByte(opts.FeeLevel)
+	}
+	feePerKB := btc.Amount(int64(feePerByte) * 1000)
 
-	// outputs
-	out := wire.NewTxOut(amount, script)
+	// Select coins. When the fee is subtracted from the outputs the selected coins only
+	// need to cover the output amounts.
+	selected := coins
+	if !spendAllCoins {
+		var target btc.Amount
+		for _, out := range outputs {
+			target += btc.Amount(out.Value)
+		}
+		selectionFeePerKB := btc.Amount(0)
+		if !opts.SubtractFee {
+			selectionFeePerKB = feePerKB
+			target += txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(nil, outputs, false))
+		}
+		selected, err = selectCoins(coins, target, selectionFeePerKB, w.coinSelection)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Create change source
 	changeSource := func() ([]byte, error) {
@@ -120,39 +186,45 @@ func (w *LibbitcoinWallet) Spend(amount int64, addr btc.Address, feeLevel bitcoi
 		return script, nil
 	}
 
-	authoredTx, err := txauthor.NewUnsignedTransaction([]*wire.TxOut{out,}, btc.Amount(feePerKB), inputSource, changeSource)
+	authoredTx, err := buildTransaction(outputs, selected, feePerKB, opts.SubtractFee || opts.SendAll, opts.SendAll, changeSource)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := new(bitcoin.SpendResult)
+	if authoredTx.ChangeIndex >= 0 {
+		result.Change = authoredTx.Tx.TxOut[authoredTx.ChangeIndex].Value
+	}
+	result.Fee = int64(authoredTx.TotalInput)
+	for _, out := range authoredTx.Tx.TxOut {
+		result.Fee -= out.Value
 	}
 
 	// BIP 69 sorting
 	txsort.InPlaceSort(authoredTx.Tx)
 
-	// Sign tx
-	getKey := txscript.KeyClosure(func(addr btc.Address) (
-	*btcec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif := additionalKeysByAddress[addrStr]
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	getScript := txscript.ScriptClosure(func(
-		addr btc.Address) ([]byte, error) {
-		return []byte{}, nil
-	})
-	for i, txIn := range authoredTx.Tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		script, err := txscript.SignTxOutput(w.params,
-			authoredTx.Tx, i, prevOutScript, txscript.SigHashAll, getKey,
-			getScript, txIn.SignatureScript)
+	var witnesses [][][]byte
+	if !opts.DryRun {
+		witnesses, err = w.signTransaction(authoredTx.Tx, coinMap)
 		if err != nil {
-			return errors.New("Failed to sign transaction")
+			return nil, err
 		}
-		txIn.SignatureScript = script
 	}
 
-	// Broadcast tx to bitcoin network
+	// The txid doesn't commit to the witnesses so it's the hash of the legacy serialization
+	// either way, but the network needs the witnesses.
 	serializedTx := new(bytes.Buffer)
 	authoredTx.Tx.Serialize(serializedTx)
+	if witnesses != nil {
+		serializedTx = bytes.NewBuffer(serializeWitnessTx(authoredTx.Tx, witnesses))
+	}
+	result.Tx = serializedTx.Bytes()
+	result.Txid = authoredTx.Tx.TxSha().String()
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// Broadcast tx to bitcoin network
 	w.Client.Broadcast(serializedTx.Bytes(), func(i interface{}, err error){
 		if err == nil {
 			log.Infof("Broadcast tx %s to bitcoin network\n", authoredTx.Tx.TxSha().String())
@@ -162,9 +234,172 @@ func (w *LibbitcoinWallet) Spend(amount int64, addr btc.Address, feeLevel bitcoi
 	})
 
 	// Update the db
-	w.ProcessTransaction(btc.NewTx(authoredTx.Tx), 0)
+	w.processTransaction(btc.NewTx(authoredTx.Tx), serializedTx.Bytes(), 0)
+
+	return result, nil
+}
+
+func newTxIn(c coinset.Coin) *wire.TxIn {
+	outpoint := wire.NewOutPoint(c.Hash(), c.Index())
+	in := wire.NewTxIn(outpoint, []byte{})
+	in.Sequence = 0 // Opt-in RBF so we can bump fees
+	return in
+}
+
+// Build an unsigned transaction spending all of the selected coins. A change output is added
+// if the leftover value is not dust, otherwise it goes to the miner. If subtractFee is set the
+// fee is deducted from the first output rather than being paid on top of the output amounts.
+// If sendAll is set the first output receives the total value of the coins less the fee.
+func buildTransaction(outputs []*wire.TxOut, selected []coinset.Coin, feePerKB btc.Amount, subtractFee bool,
+	sendAll bool, changeSource txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
+
+	var target btc.Amount
+	for _, out := range outputs {
+		target += btc.Amount(out.Value)
+	}
+	tx := wire.NewMsgTx()
+	var total btc.Amount
+	var scripts [][]byte
+	for _, c := range selected {
+		total += c.Value()
+		tx.AddTxIn(newTxIn(c))
+		scripts = append(scripts, c.PkScript())
+	}
+	if sendAll {
+		outputs[0].Value = int64(total)
+		target = total
+	}
+	if len(selected) == 0 || total < target {
+		return nil, errInsufficientFunds
+	}
+	for _, out := range outputs {
+		tx.AddTxOut(out)
+	}
+
+	feeWithChange := txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(scripts, outputs, true))
+	feeWithoutChange := txrules.FeeForSerializeSize(feePerKB, estimateSerializeSize(scripts, outputs, false))
+	remaining := total - target
+	changeAmount := remaining
+	if !subtractFee {
+		changeAmount -= feeWithChange
+	}
+	changeIndex := -1
+	var fee btc.Amount
+	if changeAmount > 0 && !txrules.IsDustAmount(changeAmount, p2pkhOutputSize - 9, feePerKB) {
+		fee = feeWithChange
+		changeScript, err := changeSource()
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(changeAmount), changeScript))
+		changeIndex = len(tx.TxOut) - 1
+	} else if subtractFee {
+		// Any dust left over goes to the miner and counts towards the fee.
+		fee = feeWithoutChange - remaining
+		if fee < 0 {
+			fee = 0
+		}
+	} else if remaining < feeWithoutChange {
+		return nil, errInsufficientFunds
+	}
+	if subtractFee {
+		outputs[0].Value -= int64(fee)
+		if outputs[0].Value <= 0 || txrules.IsDustOutput(outputs[0], txrules.DefaultRelayFeePerKb) {
+			return nil, errors.New("Amount is too small to pay the fee")
+		}
+	}
+	return &txauthor.AuthoredTx{
+		Tx:          tx,
+		PrevScripts: scripts,
+		TotalInput:  total,
+		ChangeIndex: changeIndex,
+	}, nil
+}
+
+// Estimate the worst case virtual size of a transaction spending inputs with the given
+// previous output scripts.
+func estimateSerializeSize(prevScripts [][]byte, outputs []*wire.TxOut, addChangeOutput bool) int {
+	outputCount := len(outputs)
+	changeSize := 0
+	if addChangeOutput {
+		outputCount++
+		changeSize = p2pkhOutputSize
+	}
+	size := 8 + wire.VarIntSerializeSize(uint64(len(prevScripts))) + wire.VarIntSerializeSize(uint64(outputCount)) + changeSize
+	hasWitness := false
+	for _, script := range prevScripts {
+		size += inputSize(script)
+		if isWitnessScript(script) {
+			hasWitness = true
+		}
+	}
+	if hasWitness {
+		size++ // Marker and flag
+	}
+	for _, out := range outputs {
+		size += out.SerializeSize()
+	}
+	return size
+}
 
-	return nil
+// Sign each input of the transaction with the key belonging to the coin it spends. Returns
+// the witness for each input, which is nil for legacy inputs, or nil if no input has a witness.
+func (w *LibbitcoinWallet) signTransaction(tx *wire.MsgTx, coinMap map[coinset.Coin]*bip32.Key) ([][][]byte, error) {
+	prevCoins := make(map[wire.OutPoint]coinset.Coin)
+	keysByAddress := make(map[string]*btc.WIF)
+	for c, key := range coinMap {
+		prevCoins[*wire.NewOutPoint(c.Hash(), c.Index())] = c
+		addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+		pk, _ := btcec.PrivKeyFromBytes(btcec.S256(), key.Key)
+		wif, _ := btc.NewWIF(pk, w.params, true)
+		keysByAddress[addr.AddressPubKeyHash().EncodeAddress()] = wif
+	}
+	getKey := txscript.KeyClosure(func(addr btc.Address) (
+	*btcec.PrivateKey, bool, error) {
+		addrStr := addr.EncodeAddress()
+		wif, ok := keysByAddress[addrStr]
+		if !ok {
+			return nil, false, errors.New("Key not found")
+		}
+		return wif.PrivKey, wif.CompressPubKey, nil
+	})
+	getScript := txscript.ScriptClosure(func(
+		addr btc.Address) ([]byte, error) {
+		return []byte{}, nil
+	})
+	witnesses := make([][][]byte, len(tx.TxIn))
+	hasWitness := false
+	for i, txIn := range tx.TxIn {
+		coin := prevCoins[txIn.PreviousOutPoint]
+		if coin != nil && isWitnessScript(coin.PkScript()) {
+			key := coinMap[coin]
+			addr, _ := btc.NewAddressPubKey(key.PublicKey().Key, w.params)
+			wif := keysByAddress[addr.AddressPubKeyHash().EncodeAddress()]
+			script, witness, err := signWitnessInput(tx, i, int64(coin.Value()), wif.PrivKey)
+			if err != nil {
+				return nil, errors.New("Failed to sign transaction")
+			}
+			txIn.SignatureScript = script
+			witnesses[i] = witness
+			hasWitness = true
+			continue
+		}
+		var prevOutScript []byte
+		if coin != nil {
+			prevOutScript = coin.PkScript()
+		}
+		script, err := txscript.SignTxOutput(w.params,
+			tx, i, prevOutScript, txscript.SigHashAll, getKey,
+			getScript, txIn.SignatureScript)
+		if err != nil {
+			return nil, errors.New("Failed to sign transaction")
+		}
+		txIn.SignatureScript = script
+	}
+	if !hasWitness {
+		return nil, nil
+	}
+	return witnesses, nil
 }
 
 func (w *LibbitcoinWallet) getFeePerByte(feeLevel bitcoin.FeeLevel) uint64 {
@@ -15,6 +15,12 @@ import (
 // In cases 1 and 2 we add the transaction to the database and update our utxo table.
 // In the last case we just update the height and/or state of the transaction.
 func (w *LibbitcoinWallet) ProcessTransaction(tx *btc.Tx, height uint32) {
+	w.processTransaction(tx, nil, height)
+}
+
+// If raw is not nil it is stored in the database in place of the serialized tx.
+// This lets us keep the witnesses of our own segwit transactions for rebroadcasting.
+func (w *LibbitcoinWallet) processTransaction(tx *btc.Tx, raw []byte, height uint32) {
 	txid, err := hex.DecodeString(tx.Sha().String())
 	if err != nil {
 		return
@@ -54,6 +60,9 @@ func (w *LibbitcoinWallet) ProcessTransaction(tx *btc.Tx, height uint32) {
 		// Put to database
 		serializedTx := new(bytes.Buffer)
 		tx.MsgTx().Serialize(serializedTx)
+		if raw != nil {
+			serializedTx = bytes.NewBuffer(raw)
+		}
 		var state bitcoin.TransactionState
 		if height > 0 {
 			state = bitcoin.CONFIRMED
@@ -1,13 +1,14 @@
 package libbitcoin
 
 import (
+	"sync"
 	"time"
 	"github.com/OpenBazaar/go-libbitcoinclient"
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/op/go-logging"
 	b32 "github.com/tyler-smith/go-bip32"
-	b39 "github.com/tyler-smith/go-bip39"
 	btc "github.com/btcsuite/btcutil"
 )
 
@@ -21,23 +22,38 @@ type LibbitcoinWallet struct {
 	masterPrivateKey *b32.Key
 	masterPublicKey  *b32.Key
 
+	// All wallet keys are derived from the account key. This is a public key in watch-only mode.
+	accountKey       *b32.Key
+
 	maxFee           uint64
 	priorityFee      uint64
 	normalFee        uint64
 	economicFee      uint64
 	feeAPI           string
 
+	coinSelection    CoinSelectionStrategy
+	addressType      AddressType
+	gapLimit         int
+
 	db               repo.Datastore
+
+	chainLock        sync.Mutex
+	reorgListeners   []func(forkHeight int)
 }
 
-func NewLibbitcoinWallet(mnemonic string, params *chaincfg.Params, db repo.Datastore, servers []libbitcoin.Server,
-	maxFee uint64, lowFee uint64, mediumFee uint64, highFee uint64, feeApi string) *LibbitcoinWallet {
+func NewLibbitcoinWallet(seed []byte, params *chaincfg.Params, db repo.Datastore, servers []libbitcoin.Server,
+	maxFee uint64, lowFee uint64, mediumFee uint64, highFee uint64, feeApi string, coinSelection CoinSelectionStrategy, addressType AddressType,
+	gapLimit int, watchOnlyKey *b32.Key) *LibbitcoinWallet {
 
-	seed := b39.NewSeed(mnemonic, "")
 	mk, _ := b32.NewMasterKey(seed)
 	l := new(LibbitcoinWallet)
 	l.masterPrivateKey = mk
 	l.masterPublicKey = mk.PublicKey()
+	if watchOnlyKey != nil {
+		l.accountKey = watchOnlyKey
+	} else {
+		l.accountKey, _ = mk.NewChildKey(b32.FirstHardenedChild)
+	}
 	l.params = params
 	l.Client = libbitcoin.NewLibbitcoinClient(servers, params)
 	l.db = db
@@ -46,24 +62,32 @@ func NewLibbitcoinWallet(mnemonic string, params *chaincfg.Params, db repo.Datas
 	l.normalFee = mediumFee
 	l.economicFee = lowFee
 	l.feeAPI = feeApi
+	l.coinSelection = coinSelection
+	l.addressType = addressType
+	l.gapLimit = gapLimit
 	go l.rebroadcastUnconfirmed()
 	go l.startUpdateLoop()
 	go l.subscribeAll()
 	return l
 }
 
-// Calls updateWalletBalances() once at start up and then every 24 hours after.
+// Calls updateWalletBalances() once at start up and then every hour after.
 // In theory we should not need to repeat this call but if the libbitcoin server isn't
 // correctly returning Subscribe data, then we will pick up missing transactions
-// when this loops through.
+// when this loops through. The chain tip is checked every minute for new blocks and reorgs.
 func (w *LibbitcoinWallet) startUpdateLoop() {
 	tick := time.NewTicker(time.Hour)
 	defer tick.Stop()
-	go w.updateWalletBalances()
+	headerTick := time.NewTicker(time.Minute)
+	defer headerTick.Stop()
+	w.updateWalletBalances(w.scanHeight())
+	go w.syncHeaders()
 	for {
 		select {
 		case <-tick.C:
-			go w.updateWalletBalances()
+			go w.updateWalletBalances(w.scanHeight())
+		case <-headerTick.C:
+			go w.syncHeaders()
 		}
 	}
 }
@@ -74,17 +98,12 @@ func (w *LibbitcoinWallet) rebroadcastUnconfirmed() {
 	}
 }
 
-// Loop through each address in the wallet and fetch the history from the libbitcoin server.
+// Loop through each address in the wallet and fetch the history from the libbitcoin server starting at fromHeight.
 // For each returned txid, fetch the full transaction, checking the mempool first then the blockchain.
 // If a transaction is returned well will parse it and check to see if we need to update our wallet state.
-func (w *LibbitcoinWallet) updateWalletBalances() {
-	keys, _ := w.db.Keys().GetAll()
-	for _, k := range(keys) {
-		addr, _ := btc.NewAddressPubKey(k.PublicKey().Key, w.params)
-		// FIXME: we don't want to fetch from height zero every time. Ideally it would use the height of the last
-		// FIXME: seen block but to handle cases where the server failed to send a transaction we should probably
-		// FIXME: use the last height of any transaction in the database ― which requires another db function.
-		w.Client.FetchHistory2(addr.AddressPubKeyHash(), 0, func(i interface{}, err error){
+func (w *LibbitcoinWallet) updateWalletBalances(fromHeight uint32) {
+	for _, addr := range(w.watchedAddresses()) {
+		w.Client.FetchHistory2(addr, fromHeight, func(i interface{}, err error){
 			for _, response := range(i.([]libbitcoin.FetchHistory2Resp)) {
 				w.fetchFullTx(response.TxHash, response.Height)
 			}
@@ -111,11 +130,24 @@ func (w *LibbitcoinWallet) fetchFullTx(txid string, height uint32) {
 }
 
 func (w *LibbitcoinWallet) subscribeAll() {
-	keys, _ := w.db.Keys().GetAll()
-	for _, k := range(keys) {
-		addr, _ := btc.NewAddressPubKey(k.PublicKey().Key, w.params)
-		w.SubscribeAddress(addr.AddressPubKeyHash())
+	for _, addr := range(w.watchedAddresses()) {
+		w.SubscribeAddress(addr)
+	}
+}
+
+// Return the address for each key's saved script. This covers both legacy and
+// segwit keys regardless of the configured address type.
+func (w *LibbitcoinWallet) watchedAddresses() []btc.Address {
+	var addrs []btc.Address
+	scripts, _ := w.db.Keys().GetAllScripts()
+	for _, script := range(scripts) {
+		_, a, _, err := txscript.ExtractPkScriptAddrs(script, w.params)
+		if err != nil || len(a) != 1 {
+			continue
+		}
+		addrs = append(addrs, a[0])
 	}
+	return addrs
 }
 
 func (w *LibbitcoinWallet) SubscribeAddress(addr btc.Address) {
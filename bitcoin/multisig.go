@@ -0,0 +1,123 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+// The size of a 2 of 3 multisig P2SH input in bytes, used to estimate release fees
+const RedeemMultisigInputSize = 297
+
+// The key a party to an order uses in the escrow multisig. It's the first child of the
+// party's master key under the order's chain code so the other parties can derive the
+// public key from the master public key.
+func EscrowKey(master *b32.Key, chaincode []byte) (*b32.Key, error) {
+	parent := &b32.Key{
+		Version:     master.Version,
+		Depth:       master.Depth,
+		ChildNumber: master.ChildNumber,
+		FingerPrint: master.FingerPrint,
+		ChainCode:   chaincode,
+		Key:         master.Key,
+		IsPrivate:   master.IsPrivate,
+	}
+	return NewChildKey(parent, 0)
+}
+
+// The signatures of one party to a multisig escrow, one for each input of the transaction
+type EscrowSignatures struct {
+	Pubkey     []byte
+	Signatures [][]byte
+}
+
+// Build a transaction spending the escrow coins to the outputs. As for wallet coins
+// the txids are in the byte order they're displayed in.
+func CreateRelease(inputs []Utxo, outputs []SpendOutput) (*wire.MsgTx, error) {
+	tx := wire.NewMsgTx()
+	for _, in := range inputs {
+		hash, err := wire.NewShaHashFromStr(hex.EncodeToString(in.Txid))
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(hash, uint32(in.Index)), nil))
+	}
+	for _, out := range outputs {
+		script, err := txscript.PayToAddrScript(out.Address)
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(out.Amount, script))
+	}
+	return tx, nil
+}
+
+// Sign each input of a transaction spending P2SH multisig coins with the redeem script
+func SignMultisig(tx *wire.MsgTx, redeemScript []byte, key *btcec.PrivateKey) ([][]byte, error) {
+	var sigs [][]byte
+	for i := range tx.TxIn {
+		sig, err := txscript.RawTxInSignature(tx, i, redeemScript, txscript.SigHashAll, key)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// Set the signature script of each input from the parties' signatures. The signatures
+// are ordered by the position of the party's key in the redeem script as
+// OP_CHECKMULTISIG requires.
+func CombineMultisig(tx *wire.MsgTx, redeemScript []byte, sigs []EscrowSignatures) error {
+	pubkeys, err := txscript.PushedData(redeemScript)
+	if err != nil {
+		return err
+	}
+	var ordered []EscrowSignatures
+	for _, pubkey := range pubkeys {
+		for _, s := range sigs {
+			if bytes.Equal(s.Pubkey, pubkey) {
+				ordered = append(ordered, s)
+			}
+		}
+	}
+	if len(ordered) != len(sigs) {
+		return errors.New("Signature from a key not in the redeem script")
+	}
+	for i, in := range tx.TxIn {
+		builder := txscript.NewScriptBuilder().AddOp(txscript.OP_0)
+		for _, s := range ordered {
+			if len(s.Signatures) != len(tx.TxIn) {
+				return errors.New("Missing signatures for the transaction inputs")
+			}
+			builder.AddData(s.Signatures[i])
+		}
+		script, err := builder.AddData(redeemScript).Script()
+		if err != nil {
+			return err
+		}
+		in.SignatureScript = script
+	}
+	return nil
+}
+
+// Does the transaction pay at least amount to the address?
+func PaysTo(tx *wire.MsgTx, addr btc.Address, amount int64) bool {
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return false
+	}
+	var total int64
+	for _, out := range tx.TxOut {
+		if bytes.Equal(out.PkScript, script) {
+			total += out.Value
+		}
+	}
+	return total >= amount
+}
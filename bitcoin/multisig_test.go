@@ -0,0 +1,132 @@
+package bitcoin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	btc "github.com/btcsuite/btcutil"
+	b32 "github.com/tyler-smith/go-bip32"
+)
+
+var testChaincode = bytes.Repeat([]byte{0x42}, 32)
+
+func newEscrowKey(t *testing.T, seed byte) *btcec.PrivateKey {
+	master, err := b32.NewMasterKey(bytes.Repeat([]byte{seed}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := EscrowKey(master, testChaincode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), key.Key)
+	return priv
+}
+
+func TestEscrowKey(t *testing.T) {
+	master, err := b32.NewMasterKey(bytes.Repeat([]byte{0x01}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := EscrowKey(master, testChaincode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The other parties derive our escrow public key from the master public key
+	pub, err := EscrowKey(master.PublicKey(), testChaincode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.PublicKey().Key, pub.Key) {
+		t.Error("Private and public derivations differ")
+	}
+	other, err := EscrowKey(master.PublicKey(), bytes.Repeat([]byte{0x43}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(other.Key, pub.Key) {
+		t.Error("Chain code was ignored")
+	}
+}
+
+func TestReleaseMultisig(t *testing.T) {
+	buyer, vendor, moderator := newEscrowKey(t, 1), newEscrowKey(t, 2), newEscrowKey(t, 3)
+	var pubkeys []*btc.AddressPubKey
+	for _, k := range []*btcec.PrivateKey{buyer, vendor, moderator} {
+		addr, err := btc.NewAddressPubKey(k.PubKey().SerializeCompressed(), &chaincfg.TestNet3Params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubkeys = append(pubkeys, addr)
+	}
+	redeemScript, err := txscript.MultiSigScript(pubkeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrowAddr, err := btc.NewAddressScriptHash(redeemScript, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrowScript, _ := txscript.PayToAddrScript(escrowAddr)
+	refundAddr := pubkeys[0].AddressPubKeyHash()
+
+	inputs := []Utxo{
+		{Txid: bytes.Repeat([]byte{0x01}, 32), Index: 0, Value: 50000, ScriptPubKey: escrowScript},
+		{Txid: bytes.Repeat([]byte{0x02}, 32), Index: 3, Value: 30000, ScriptPubKey: escrowScript},
+	}
+	tx, err := CreateRelease(inputs, []SpendOutput{{Address: refundAddr, Amount: 70000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !PaysTo(tx, refundAddr, 70000) || PaysTo(tx, refundAddr, 70001) {
+		t.Error("PaysTo returned the wrong result")
+	}
+
+	vendorSigs, err := SignMultisig(tx, redeemScript, vendor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyerSigs, err := SignMultisig(tx, redeemScript, buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Passed out of order, the signatures must be sorted by the key order in the script
+	err = CombineMultisig(tx, redeemScript, []EscrowSignatures{
+		{vendor.PubKey().SerializeCompressed(), vendorSigs},
+		{buyer.PubKey().SerializeCompressed(), buyerSigs},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range tx.TxIn {
+		vm, err := txscript.NewEngine(escrowScript, tx, i, txscript.StandardVerifyFlags, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Errorf("Input %d failed to verify: %s", i, err)
+		}
+	}
+}
+
+func TestCombineMultisigUnknownKey(t *testing.T) {
+	buyer, vendor, other := newEscrowKey(t, 1), newEscrowKey(t, 2), newEscrowKey(t, 4)
+	var pubkeys []*btc.AddressPubKey
+	for _, k := range []*btcec.PrivateKey{buyer, vendor} {
+		addr, _ := btc.NewAddressPubKey(k.PubKey().SerializeCompressed(), &chaincfg.TestNet3Params)
+		pubkeys = append(pubkeys, addr)
+	}
+	redeemScript, _ := txscript.MultiSigScript(pubkeys, 2)
+	tx, err := CreateRelease([]Utxo{{Txid: bytes.Repeat([]byte{0x01}, 32)}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs, _ := SignMultisig(tx, redeemScript, other)
+	err = CombineMultisig(tx, redeemScript, []EscrowSignatures{{other.PubKey().SerializeCompressed(), sigs}})
+	if err == nil {
+		t.Error("Combined signature from a key not in the redeem script")
+	}
+}
@@ -1,10 +1,12 @@
 package bitcoin
 
 import (
+	"fmt"
 	"time"
 	btc "github.com/btcsuite/btcutil"
 	b32 "github.com/tyler-smith/go-bip32"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
 )
 
 // TODO: Build out this interface
@@ -12,12 +14,29 @@ type BitcoinWallet interface {
 	// Keys
 	GetMasterPrivateKey() *b32.Key
 	GetMasterPublicKey() *b32.Key
-	GetCurrentAddress(purpose KeyPurpose) *btc.AddressPubKeyHash
-	GetFreshAddress(purpose KeyPurpose) *btc.AddressPubKeyHash
+	GetCurrentAddress(purpose KeyPurpose) btc.Address
+	GetFreshAddress(purpose KeyPurpose) btc.Address
+
+	// The extended public key of the account (m/0') all wallet keys are derived from.
+	// A watch-only wallet can be created from it.
+	GetAccountPublicKey() *b32.Key
+
+	// A watch-only wallet has no private keys for its coins so can't sign
+	WatchOnly() bool
 
 	// Wallet
 	GetBalance() (unconfirmed uint64, confirmed uint64)
 	Spend(amount int64, addr btc.Address, feeLevel FeeLevel) error
+	SpendWithOptions(opts SpendOptions) (*SpendResult, error)
+
+	// Offline signing. The unsigned transaction is built from the options as in a dry run,
+	// signed by a wallet holding the private keys and the result broadcast by this wallet.
+	CreateUnsignedTransaction(opts SpendOptions) (*UnsignedTransaction, error)
+	SignTransaction(utx *UnsignedTransaction) ([]byte, error)
+	BroadcastTransaction(tx []byte) (txid string, err error)
+
+	// Rebuild the wallet from the master key by scanning the blockchain
+	Rescan() error
 
 	// Params
 	Params() *chaincfg.Params
@@ -70,3 +89,77 @@ const (
 	NORMAL   = 1
 	ECONOMIC = 2
 )
+
+type SpendOutput struct {
+	Address btc.Address
+	Amount  int64
+}
+
+type SpendOptions struct {
+	// The recipients of the transaction
+	Outputs     []SpendOutput
+
+	// Coins to spend. If empty, coins are selected from the wallet automatically.
+	// If set, all of the given coins are spent.
+	Inputs      []wire.OutPoint
+
+	// Used to look up the fee rate if FeePerByte is zero
+	FeeLevel    FeeLevel
+
+	// Overrides the fee level with an explicit fee rate in satoshi per byte
+	FeePerByte  uint64
+
+	// Deduct the fee from the first output instead of paying it on top of the output amounts
+	SubtractFee bool
+
+	// Spend every input (or the entire wallet if no inputs are given) to the first and only
+	// output. The amount of the output is ignored and the fee is subtracted from it.
+	SendAll     bool
+
+	// Build the transaction but do not sign or broadcast it
+	DryRun      bool
+}
+
+type SpendResult struct {
+	Txid   string
+
+	// The serialized transaction. This is unsigned on a dry run.
+	Tx     []byte
+
+	Fee    int64
+	Change int64
+}
+
+type UnsignedInput struct {
+	Outpoint     wire.OutPoint
+	Value        int64
+	ScriptPubKey []byte
+
+	// The BIP32 path of the key which signs this input, e.g. m/0'/1/5
+	KeyPath      string
+}
+
+type UnsignedTransaction struct {
+	Tx     []byte
+	Inputs []UnsignedInput
+	Fee    int64
+	Change int64
+}
+
+// Format the BIP32 path of a wallet key. All wallet keys are in account 0.
+func KeyPath(purpose KeyPurpose, index uint32) string {
+	return fmt.Sprintf("m/0'/%d/%d", purpose, index)
+}
+
+// Parse a BIP32 path created by KeyPath
+func ParseKeyPath(path string) (KeyPurpose, uint32, error) {
+	var purpose KeyPurpose
+	var index uint32
+	if _, err := fmt.Sscanf(path, "m/0'/%d/%d", &purpose, &index); err != nil || KeyPath(purpose, index) != path {
+		return 0, 0, fmt.Errorf("Invalid key path %s", path)
+	}
+	if purpose != RECEIVING && purpose != CHANGE && purpose != REFUND {
+		return 0, 0, fmt.Errorf("Invalid key purpose in path %s", path)
+	}
+	return purpose, index, nil
+}
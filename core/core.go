@@ -55,6 +55,12 @@ type OpenBazaarNode struct {
 	// A service that periodically republishes active pointers
 	PointerRepublisher *net.PointerRepublisher
 
+	// A service that periodically pulls the feeds of the peers we follow
+	FeedAggregator *net.FeedAggregator
+
+	// A service that keeps the listing search index up to date
+	SearchIndexer *net.SearchIndexer
+
 	// TODO: Libsignal Client
 }
 
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// Sign the post with our identity key
+func (n *OpenBazaarNode) SignPost(post *pb.Post) (*pb.SignedPost, error) {
+	ser, err := proto.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	sp := &pb.SignedPost{
+		Post:       post,
+		GuidPubkey: pubkey,
+		Signature:  sig,
+	}
+	return sp, nil
+}
+
+// Sign the post and write it to the feed directory, add it to the feed index and our
+// own timeline then republish. Returns the IPFS hash of the post.
+func (n *OpenBazaarNode) Post(post *pb.Post) (string, error) {
+	if err := n.validatePost(post); err != nil {
+		return "", err
+	}
+	post.Timestamp = uint64(time.Now().Unix())
+	sp, err := n.SignPost(post)
+	if err != nil {
+		return "", err
+	}
+	ser, err := proto.Marshal(sp)
+	if err != nil {
+		return "", err
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: false,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(sp)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(ser)
+	postPath := path.Join(n.RepoPath, "root", "feed", hex.EncodeToString(h[:8])+".json")
+	if err := ioutil.WriteFile(postPath, []byte(out), 0644); err != nil {
+		return "", err
+	}
+	hash, err := ipfs.AddFile(n.Context, postPath)
+	if err != nil {
+		return "", err
+	}
+	if err := n.updateFeedIndex(net.FeedIndexEntry{Hash: hash, Timestamp: post.Timestamp}); err != nil {
+		return "", err
+	}
+	if err := n.Datastore.Feed().Put(hash, n.IpfsNode.Identity.Pretty(), ser, time.Unix(int64(post.Timestamp), 0)); err != nil {
+		return "", err
+	}
+	if err := n.SeedNode(); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// Append the post to the index.json file in the feed directory
+func (n *OpenBazaarNode) updateFeedIndex(entry net.FeedIndexEntry) error {
+	indexPath := path.Join(n.RepoPath, "root", "feed", "index.json")
+	var index []net.FeedIndexEntry
+	file, err := ioutil.ReadFile(indexPath)
+	if err == nil {
+		if err := json.Unmarshal(file, &index); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	index = append(index, entry)
+	return writeJSON(indexPath, index)
+}
+
+func (n *OpenBazaarNode) validatePost(post *pb.Post) error {
+	if post.Text == "" && len(post.Images) == 0 {
+		return errors.New("A post must have text or images")
+	}
+	for _, name := range post.Listings {
+		if _, err := os.Stat(path.Join(n.RepoPath, "root", "listings", name, "listing.json")); err != nil {
+			return errors.New("Linked listing " + name + " does not exist")
+		}
+	}
+	return nil
+}
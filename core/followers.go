@@ -0,0 +1,44 @@
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Write the lists of followers and followed peers into the root directory and republish
+// it so other peers can read them
+func (n *OpenBazaarNode) UpdateFollow() error {
+	followers, err := n.Datastore.Followers().Get(0, -1)
+	if err != nil {
+		return err
+	}
+	// Each follower is published with its signed proof so others can check the list
+	if followers == nil {
+		followers = []repo.Follower{}
+	}
+	if err := writeJSON(path.Join(n.RepoPath, "root", "followers"), followers); err != nil {
+		return err
+	}
+	following, err := n.Datastore.Following().Get(0, -1)
+	if err != nil {
+		return err
+	}
+	if following == nil {
+		following = []string{}
+	}
+	if err := writeJSON(path.Join(n.RepoPath, "root", "following"), following); err != nil {
+		return err
+	}
+	return n.SeedNode()
+}
+
+func writeJSON(fpath string, v interface{}) error {
+	j, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fpath, j, 0644)
+}
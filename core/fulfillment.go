@@ -0,0 +1,118 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/net/service"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"golang.org/x/net/context"
+)
+
+// Fulfill an order placed with us. The fulfillment is signed into the contract and sent
+// to the buyer.
+func (n *OpenBazaarNode) FulfillOrder(contract *pb.RicardianContract, fulfillment *pb.OrderFulfillment) error {
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil || contract.VendorListings[0].VendorID.Guid != n.IpfsNode.Identity.Pretty() {
+		return errors.New("The contract is not for one of our sales")
+	}
+	if contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil {
+		return errors.New("The contract has no order")
+	}
+	fulfillment.Timestamp = uint64(time.Now().Unix())
+	if err := service.SignFulfillment(n.IpfsNode.PrivateKey, contract, fulfillment); err != nil {
+		return err
+	}
+	// Catch anything the buyer would reject before sending
+	if err := service.VerifyFulfillment(contract); err != nil {
+		return err
+	}
+	p, err := peer.IDB58Decode(contract.BuyerOrder.BuyerID.Guid)
+	if err != nil {
+		return err
+	}
+	ser, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := pb.Message{
+		MessageType: pb.Message_ORDER_FULFILLMENT,
+		Payload:     &any.Any{Value: ser}}
+	err = n.Service.SendMessage(ctx, p, &m)
+	if err != nil { // Couldn't connect directly to peer. Likely offline.
+		if err := n.SendOfflineMessage(p, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save a verified fulfillment of an order we placed
+func (n *OpenBazaarNode) ProcessFulfillment(contract *pb.RicardianContract) error {
+	return n.savePurchase(contract, repo.ORDER_FULFILLED)
+}
+
+// Save the contract of an order we placed with its new state. The vendor sends each
+// section in its own copy of the contract so the signed sections of the saved copy are
+// carried over. A confirmation arriving late doesn't undo a later state and a refunded
+// order stays refunded when it's fulfilled.
+func (n *OpenBazaarNode) savePurchase(contract *pb.RicardianContract, state repo.OrderState) error {
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(ser)
+	orderHash := hex.EncodeToString(h[:])
+
+	saved, savedState, err := n.Datastore.Purchases().Get(orderHash)
+	if err == nil {
+		old := new(pb.RicardianContract)
+		if err := proto.Unmarshal(saved, old); err != nil {
+			return err
+		}
+		if contract.Refund == nil && old.Refund != nil {
+			contract.Refund = old.Refund
+			contract.Signatures = append(contract.Signatures, sectionSignatures(old, pb.Signatures_REFUND)...)
+		}
+		if contract.VendorOrderConfirmation == nil && old.VendorOrderConfirmation != nil {
+			contract.VendorOrderConfirmation = old.VendorOrderConfirmation
+			contract.Signatures = append(contract.Signatures, sectionSignatures(old, pb.Signatures_ORDER_CONFIRMATION)...)
+		}
+		if contract.VendorOrderFulfillment == nil && old.VendorOrderFulfillment != nil {
+			contract.VendorOrderFulfillment = old.VendorOrderFulfillment
+			contract.Signatures = append(contract.Signatures, sectionSignatures(old, pb.Signatures_ORDER_FULFILLMENT)...)
+		}
+		if state == repo.ORDER_CONFIRMED && savedState != repo.ORDER_PENDING {
+			state = savedState
+		}
+		if state == repo.ORDER_FULFILLED && (savedState == repo.ORDER_REFUNDED || savedState == repo.ORDER_PARTIALLY_REFUNDED) {
+			state = savedState
+		}
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return err
+	}
+
+	ser, err = proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	return n.Datastore.Purchases().Put(orderHash, ser, state, time.Now())
+}
+
+func sectionSignatures(contract *pb.RicardianContract, section pb.Signatures_Section) []*pb.Signatures {
+	var sigs []*pb.Signatures
+	for _, s := range contract.Signatures {
+		if s.Section == section {
+			sigs = append(sigs, s)
+		}
+	}
+	return sigs
+}
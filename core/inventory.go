@@ -0,0 +1,152 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/net/service"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"golang.org/x/net/context"
+)
+
+// A listing with this much stock or less across its variants is published as low on stock
+const lowStockThreshold = 5
+
+// The inventory key of an option combination, e.g. "Color=Red,Size=9". The options are
+// sorted by name so the key doesn't depend on the order they're given in.
+func InventoryVariant(options map[string]string) string {
+	var pairs []string
+	for name, value := range options {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Confirm an order placed with us. The ordered items are taken out of stock, failing with
+// repo.ErrInsufficientStock if there aren't enough, then the confirmation is signed into
+// the contract and sent to the buyer.
+func (n *OpenBazaarNode) ConfirmOrder(contract *pb.RicardianContract) error {
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil || contract.VendorListings[0].VendorID.Guid != n.IpfsNode.Identity.Pretty() {
+		return errors.New("The contract is not for one of our sales")
+	}
+	if contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil {
+		return errors.New("The contract has no order")
+	}
+	if contract.VendorOrderConfirmation != nil {
+		return errors.New("The order is already confirmed")
+	}
+	items, err := orderInventory(contract)
+	if err != nil {
+		return err
+	}
+	if err := n.Datastore.Inventory().Reserve(items); err != nil {
+		return err
+	}
+	confirmation := &pb.OrderConfirmation{Timestamp: uint64(time.Now().Unix())}
+	if err := service.SignOrderConfirmation(n.IpfsNode.PrivateKey, contract, confirmation); err != nil {
+		n.Datastore.Inventory().Restore(items)
+		return err
+	}
+
+	p, err := peer.IDB58Decode(contract.BuyerOrder.BuyerID.Guid)
+	if err != nil {
+		return err
+	}
+	ser, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := pb.Message{
+		MessageType: pb.Message_ORDER_CONFIRMATION,
+		Payload:     &any.Any{Value: ser}}
+	err = n.Service.SendMessage(ctx, p, &m)
+	if err != nil { // Couldn't connect directly to peer. Likely offline.
+		if err := n.SendOfflineMessage(p, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save a verified confirmation of an order we placed
+func (n *OpenBazaarNode) ProcessOrderConfirmation(contract *pb.RicardianContract) error {
+	return n.savePurchase(contract, repo.ORDER_CONFIRMED)
+}
+
+// Put the items of a confirmed order back in stock, e.g. when it's refunded in full.
+// Nothing was taken out of stock for an unconfirmed order.
+func (n *OpenBazaarNode) RestoreInventory(contract *pb.RicardianContract) error {
+	if contract.VendorOrderConfirmation == nil {
+		return nil
+	}
+	items, err := orderInventory(contract)
+	if err != nil {
+		return err
+	}
+	return n.Datastore.Inventory().Restore(items)
+}
+
+// The inventory variant and quantity of each item in an order. The items reference the
+// listings in the contract by hash.
+func orderInventory(contract *pb.RicardianContract) ([]repo.InventoryItem, error) {
+	listingNames := make(map[string]string)
+	for _, listing := range contract.VendorListings {
+		ser, err := proto.Marshal(listing)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.Sum256(ser)
+		listingNames[string(h[:])] = listing.ListingName
+	}
+	var items []repo.InventoryItem
+	for _, item := range contract.BuyerOrder.Items {
+		name, ok := listingNames[string(item.ListingHash)]
+		if !ok {
+			return nil, errors.New("The order is for a listing not in the contract")
+		}
+		options := make(map[string]string)
+		for _, o := range item.Options {
+			options[o.Name] = o.Value
+		}
+		items = append(items, repo.InventoryItem{
+			ListingName: name,
+			Variant:     InventoryVariant(options),
+			Quantity:    int(item.Quantity),
+		})
+	}
+	return items, nil
+}
+
+// The coarse stock level published in a listing
+func (n *OpenBazaarNode) stockLevel(listingName string) (pb.Listing_Item_StockLevel, error) {
+	stock, err := n.Datastore.Inventory().Get(listingName)
+	if err != nil {
+		return pb.Listing_Item_UNTRACKED, err
+	}
+	if len(stock) == 0 {
+		return pb.Listing_Item_UNTRACKED, nil
+	}
+	var total int
+	for _, count := range stock {
+		total += count
+	}
+	switch {
+	case total <= 0:
+		return pb.Listing_Item_OUT_OF_STOCK, nil
+	case total <= lowStockThreshold:
+		return pb.Listing_Item_LOW_STOCK, nil
+	default:
+		return pb.Listing_Item_IN_STOCK, nil
+	}
+}
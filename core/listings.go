@@ -31,6 +31,13 @@ func (n *OpenBazaarNode) SignListing(listing *pb.Listing) (*pb.RicardianContract
 	p.Bitcoin = n.Wallet.GetMasterPublicKey().Key
 	id.Pubkeys = p
 	listing.VendorID = id
+	if listing.Item != nil {
+		stock, err := n.stockLevel(listing.ListingName)
+		if err != nil {
+			return c, err
+		}
+		listing.Item.Stock = stock
+	}
 	s := new(pb.Signatures)
 	s.Section = pb.Signatures_LISTING
 	serializedListing, err := proto.Marshal(listing)
@@ -65,6 +72,7 @@ func (n *OpenBazaarNode) UpdateListingIndex(contract *pb.RicardianContract) erro
 
 	// read existing file
 	file, _ := ioutil.ReadFile(indexPath)
+	var oldHash string
 	listingHash, err := ipfs.AddFile(n.Context, listingPath)
 	if err != nil {
 		return err
@@ -82,6 +90,7 @@ func (n *OpenBazaarNode) UpdateListingIndex(contract *pb.RicardianContract) erro
 		if d.Name != ld.Name {
 			continue
 		}
+		oldHash = d.Hash
 
 		if len(index) == 1 {
 			index = []listingData{}
@@ -112,6 +121,18 @@ func (n *OpenBazaarNode) UpdateListingIndex(contract *pb.RicardianContract) erro
 	if werr != nil {
 		return werr
 	}
+
+	// Advertise the new version of the listing under its tags. Publishing pointers
+	// takes a while so it's done in the background.
+	var tags []string
+	if item := contract.VendorListings[0].Item; item != nil {
+		tags = item.Tags
+	}
+	go func() {
+		if err := n.PublishTags(tags, listingHash, oldHash); err != nil {
+			log.Errorf("Failed to publish listing tags: %s", err)
+		}
+	}()
 	return nil
 }
 
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"path"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// All moderators advertise themselves under the same pointer key
+const moderatorTopic = "moderators"
+
+// A moderator found on the network
+type ModeratorResult struct {
+	PeerId    string
+	Moderator *pb.Moderator
+}
+
+// Sign the moderator profile, write it to the root directory and advertise it with a
+// MODERATOR pointer. Pointers to any previous version of the profile are dropped.
+func (n *OpenBazaarNode) SetModerator(mod *pb.Moderator) error {
+	if err := validateModerator(mod); err != nil {
+		return err
+	}
+	mod.BitcoinPubkey = n.Wallet.GetMasterPublicKey().Key
+	ser, err := proto.Marshal(mod)
+	if err != nil {
+		return err
+	}
+	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
+	if err != nil {
+		return err
+	}
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return err
+	}
+	sm := &pb.SignedModerator{
+		Moderator:  mod,
+		GuidPubkey: pubkey,
+		Signature:  sig,
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: false,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(sm)
+	if err != nil {
+		return err
+	}
+	modPath := path.Join(n.RepoPath, "root", "moderator")
+	if err := ioutil.WriteFile(modPath, []byte(out), 0644); err != nil {
+		return err
+	}
+	hash, err := ipfs.AddFile(n.Context, modPath)
+	if err != nil {
+		return err
+	}
+	if err := n.deleteTopicPointers(ipfs.MODERATOR, ""); err != nil {
+		return err
+	}
+	if err := n.publishTopicPointer(ipfs.MODERATOR, moderatorTopic, hash); err != nil {
+		return err
+	}
+	return n.SeedNode()
+}
+
+// Find the moderators advertised on the network. Only profiles with a valid
+// signature are returned, one per peer.
+func (n *OpenBazaarNode) GetModerators() []ModeratorResult {
+	var results []ModeratorResult
+	seen := make(map[string]bool)
+	var lock sync.Mutex
+	n.walkTopic(ipfs.MODERATOR, moderatorTopic, func(hash string, b []byte) {
+		sm := new(pb.SignedModerator)
+		if err := jsonpb.UnmarshalString(string(b), sm); err != nil {
+			log.Debugf("Failed to parse moderator %s: %s", hash, err)
+			return
+		}
+		peerId, err := net.VerifyModerator(sm)
+		if err != nil {
+			log.Debugf("Dropping moderator %s: %s", hash, err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		// Peers may still have pointers to older versions of a profile
+		if seen[peerId] {
+			return
+		}
+		seen[peerId] = true
+		results = append(results, ModeratorResult{peerId, sm.Moderator})
+	})
+	return results
+}
+
+func validateModerator(mod *pb.Moderator) error {
+	if mod.Description == "" {
+		return errors.New("A moderator description is required")
+	}
+	if mod.Fee == nil {
+		return errors.New("A moderator fee is required")
+	}
+	if mod.Fee.Percentage < 0 || mod.Fee.Percentage > 100 {
+		return errors.New("The fee percentage must be between 0 and 100")
+	}
+	return nil
+}
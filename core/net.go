@@ -1,10 +1,13 @@
 package core
 
 import (
+	"time"
+
 	multihash "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
 	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
 
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net/service"
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/any"
@@ -91,15 +94,23 @@ func (n *OpenBazaarNode) Follow(peerId string) error {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	m := pb.Message{MessageType: pb.Message_FOLLOW}
+	proof, err := service.SignFollowCommand(n.IpfsNode.PrivateKey, pb.Message_FOLLOW, peerId, time.Now())
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_FOLLOW,
+		Payload:     &any.Any{Value: proof}}
 	err = n.Service.SendMessage(ctx, p, &m)
 	if err != nil { // Couldn't connect directly to peer. Likely offline.
 		if err := n.SendOfflineMessage(p, &m); err != nil {
 			return err
 		}
 	}
-	n.Datastore.Following().Put(peerId)
-	return nil
+	if err := n.Datastore.Following().Put(peerId); err != nil {
+		return err
+	}
+	return n.UpdateFollow()
 }
 
 func (n *OpenBazaarNode) Unfollow(peerId string) error {
@@ -109,13 +120,21 @@ func (n *OpenBazaarNode) Unfollow(peerId string) error {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	m := pb.Message{MessageType: pb.Message_UNFOLLOW}
+	proof, err := service.SignFollowCommand(n.IpfsNode.PrivateKey, pb.Message_UNFOLLOW, peerId, time.Now())
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_UNFOLLOW,
+		Payload:     &any.Any{Value: proof}}
 	err = n.Service.SendMessage(ctx, p, &m)
 	if err != nil {
 		if err := n.SendOfflineMessage(p, &m); err != nil {
 			return err
 		}
 	}
-	n.Datastore.Following().Delete(peerId)
-	return nil
+	if err := n.Datastore.Following().Delete(peerId); err != nil {
+		return err
+	}
+	return n.UpdateFollow()
 }
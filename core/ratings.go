@@ -0,0 +1,153 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net/service"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"golang.org/x/net/context"
+)
+
+// An entry in the ratings/index.json file of a node's root directory
+type RatingIndexEntry struct {
+	Hash      string
+	OrderHash string // hex encoded
+	Timestamp uint64
+}
+
+// Rate an order we placed. The rating is added to the order's contract, signed and
+// sent to the vendor who publishes it in their store.
+func (n *OpenBazaarNode) RateOrder(contract *pb.RicardianContract, rating *pb.Rating) error {
+	if contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil || contract.BuyerOrder.BuyerID.Guid != n.IpfsNode.Identity.Pretty() {
+		return errors.New("The contract is not for one of our orders")
+	}
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil {
+		return errors.New("The contract has no vendor")
+	}
+	vendorId := contract.VendorListings[0].VendorID.Guid
+	rating.Timestamp = uint64(time.Now().Unix())
+	if err := service.SignRating(n.IpfsNode.PrivateKey, contract, rating); err != nil {
+		return err
+	}
+	// Catch anything the vendor would reject before sending
+	if err := service.VerifyRating(contract, vendorId); err != nil {
+		return err
+	}
+	p, err := peer.IDB58Decode(vendorId)
+	if err != nil {
+		return err
+	}
+	ser, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := pb.Message{
+		MessageType: pb.Message_RATING,
+		Payload:     &any.Any{Value: ser}}
+	err = n.Service.SendMessage(ctx, p, &m)
+	if err != nil { // Couldn't connect directly to peer. Likely offline.
+		if err := n.SendOfflineMessage(p, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write a verified rating of one of our orders to the ratings directory, add it to
+// the ratings index and republish. A newer rating of the same order replaces the old one.
+func (n *OpenBazaarNode) PublishRating(contract *pb.RicardianContract) error {
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: false,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(contract)
+	if err != nil {
+		return err
+	}
+	orderHash := hex.EncodeToString(contract.BuyerRating.OrderHash)
+	ratingPath := path.Join(n.RepoPath, "root", "ratings", orderHash+".json")
+	if err := ioutil.WriteFile(ratingPath, []byte(out), 0644); err != nil {
+		return err
+	}
+	hash, err := ipfs.AddFile(n.Context, ratingPath)
+	if err != nil {
+		return err
+	}
+
+	indexPath := path.Join(n.RepoPath, "root", "ratings", "index.json")
+	var index []RatingIndexEntry
+	file, err := ioutil.ReadFile(indexPath)
+	if err == nil {
+		if err := json.Unmarshal(file, &index); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	for i, entry := range index {
+		if entry.OrderHash == orderHash {
+			index = append(index[:i], index[i+1:]...)
+			break
+		}
+	}
+	index = append(index, RatingIndexEntry{hash, orderHash, contract.BuyerRating.Timestamp})
+	if err := writeJSON(indexPath, index); err != nil {
+		return err
+	}
+	return n.SeedNode()
+}
+
+// Fetch the ratings published in the peer's store. Ratings which fail verification
+// against the buyer's signatures and the order are dropped.
+func (n *OpenBazaarNode) GetRatings(peerId string) ([]*pb.RicardianContract, error) {
+	b, err := ipfs.Cat(n.Context, path.Join("/ipns", peerId, "ratings", "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	var index []RatingIndexEntry
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, err
+	}
+	var ratings []*pb.RicardianContract
+	seen := make(map[string]bool)
+	for _, entry := range index {
+		b, err := ipfs.Cat(n.Context, entry.Hash)
+		if err != nil {
+			log.Debugf("Failed to fetch rating %s: %s", entry.Hash, err)
+			continue
+		}
+		contract := new(pb.RicardianContract)
+		if err := jsonpb.UnmarshalString(string(b), contract); err != nil {
+			log.Debugf("Failed to parse rating %s: %s", entry.Hash, err)
+			continue
+		}
+		if err := service.VerifyRating(contract, peerId); err != nil {
+			log.Debugf("Dropping rating %s: %s", entry.Hash, err)
+			continue
+		}
+		// One rating per order, whatever the index says
+		orderHash := string(contract.BuyerRating.OrderHash)
+		if seen[orderHash] {
+			continue
+		}
+		seen[orderHash] = true
+		ratings = append(ratings, contract)
+	}
+	return ratings, nil
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/net/service"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	b32 "github.com/tyler-smith/go-bip32"
+	"golang.org/x/net/context"
+)
+
+// The fee rate of an escrow release when none is given, in satoshi per byte
+const defaultReleaseFeePerByte = 40
+
+// Refund all or part of an order placed with us. A direct payment is refunded from our
+// wallet to the buyer's refund address. For a moderated payment we sign a transaction
+// releasing the escrow coins to the buyer which the buyer countersigns and broadcasts.
+// The refund is signed into the contract and sent to the buyer. A full refund puts the
+// items back in stock.
+func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, amount uint64, memo string, escrowCoins []bitcoin.Utxo, feePerByte uint64) error {
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil || contract.VendorListings[0].VendorID.Guid != n.IpfsNode.Identity.Pretty() {
+		return errors.New("The contract is not for one of our sales")
+	}
+	order := contract.BuyerOrder
+	if order == nil || order.BuyerID == nil || order.Payment == nil {
+		return errors.New("The contract has no order")
+	}
+	if amount == 0 || amount > uint64(order.Payment.Amount) {
+		return errors.New("The refund must be more than zero and no more than the payment")
+	}
+	refundAddr, err := btc.DecodeAddress(order.RefundAddress, n.Wallet.Params())
+	if err != nil {
+		return err
+	}
+	refund := &pb.Refund{
+		Amount:    amount,
+		Memo:      memo,
+		Timestamp: uint64(time.Now().Unix()),
+	}
+	switch order.Payment.Method {
+	case pb.Order_Payment_DIRECT:
+		res, err := n.Wallet.SpendWithOptions(bitcoin.SpendOptions{
+			Outputs:    []bitcoin.SpendOutput{{Address: refundAddr, Amount: int64(amount)}},
+			FeeLevel:   bitcoin.NORMAL,
+			FeePerByte: feePerByte,
+		})
+		if err != nil {
+			return err
+		}
+		refund.Txid = res.Txid
+	case pb.Order_Payment_MODERATED:
+		if err := n.signRelease(order, refund, refundAddr, escrowCoins, feePerByte); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Unknown payment method")
+	}
+	if err := service.SignRefund(n.IpfsNode.PrivateKey, contract, refund); err != nil {
+		return err
+	}
+
+	p, err := peer.IDB58Decode(order.BuyerID.Guid)
+	if err != nil {
+		return err
+	}
+	ser, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := pb.Message{
+		MessageType: pb.Message_REFUND,
+		Payload:     &any.Any{Value: ser}}
+	err = n.Service.SendMessage(ctx, p, &m)
+	if err != nil { // Couldn't connect directly to peer. Likely offline.
+		if err := n.SendOfflineMessage(p, &m); err != nil {
+			return err
+		}
+	}
+	if amount == uint64(order.Payment.Amount) {
+		if err := n.RestoreInventory(contract); err != nil {
+			log.Errorf("Failed to restore the inventory of a refunded order: %s", err)
+		}
+	}
+	return nil
+}
+
+// Build and sign our half of the transaction releasing the escrow. The buyer gets the
+// refund and what's left after the fee comes back to us.
+func (n *OpenBazaarNode) signRelease(order *pb.Order, refund *pb.Refund, refundAddr btc.Address, escrowCoins []bitcoin.Utxo, feePerByte uint64) error {
+	if len(escrowCoins) == 0 {
+		return errors.New("A moderated refund needs the escrow coins")
+	}
+	redeemScript, chaincode, err := escrowParams(order.Payment)
+	if err != nil {
+		return err
+	}
+	escrowAddr, err := btc.NewAddressScriptHash(redeemScript, n.Wallet.Params())
+	if err != nil {
+		return err
+	}
+	if escrowAddr.EncodeAddress() != order.Payment.Address {
+		return errors.New("The redeem script does not match the payment address")
+	}
+	if feePerByte == 0 {
+		feePerByte = defaultReleaseFeePerByte
+	}
+	var total int64
+	for _, c := range escrowCoins {
+		total += int64(c.Value)
+	}
+	// Version, locktime and counts, the inputs and two P2PKH outputs
+	size := 10 + len(escrowCoins)*bitcoin.RedeemMultisigInputSize + 2*34
+	fee := int64(size) * int64(feePerByte)
+	change := total - int64(refund.Amount) - fee
+	if change < 0 {
+		return errors.New("The escrow does not cover the refund and fee")
+	}
+	outputs := []bitcoin.SpendOutput{{Address: refundAddr, Amount: int64(refund.Amount)}}
+	if !txrules.IsDustAmount(btc.Amount(change), 25, txrules.DefaultRelayFeePerKb) {
+		outputs = append(outputs, bitcoin.SpendOutput{Address: n.Wallet.GetFreshAddress(bitcoin.RECEIVING), Amount: change})
+	}
+	tx, err := bitcoin.CreateRelease(escrowCoins, outputs)
+	if err != nil {
+		return err
+	}
+	key, err := bitcoin.EscrowKey(n.Wallet.GetMasterPrivateKey(), chaincode)
+	if err != nil {
+		return err
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), key.Key)
+	sigs, err := bitcoin.SignMultisig(tx, redeemScript, priv)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return err
+	}
+	refund.ReleaseTx = buf.Bytes()
+	refund.ReleaseSignatures = sigs
+	return nil
+}
+
+// Apply a verified refund of an order we placed. For a moderated payment the release
+// transaction is countersigned and broadcast. The order is saved with its new state.
+func (n *OpenBazaarNode) ProcessRefund(contract *pb.RicardianContract) error {
+	order, refund := contract.BuyerOrder, contract.Refund
+	if order.Payment != nil && order.Payment.Method == pb.Order_Payment_MODERATED {
+		if err := n.releaseEscrow(contract); err != nil {
+			return err
+		}
+	}
+	state := repo.ORDER_REFUNDED
+	if order.Payment != nil && refund.Amount < uint64(order.Payment.Amount) {
+		state = repo.ORDER_PARTIALLY_REFUNDED
+	}
+	return n.savePurchase(contract, state)
+}
+
+// Countersign the vendor's release of the escrow and broadcast it
+func (n *OpenBazaarNode) releaseEscrow(contract *pb.RicardianContract) error {
+	order, refund := contract.BuyerOrder, contract.Refund
+	if n.Wallet.WatchOnly() {
+		return errors.New("A watch-only wallet can't sign the escrow release")
+	}
+	redeemScript, chaincode, err := escrowParams(order.Payment)
+	if err != nil {
+		return err
+	}
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(refund.ReleaseTx)); err != nil {
+		return err
+	}
+	refundAddr, err := btc.DecodeAddress(order.RefundAddress, n.Wallet.Params())
+	if err != nil {
+		return err
+	}
+	if !bitcoin.PaysTo(tx, refundAddr, int64(refund.Amount)) {
+		return errors.New("The release does not pay the refund to our refund address")
+	}
+	ourKey, err := bitcoin.EscrowKey(n.Wallet.GetMasterPrivateKey(), chaincode)
+	if err != nil {
+		return err
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), ourKey.Key)
+	ourSigs, err := bitcoin.SignMultisig(tx, redeemScript, priv)
+	if err != nil {
+		return err
+	}
+	vendorID := contract.VendorListings[0].VendorID
+	if vendorID.Pubkeys == nil {
+		return errors.New("The vendor has no bitcoin key")
+	}
+	vendorKey, err := bitcoin.EscrowKey(&b32.Key{Key: vendorID.Pubkeys.Bitcoin}, chaincode)
+	if err != nil {
+		return err
+	}
+	err = bitcoin.CombineMultisig(tx, redeemScript, []bitcoin.EscrowSignatures{
+		{Pubkey: ourKey.PublicKey().Key, Signatures: ourSigs},
+		{Pubkey: vendorKey.Key, Signatures: refund.ReleaseSignatures},
+	})
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return err
+	}
+	_, err = n.Wallet.BroadcastTransaction(buf.Bytes())
+	return err
+}
+
+// The hex encoded redeem script and chain code of a moderated payment
+func escrowParams(payment *pb.Order_Payment) (redeemScript []byte, chaincode []byte, err error) {
+	redeemScript, err = hex.DecodeString(payment.RedeemScript)
+	if err != nil {
+		return nil, nil, err
+	}
+	chaincode, err = hex.DecodeString(payment.Chaincode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(redeemScript) == 0 || len(chaincode) != 32 {
+		return nil, nil, errors.New("The payment has no escrow")
+	}
+	return redeemScript, chaincode, nil
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/ipfs/go-ipfs/routing/dht"
+	"golang.org/x/net/context"
+)
+
+// How long to wait for pointers when searching a tag or channel
+const topicSearchTimeout = time.Minute
+
+// A listing found under a tag
+type TagResult struct {
+	Hash     string
+	Contract *pb.RicardianContract
+}
+
+// A post found in a channel
+type ChannelResult struct {
+	Hash   string
+	PeerId string
+	Post   *pb.SignedPost
+}
+
+// Publish pointers to the listing under each of its tags and stop republishing the
+// pointers to the previous version of the listing
+func (n *OpenBazaarNode) PublishTags(tags []string, hash string, oldHash string) error {
+	if oldHash != "" && oldHash != hash {
+		if err := n.deleteTopicPointers(ipfs.TAG, oldHash); err != nil {
+			return err
+		}
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		if err := n.publishTopicPointer(ipfs.TAG, tag, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find the listings advertised under the tag. Only listings with a valid vendor
+// signature which have the tag are returned.
+func (n *OpenBazaarNode) SearchTag(tag string) []TagResult {
+	var results []TagResult
+	var lock sync.Mutex
+	n.walkTopic(ipfs.TAG, tag, func(hash string, b []byte) {
+		contract := new(pb.RicardianContract)
+		if err := jsonpb.UnmarshalString(string(b), contract); err != nil {
+			log.Debugf("Failed to parse listing %s: %s", hash, err)
+			return
+		}
+		if err := net.VerifyListing(contract); err != nil {
+			log.Debugf("Dropping listing %s: %s", hash, err)
+			return
+		}
+		if !hasTag(contract.VendorListings[0], tag) {
+			return
+		}
+		if n.SearchIndexer != nil {
+			if err := n.SearchIndexer.IndexListing(hash, contract); err != nil {
+				log.Error(err)
+			}
+		}
+		lock.Lock()
+		results = append(results, TagResult{hash, contract})
+		lock.Unlock()
+	})
+	return results
+}
+
+// Add a post to the named channel. The post is recorded in the channel's index in
+// the root directory and a pointer to it is published under the channel name.
+func (n *OpenBazaarNode) AddToChannel(channel string, hash string) error {
+	if err := validateChannel(channel); err != nil {
+		return err
+	}
+	b, err := ipfs.Cat(n.Context, hash)
+	if err != nil {
+		return err
+	}
+	sp := new(pb.SignedPost)
+	if err := jsonpb.UnmarshalString(string(b), sp); err != nil {
+		return err
+	}
+	author, err := net.PostAuthor(sp)
+	if err != nil {
+		return err
+	}
+	if err := net.VerifyPost(sp, author); err != nil {
+		return err
+	}
+
+	indexPath := path.Join(n.RepoPath, "root", "channel", channel+".json")
+	var index []string
+	file, err := ioutil.ReadFile(indexPath)
+	if err == nil {
+		if err := json.Unmarshal(file, &index); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	for _, h := range index {
+		if h == hash {
+			return errors.New("The post is already in the channel")
+		}
+	}
+	index = append(index, hash)
+	if err := writeJSON(indexPath, index); err != nil {
+		return err
+	}
+	if err := n.publishTopicPointer(ipfs.CHANNEL, channel, hash); err != nil {
+		return err
+	}
+	return n.SeedNode()
+}
+
+// Find the posts added to the named channel by any peer
+func (n *OpenBazaarNode) GetChannel(channel string) ([]ChannelResult, error) {
+	if err := validateChannel(channel); err != nil {
+		return nil, err
+	}
+	var results []ChannelResult
+	var lock sync.Mutex
+	n.walkTopic(ipfs.CHANNEL, channel, func(hash string, b []byte) {
+		sp := new(pb.SignedPost)
+		if err := jsonpb.UnmarshalString(string(b), sp); err != nil {
+			log.Debugf("Failed to parse post %s: %s", hash, err)
+			return
+		}
+		author, err := net.PostAuthor(sp)
+		if err != nil {
+			return
+		}
+		if err := net.VerifyPost(sp, author); err != nil {
+			log.Debugf("Dropping post %s: %s", hash, err)
+			return
+		}
+		lock.Lock()
+		results = append(results, ChannelResult{hash, author, sp})
+		lock.Unlock()
+	})
+	// Pick up the stores of the authors in the search index
+	if n.SearchIndexer != nil {
+		authors := make(map[string]bool)
+		for _, r := range results {
+			if len(r.Post.Post.Listings) > 0 {
+				authors[r.PeerId] = true
+			}
+		}
+		for peerId := range authors {
+			go func(peerId string) {
+				if err := n.SearchIndexer.IndexPeer(peerId); err != nil {
+					log.Debugf("Failed to index listings of %s: %s", peerId, err)
+				}
+			}(peerId)
+		}
+	}
+	return results, nil
+}
+
+// Publish a pointer to the IPFS hash under the topic and save it so it's republished
+func (n *OpenBazaarNode) publishTopicPointer(purpose ipfs.Purpose, topic string, hash string) error {
+	addr, err := ma.NewMultiaddr("/ipfs/" + hash)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pointer, err := ipfs.PublishPointer(n.IpfsNode, ctx, ipfs.TopicKey(purpose, topic), ipfs.TopicPrefixLen, addr)
+	if err != nil {
+		return err
+	}
+	pointer.Purpose = purpose
+	return n.Datastore.Pointers().Put(pointer)
+}
+
+// Stop republishing our pointers of the given purpose to the IPFS hash, or all our
+// pointers of that purpose if the hash is empty
+func (n *OpenBazaarNode) deleteTopicPointers(purpose ipfs.Purpose, hash string) error {
+	pointers, err := n.Datastore.Pointers().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, p := range pointers {
+		if p.Purpose != purpose {
+			continue
+		}
+		if hash == "" || (len(p.Value.Addrs) > 0 && p.Value.Addrs[0].String() == "/ipfs/"+hash) {
+			if err := n.Datastore.Pointers().Delete(p.Value.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Fetch the file behind each pointer published under the topic and pass it to fn.
+// Files are fetched concurrently and each hash is only fetched once.
+func (n *OpenBazaarNode) walkTopic(purpose ipfs.Purpose, topic string, fn func(hash string, b []byte)) {
+	ctx, cancel := context.WithTimeout(context.Background(), topicSearchTimeout)
+	defer cancel()
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for p := range ipfs.FindPointersAsync(n.IpfsNode.Routing.(*dht.IpfsDHT), ctx, ipfs.TopicKey(purpose, topic), ipfs.TopicPrefixLen) {
+		if len(p.Addrs) == 0 {
+			continue
+		}
+		hash, err := p.Addrs[0].ValueForProtocol(ma.P_IPFS)
+		if err != nil || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		wg.Add(1)
+		go func(hash string) {
+			defer wg.Done()
+			b, err := ipfs.Cat(n.Context, hash)
+			if err != nil {
+				log.Debugf("Failed to fetch %s: %s", hash, err)
+				return
+			}
+			fn(hash, b)
+		}(hash)
+	}
+	wg.Wait()
+}
+
+func hasTag(listing *pb.Listing, tag string) bool {
+	if listing.Item == nil {
+		return false
+	}
+	for _, t := range listing.Item.Tags {
+		if strings.EqualFold(strings.TrimSpace(t), strings.TrimSpace(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Channel names are used as file names in the root directory
+func validateChannel(channel string) error {
+	if channel == "" || strings.ContainsAny(channel, `/\`) || strings.HasPrefix(channel, ".") {
+		return errors.New("Invalid channel name")
+	}
+	return nil
+}
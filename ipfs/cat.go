@@ -2,11 +2,12 @@ package ipfs
 
 import (
 	"io"
+	"io/ioutil"
 
 	"github.com/ipfs/go-ipfs/commands"
 )
 
-// Fetch data from IPFS given the hash
+// Fetch data from IPFS given the hash or an /ipfs or /ipns path
 func Cat(ctx commands.Context, hash string) ([]byte, error) {
 	args := []string{"cat", hash}
 	req, cmd, err := NewRequest(ctx, args)
@@ -21,10 +22,5 @@ func Cat(ctx commands.Context, hash string) ([]byte, error) {
 	}
 	resp := res.Output()
 	reader := resp.(io.Reader)
-	b := make([]byte, res.Length())
-	_, err = reader.Read(b)
-	if err != nil {
-		return nil, err
-	}
-	return b, nil
+	return ioutil.ReadAll(reader)
 }
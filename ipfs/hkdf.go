@@ -0,0 +1,59 @@
+package ipfs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"hash"
+)
+
+// The most output HKDF-SHA256 can produce (RFC 5869 section 2.3)
+const hkdfMaxOutput = 255 * sha256.Size
+
+var errHKDFExhausted = errors.New("hkdf: output limit reached")
+
+// An io.Reader returning the output of HKDF-SHA256 (RFC 5869) for a secret, salt and info
+type HKDFReader struct {
+	expander hash.Hash
+	info     []byte
+	counter  byte
+	prev     []byte
+	buf      []byte
+	read     int
+}
+
+func NewHKDFReader(secret, salt, info []byte) *HKDFReader {
+	if salt == nil {
+		salt = make([]byte, sha256.Size)
+	}
+	extractor := hmac.New(sha256.New, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+	return &HKDFReader{
+		expander: hmac.New(sha256.New, prk),
+		info:     info,
+	}
+}
+
+func (r *HKDFReader) Read(p []byte) (int, error) {
+	if r.read+len(p) > hkdfMaxOutput {
+		return 0, errHKDFExhausted
+	}
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			r.counter++
+			r.expander.Reset()
+			r.expander.Write(r.prev)
+			r.expander.Write(r.info)
+			r.expander.Write([]byte{r.counter})
+			r.prev = r.expander.Sum(nil)
+			r.buf = r.prev
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	r.read += n
+	return n, nil
+}
@@ -1,9 +1,13 @@
 package ipfs
 
 import (
+	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
+	"math/big"
 	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
 	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
 
@@ -43,12 +47,92 @@ func IdentityKeyFromSeed(seed []byte, bits int) ([]byte, error) {
 	return encodedKey, nil
 }
 
+const identityKeyInfo = "OpenBazaar RSA identity key"
+
+// The RSA public exponent of identity keys
+const identityKeyExponent = 65537
+
+// Derive an RSA identity key from the seed. Unlike IdentityKeyFromSeed this does not use
+// crypto/rsa to generate the key, whose use of the random source differs between Go
+// releases. The starting point of each prime is read from HKDF-SHA256 of the seed and
+// the prime is the first one at or above it so the same seed always gives the same key.
+func DeterministicIdentityKey(seed []byte, bits int) ([]byte, error) {
+	if bits < 512 || bits%16 != 0 {
+		return nil, errors.New("Key size must be a multiple of 16 bits and at least 512")
+	}
+	reader := NewHKDFReader(seed, nil, []byte(identityKeyInfo))
+	p, err := nextPrime(reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := nextPrime(reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	if p.Cmp(q) == 0 {
+		return nil, errors.New("Derived primes are equal")
+	}
+
+	one := big.NewInt(1)
+	n := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+	d := new(big.Int).ModInverse(big.NewInt(identityKeyExponent), phi)
+	if d == nil || n.BitLen() != bits {
+		return nil, errors.New("Failed to derive identity key")
+	}
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: n, E: identityKeyExponent},
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	if err := priv.Validate(); err != nil {
+		return nil, err
+	}
+	priv.Precompute()
+	sk, err := libp2p.UnmarshalRsaPrivateKey(x509.MarshalPKCS1PrivateKey(priv))
+	if err != nil {
+		return nil, err
+	}
+	return libp2p.MarshalPrivateKey(sk)
+}
+
+// Read a starting point of the given size with its top two bits set, so the product of
+// two such primes has exactly twice the bits, and search upwards for a prime p with
+// p-1 coprime to the public exponent
+func nextPrime(reader *HKDFReader, bits int) (*big.Int, error) {
+	b := make([]byte, bits/8)
+	if _, err := reader.Read(b); err != nil {
+		return nil, err
+	}
+	b[0] |= 0xc0
+	b[len(b)-1] |= 1
+	p := new(big.Int).SetBytes(b)
+	e := big.NewInt(identityKeyExponent)
+	two := big.NewInt(2)
+	pMinus1 := new(big.Int)
+	gcd := new(big.Int)
+	for {
+		if p.ProbablyPrime(20) {
+			pMinus1.Sub(p, big.NewInt(1))
+			if gcd.GCD(nil, nil, e, pMinus1).Cmp(big.NewInt(1)) == 0 {
+				break
+			}
+		}
+		p.Add(p, two)
+	}
+	if p.BitLen() != bits {
+		return nil, errors.New("Prime search overflowed")
+	}
+	return p, nil
+}
+
 type DeterministicReader struct {
 	Seed    []byte
 	Counter int
 }
 
-// TODO: this is a place holder until we settle on a key expansion algorithm
+// Used by IdentityKeyFromSeed for the identity keys of nodes created before
+// DeterministicIdentityKey. New keys should not use it.
 func (d *DeterministicReader) Read(p []byte) (n int, err error) {
 	l := len(p)
 	deterministcBytes := []byte{}
@@ -0,0 +1,61 @@
+package ipfs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// RFC 5869 test case 1
+func TestHKDFReader(t *testing.T) {
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	okm, _ := hex.DecodeString("3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+	r := NewHKDFReader(ikm, salt, info)
+	out := make([]byte, len(okm))
+	// Read in uneven pieces to cross block boundaries
+	r.Read(out[:5])
+	r.Read(out[5:40])
+	r.Read(out[40:])
+	if !bytes.Equal(out, okm) {
+		t.Errorf("Unexpected output %x", out)
+	}
+	if _, err := r.Read(make([]byte, hkdfMaxOutput)); err != errHKDFExhausted {
+		t.Error("Expected output limit to be enforced")
+	}
+}
+
+// These must never change. A node restored from its mnemonic gets its peer ID from here.
+var identityKeyVectors = []struct {
+	bits   int
+	peerID string
+}{
+	{1024, "QmRocNe5Y26uvKv9t1LvbbtrKkuEYgm4Cd94Dtz8vcae2u"},
+	{4096, "QmWjAjyFrcrJseoZ1prnaM5gwPbftLfV1WkNVztQhmMsQW"},
+}
+
+func TestDeterministicIdentityKey(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	for _, v := range identityKeyVectors {
+		if v.bits > 1024 && testing.Short() {
+			continue
+		}
+		key, err := DeterministicIdentityKey(seed, v.bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		identity, err := IdentityFromKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.PeerID != v.peerID {
+			t.Errorf("Expected %d-bit peer ID %s, got %s", v.bits, v.peerID, identity.PeerID)
+		}
+	}
+	if _, err := DeterministicIdentityKey(seed, 1000); err == nil {
+		t.Error("Expected invalid key size to fail")
+	}
+}
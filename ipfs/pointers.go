@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/ipfs/go-ipfs/core"
@@ -35,6 +36,10 @@ const (
 	CHANNEL Purpose   = 4
 )
 
+// Tag and channel pointers use the whole 64 bit prefix so every pointer for a
+// topic is stored under the same key
+const TopicPrefixLen = 64
+
 
 // A pointer is a custom provider inserted into the dht which points to a location of a file.
 // For offline messaging purposes we use a hash of the recipient's ID as the key and set the
@@ -63,6 +68,13 @@ func PublishPointer(node *core.IpfsNode, ctx context.Context, mhKey multihash.Mu
 	return Pointer{Key: k, Value: pi}, addPointer(node, ctx, k, pi)
 }
 
+// The key for pointers advertising a tag or channel. Topics are case insensitive.
+func TopicKey(purpose Purpose, topic string) multihash.Multihash {
+	h := sha256.Sum256([]byte(strconv.Itoa(int(purpose)) + ":" + strings.ToLower(strings.TrimSpace(topic))))
+	mh, _ := multihash.Encode(h[:], multihash.SHA2_256)
+	return mh
+}
+
 func RePublishPointer(node *core.IpfsNode, ctx context.Context, pointer Pointer) error {
 	return addPointer(node, ctx, pointer.Key, pointer.Value)
 }
@@ -0,0 +1,22 @@
+package ipfs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTopicKey(t *testing.T) {
+	k := TopicKey(TAG, "shoes")
+	if !bytes.Equal(k, TopicKey(TAG, " Shoes ")) {
+		t.Error("Topic keys should be case insensitive")
+	}
+	if bytes.Equal(k, TopicKey(CHANNEL, "shoes")) {
+		t.Error("Tags and channels with the same name should have different keys")
+	}
+	if bytes.Equal(k, TopicKey(TAG, "boots")) {
+		t.Error("Different tags should have different keys")
+	}
+	if !bytes.Equal(createKey(k, TopicPrefixLen), createKey(TopicKey(TAG, "SHOES"), TopicPrefixLen)) {
+		t.Error("Pointer keys should match for the same topic")
+	}
+}
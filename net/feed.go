@@ -0,0 +1,143 @@
+package net
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"time"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/ipfs/go-ipfs/commands"
+)
+
+var ErrInvalidPost = errors.New("Invalid post signature")
+
+// An entry in the feed/index.json file of a node's root directory
+type FeedIndexEntry struct {
+	Hash      string
+	Timestamp uint64
+}
+
+// Periodically pulls the feed of each peer we follow over IPNS and stores new
+// posts in the database
+type FeedAggregator struct {
+	db  repo.Datastore
+	ctx commands.Context
+}
+
+func NewFeedAggregator(db repo.Datastore, ctx commands.Context) *FeedAggregator {
+	return &FeedAggregator{
+		db:  db,
+		ctx: ctx,
+	}
+}
+
+func (a *FeedAggregator) Run() {
+	tick := time.NewTicker(time.Hour)
+	defer tick.Stop()
+	go a.fetchFeeds()
+	for {
+		select {
+		case <-tick.C:
+			go a.fetchFeeds()
+		}
+	}
+}
+
+func (a *FeedAggregator) fetchFeeds() {
+	following, err := a.db.Following().Get(0, -1)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, peerId := range following {
+		go func(peerId string) {
+			if err := a.fetchFeed(peerId); err != nil {
+				log.Debugf("Failed to fetch feed of %s: %s", peerId, err)
+			}
+		}(peerId)
+	}
+}
+
+// Fetch the posts in the peer's feed index we don't already have
+func (a *FeedAggregator) fetchFeed(peerId string) error {
+	b, err := ipfs.Cat(a.ctx, path.Join("/ipns", peerId, "feed", "index.json"))
+	if err != nil {
+		return err
+	}
+	var index []FeedIndexEntry
+	if err := json.Unmarshal(b, &index); err != nil {
+		return err
+	}
+	for _, entry := range index {
+		if a.db.Feed().Has(entry.Hash) {
+			continue
+		}
+		b, err := ipfs.Cat(a.ctx, entry.Hash)
+		if err != nil {
+			log.Debugf("Failed to fetch post %s: %s", entry.Hash, err)
+			continue
+		}
+		sp := new(pb.SignedPost)
+		if err := jsonpb.UnmarshalString(string(b), sp); err != nil {
+			log.Debugf("Failed to parse post %s: %s", entry.Hash, err)
+			continue
+		}
+		if err := VerifyPost(sp, peerId); err != nil {
+			log.Debugf("Dropping post %s from %s: %s", entry.Hash, peerId, err)
+			continue
+		}
+		ser, err := proto.Marshal(sp)
+		if err != nil {
+			return err
+		}
+		if err := a.db.Feed().Put(entry.Hash, peerId, ser, time.Unix(int64(sp.Post.Timestamp), 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The peer ID of the identity key the post claims to be signed with
+func PostAuthor(sp *pb.SignedPost) (string, error) {
+	pubkey, err := libp2p.UnmarshalPublicKey(sp.GuidPubkey)
+	if err != nil {
+		return "", ErrInvalidPost
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil {
+		return "", ErrInvalidPost
+	}
+	return id.Pretty(), nil
+}
+
+// Check the post was signed by the identity key of the peer
+func VerifyPost(sp *pb.SignedPost, peerId string) error {
+	if sp.Post == nil {
+		return ErrInvalidPost
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(sp.GuidPubkey)
+	if err != nil {
+		return ErrInvalidPost
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil || id.Pretty() != peerId {
+		return ErrInvalidPost
+	}
+	ser, err := proto.Marshal(sp.Post)
+	if err != nil {
+		return err
+	}
+	valid, err := pubkey.Verify(ser, sp.Signature)
+	if err != nil || !valid {
+		return ErrInvalidPost
+	}
+	return nil
+}
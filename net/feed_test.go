@@ -0,0 +1,50 @@
+package net
+
+import (
+	"testing"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func signTestPost(t *testing.T, sk libp2p.PrivKey, post *pb.Post) *pb.SignedPost {
+	ser, err := proto.Marshal(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := sk.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.SignedPost{Post: post, GuidPubkey: pubkey, Signature: sig}
+}
+
+func TestVerifyPost(t *testing.T) {
+	sk, _, err := libp2p.GenerateKeyPair(libp2p.RSA, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := peer.IDFromPrivateKey(sk)
+	sp := signTestPost(t, sk, &pb.Post{Text: "New stock", Listings: []string{"shoes"}, Timestamp: 1470000000})
+	if err := VerifyPost(sp, id.Pretty()); err != nil {
+		t.Error(err)
+	}
+	if err := VerifyPost(sp, "QmOtherPeer"); err != ErrInvalidPost {
+		t.Error("Accepted post from the wrong peer")
+	}
+	sp.Post.Text = "Changed"
+	if err := VerifyPost(sp, id.Pretty()); err != ErrInvalidPost {
+		t.Error("Accepted modified post")
+	}
+	sp.Post = nil
+	if err := VerifyPost(sp, id.Pretty()); err != ErrInvalidPost {
+		t.Error("Accepted post without content")
+	}
+}
@@ -0,0 +1,46 @@
+package net
+
+import (
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidListing = errors.New("Invalid listing signature")
+
+// Check the listing in the contract was signed by the vendor's identity key
+func VerifyListing(contract *pb.RicardianContract) error {
+	if len(contract.VendorListings) == 0 || len(contract.Signatures) == 0 {
+		return ErrInvalidListing
+	}
+	listing := contract.VendorListings[0]
+	if listing.VendorID == nil || listing.VendorID.Pubkeys == nil {
+		return ErrInvalidListing
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(listing.VendorID.Pubkeys.Guid)
+	if err != nil {
+		return ErrInvalidListing
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil || id.Pretty() != listing.VendorID.Guid {
+		return ErrInvalidListing
+	}
+	ser, err := proto.Marshal(listing)
+	if err != nil {
+		return err
+	}
+	for _, s := range contract.Signatures {
+		if s.Section != pb.Signatures_LISTING {
+			continue
+		}
+		valid, err := pubkey.Verify(ser, s.Guid)
+		if err == nil && valid {
+			return nil
+		}
+	}
+	return ErrInvalidListing
+}
@@ -0,0 +1,38 @@
+package net
+
+import (
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidModerator = errors.New("Invalid moderator signature")
+
+// Check the moderator profile was signed by the identity key it carries and
+// return the peer ID of that key
+func VerifyModerator(sm *pb.SignedModerator) (string, error) {
+	if sm.Moderator == nil {
+		return "", ErrInvalidModerator
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(sm.GuidPubkey)
+	if err != nil {
+		return "", ErrInvalidModerator
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil {
+		return "", ErrInvalidModerator
+	}
+	ser, err := proto.Marshal(sm.Moderator)
+	if err != nil {
+		return "", err
+	}
+	valid, err := pubkey.Verify(ser, sm.Signature)
+	if err != nil || !valid {
+		return "", ErrInvalidModerator
+	}
+	return id.Pretty(), nil
+}
@@ -0,0 +1,52 @@
+package net
+
+import (
+	"testing"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestVerifyModerator(t *testing.T) {
+	sk, _, err := libp2p.GenerateKeyPair(libp2p.RSA, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := peer.IDFromPrivateKey(sk)
+	mod := &pb.Moderator{
+		Description: "Fast dispute resolution",
+		Languages:   []string{"en", "de"},
+		Fee:         &pb.Moderator_Fee{Percentage: 2.5},
+	}
+	ser, err := proto.Marshal(mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := sk.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := &pb.SignedModerator{Moderator: mod, GuidPubkey: pubkey, Signature: sig}
+	peerId, err := VerifyModerator(sm)
+	if err != nil {
+		t.Error(err)
+	}
+	if peerId != id.Pretty() {
+		t.Error("Returned the wrong peer ID")
+	}
+	sm.Moderator.Fee.Percentage = 1
+	if _, err := VerifyModerator(sm); err != ErrInvalidModerator {
+		t.Error("Accepted modified moderator")
+	}
+	sm.Moderator = nil
+	if _, err := VerifyModerator(sm); err != ErrInvalidModerator {
+		t.Error("Accepted moderator without content")
+	}
+}
@@ -0,0 +1,146 @@
+package net
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/ipfs/go-ipfs/commands"
+)
+
+// An entry in the listings/index.json file of a node's root directory
+type ListingIndexEntry struct {
+	Hash string
+	Name string
+}
+
+// Keeps the local search index up to date. The stores of the peers we follow and of
+// any peer with listings already in the index are crawled periodically. Only
+// listings which changed since the last crawl are fetched.
+type SearchIndexer struct {
+	db  repo.Datastore
+	ctx commands.Context
+}
+
+func NewSearchIndexer(db repo.Datastore, ctx commands.Context) *SearchIndexer {
+	return &SearchIndexer{
+		db:  db,
+		ctx: ctx,
+	}
+}
+
+func (s *SearchIndexer) Run() {
+	tick := time.NewTicker(time.Hour * 6)
+	defer tick.Stop()
+	go s.crawl()
+	for {
+		select {
+		case <-tick.C:
+			go s.crawl()
+		}
+	}
+}
+
+func (s *SearchIndexer) crawl() {
+	following, err := s.db.Following().Get(0, -1)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	discovered, err := s.db.SearchIndex().Peers()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, peerId := range append(following, discovered...) {
+		if seen[peerId] {
+			continue
+		}
+		seen[peerId] = true
+		go func(peerId string) {
+			if err := s.IndexPeer(peerId); err != nil {
+				log.Debugf("Failed to index listings of %s: %s", peerId, err)
+			}
+		}(peerId)
+	}
+}
+
+// Fetch the peer's listing index, index the listings we don't already have and
+// drop the ones the peer no longer lists
+func (s *SearchIndexer) IndexPeer(peerId string) error {
+	b, err := ipfs.Cat(s.ctx, path.Join("/ipns", peerId, "listings", "index.json"))
+	if err != nil {
+		return err
+	}
+	var index []ListingIndexEntry
+	if err := json.Unmarshal(b, &index); err != nil {
+		return err
+	}
+	var hashes []string
+	for _, entry := range index {
+		hashes = append(hashes, entry.Hash)
+		if s.db.SearchIndex().Has(entry.Hash) {
+			continue
+		}
+		b, err := ipfs.Cat(s.ctx, entry.Hash)
+		if err != nil {
+			log.Debugf("Failed to fetch listing %s: %s", entry.Hash, err)
+			continue
+		}
+		contract := new(pb.RicardianContract)
+		if err := jsonpb.UnmarshalString(string(b), contract); err != nil {
+			log.Debugf("Failed to parse listing %s: %s", entry.Hash, err)
+			continue
+		}
+		if err := VerifyListing(contract); err != nil {
+			log.Debugf("Dropping listing %s: %s", entry.Hash, err)
+			continue
+		}
+		if contract.VendorListings[0].VendorID.Guid != peerId {
+			log.Debugf("Dropping listing %s: not listed by %s", entry.Hash, peerId)
+			continue
+		}
+		if err := s.IndexListing(entry.Hash, contract); err != nil {
+			return err
+		}
+	}
+	return s.db.SearchIndex().Prune(peerId, hashes)
+}
+
+// Add a verified listing to the search index
+func (s *SearchIndexer) IndexListing(hash string, contract *pb.RicardianContract) error {
+	listing := contract.VendorListings[0]
+	l := repo.SearchListing{
+		Hash:        hash,
+		PeerId:      listing.VendorID.Guid,
+		ListingName: listing.ListingName,
+		Timestamp:   time.Now(),
+	}
+	if listing.Metadata != nil {
+		l.Category = int(listing.Metadata.Category)
+	}
+	if item := listing.Item; item != nil {
+		l.Title = item.Title
+		l.Description = item.Description
+		l.Tags = item.Tags
+		if price := item.PricePerUnit; price != nil {
+			l.Price = uint64(price.Bitcoin)
+			if price.Fiat != nil {
+				l.CurrencyCode = strings.ToUpper(price.Fiat.CurrencyCode)
+				l.FiatPrice = float64(price.Fiat.Price)
+			}
+		}
+	}
+	if listing.Shipping != nil {
+		for _, region := range listing.Shipping.ShippingRegions {
+			l.ShipsTo = append(l.ShipsTo, int(region))
+		}
+	}
+	return s.db.SearchIndex().Put(l)
+}
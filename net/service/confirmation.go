@@ -0,0 +1,78 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidConfirmation = errors.New("Invalid order confirmation")
+
+// Attach the vendor's confirmation of the order to the contract, signed with the vendor's
+// identity key. The confirmation references the order by its hash.
+func SignOrderConfirmation(sk libp2p.PrivKey, contract *pb.RicardianContract, confirmation *pb.OrderConfirmation) error {
+	if contract.BuyerOrder == nil {
+		return ErrInvalidConfirmation
+	}
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(ser)
+	confirmation.OrderHash = h[:]
+	ser, err = proto.Marshal(confirmation)
+	if err != nil {
+		return err
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		return err
+	}
+	contract.VendorOrderConfirmation = confirmation
+	contract.Signatures = append(contract.Signatures, &pb.Signatures{
+		Section: pb.Signatures_ORDER_CONFIRMATION,
+		Guid:    sig,
+	})
+	return nil
+}
+
+// Check a confirmation of one of our orders. The order must be signed by the buyer and
+// the confirmation must reference the order and be signed by the vendor.
+func VerifyOrderConfirmation(contract *pb.RicardianContract) error {
+	order, confirmation := contract.BuyerOrder, contract.VendorOrderConfirmation
+	if order == nil || confirmation == nil || len(contract.VendorListings) == 0 {
+		return ErrInvalidConfirmation
+	}
+	buyerKey, err := identityKey(order.BuyerID)
+	if err != nil {
+		return ErrInvalidConfirmation
+	}
+	ser, err := proto.Marshal(order)
+	if err != nil {
+		return err
+	}
+	if !signedBy(buyerKey, ser, pb.Signatures_ORDER, contract.Signatures) {
+		return ErrInvalidConfirmation
+	}
+	h := sha256.Sum256(ser)
+	if !bytes.Equal(confirmation.OrderHash, h[:]) {
+		return ErrInvalidConfirmation
+	}
+	vendorKey, err := identityKey(contract.VendorListings[0].VendorID)
+	if err != nil {
+		return ErrInvalidConfirmation
+	}
+	ser, err = proto.Marshal(confirmation)
+	if err != nil {
+		return err
+	}
+	if !signedBy(vendorKey, ser, pb.Signatures_ORDER_CONFIRMATION, contract.Signatures) {
+		return ErrInvalidConfirmation
+	}
+	return nil
+}
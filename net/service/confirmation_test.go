@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func TestVerifyOrderConfirmation(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	confirmation := &pb.OrderConfirmation{Timestamp: 1470100000}
+	if err := SignOrderConfirmation(vendorSk, contract, confirmation); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOrderConfirmation(contract); err != nil {
+		t.Error(err)
+	}
+
+	confirmation.Timestamp++
+	if err := VerifyOrderConfirmation(contract); err != ErrInvalidConfirmation {
+		t.Error("Accepted modified confirmation")
+	}
+	confirmation.Timestamp--
+
+	contract.BuyerOrder.Timestamp++
+	if err := VerifyOrderConfirmation(contract); err != ErrInvalidConfirmation {
+		t.Error("Accepted modified order")
+	}
+	contract.BuyerOrder.Timestamp--
+
+	if err := VerifyOrderConfirmation(contract); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyOrderConfirmationSignedByOther(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	// Only the vendor may confirm the order
+	if err := SignOrderConfirmation(buyerSk, contract, &pb.OrderConfirmation{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOrderConfirmation(contract); err != ErrInvalidConfirmation {
+		t.Error("Accepted confirmation signed by the buyer")
+	}
+}
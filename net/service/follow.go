@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidFollowProof = errors.New("Invalid follow proof")
+
+// Sign a FOLLOW or UNFOLLOW of peerId made at timestamp. The serialized SignedData is
+// sent as the message payload and kept by the followed peer as proof of the follow.
+func SignFollowCommand(sk libp2p.PrivKey, t pb.Message_MessageType, peerId string, timestamp time.Time) ([]byte, error) {
+	cmd := &pb.SignedData_Command{
+		PeerID:    peerId,
+		Type:      t,
+		Timestamp: uint64(timestamp.Unix()),
+	}
+	ser, err := proto.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := sk.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	sd := &pb.SignedData{
+		SenderPubkey:   pubkey,
+		SerializedData: ser,
+		Signature:      sig,
+	}
+	return proto.Marshal(sd)
+}
+
+// Check that proof is a command of type t signed by sender for peerId and return it
+func VerifyFollowCommand(proof []byte, t pb.Message_MessageType, sender peer.ID, peerId string) (*pb.SignedData_Command, error) {
+	sd := new(pb.SignedData)
+	if err := proto.Unmarshal(proof, sd); err != nil {
+		return nil, ErrInvalidFollowProof
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(sd.SenderPubkey)
+	if err != nil {
+		return nil, ErrInvalidFollowProof
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil || id != sender {
+		return nil, ErrInvalidFollowProof
+	}
+	valid, err := pubkey.Verify(sd.SerializedData, sd.Signature)
+	if err != nil || !valid {
+		return nil, ErrInvalidFollowProof
+	}
+	cmd := new(pb.SignedData_Command)
+	if err := proto.Unmarshal(sd.SerializedData, cmd); err != nil {
+		return nil, ErrInvalidFollowProof
+	}
+	if cmd.Type != t || cmd.PeerID != peerId {
+		return nil, ErrInvalidFollowProof
+	}
+	return cmd, nil
+}
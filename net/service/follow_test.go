@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func newTestIdentity(t *testing.T) (libp2p.PrivKey, peer.ID) {
+	sk, _, err := libp2p.GenerateKeyPair(libp2p.RSA, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk, id
+}
+
+func TestVerifyFollowCommand(t *testing.T) {
+	sk, follower := newTestIdentity(t)
+	otherSk, other := newTestIdentity(t)
+	vendor := "QmVendor"
+	timestamp := time.Unix(1470000000, 0)
+
+	proof, err := SignFollowCommand(sk, pb.Message_FOLLOW, vendor, timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := VerifyFollowCommand(proof, pb.Message_FOLLOW, follower, vendor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Timestamp != uint64(timestamp.Unix()) || cmd.PeerID != vendor {
+		t.Error("Returned wrong command")
+	}
+
+	// A proof is only valid for the sender, followed peer and command it was signed for
+	if _, err := VerifyFollowCommand(proof, pb.Message_FOLLOW, other, vendor); err != ErrInvalidFollowProof {
+		t.Error("Accepted proof from the wrong sender")
+	}
+	if _, err := VerifyFollowCommand(proof, pb.Message_FOLLOW, follower, "QmOtherVendor"); err != ErrInvalidFollowProof {
+		t.Error("Accepted proof for the wrong peer")
+	}
+	if _, err := VerifyFollowCommand(proof, pb.Message_UNFOLLOW, follower, vendor); err != ErrInvalidFollowProof {
+		t.Error("Accepted FOLLOW proof as UNFOLLOW")
+	}
+	if _, err := VerifyFollowCommand([]byte("garbage"), pb.Message_FOLLOW, follower, vendor); err != ErrInvalidFollowProof {
+		t.Error("Accepted invalid proof")
+	}
+
+	// Signed by a different key than the one it claims
+	forged, err := SignFollowCommand(otherSk, pb.Message_FOLLOW, vendor, timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyFollowCommand(forged, pb.Message_FOLLOW, follower, vendor); err != ErrInvalidFollowProof {
+		t.Error("Accepted proof signed by another peer")
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidFulfillment = errors.New("Invalid order fulfillment")
+
+// Attach the vendor's fulfillment of the order to the contract, signed with the vendor's
+// identity key. The fulfillment references the order by its hash.
+func SignFulfillment(sk libp2p.PrivKey, contract *pb.RicardianContract, fulfillment *pb.OrderFulfillment) error {
+	if contract.BuyerOrder == nil {
+		return ErrInvalidFulfillment
+	}
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(ser)
+	fulfillment.OrderHash = h[:]
+	ser, err = proto.Marshal(fulfillment)
+	if err != nil {
+		return err
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		return err
+	}
+	contract.VendorOrderFulfillment = fulfillment
+	contract.Signatures = append(contract.Signatures, &pb.Signatures{
+		Section: pb.Signatures_ORDER_FULFILLMENT,
+		Guid:    sig,
+	})
+	return nil
+}
+
+// Check a fulfillment of one of our orders. The order must be signed by the buyer and the
+// fulfillment must reference the order and be signed by the vendor. It must carry what
+// each kind of listing in the order needs: a carrier and tracking number for physical
+// goods, a file for digital goods and a note for services.
+func VerifyFulfillment(contract *pb.RicardianContract) error {
+	order, fulfillment := contract.BuyerOrder, contract.VendorOrderFulfillment
+	if order == nil || fulfillment == nil || len(contract.VendorListings) == 0 {
+		return ErrInvalidFulfillment
+	}
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata == nil {
+			continue
+		}
+		switch listing.Metadata.Category {
+		case pb.Listing_Metadata_PHYSICAL_GOOD:
+			if fulfillment.Physical == nil || fulfillment.Physical.Carrier == "" || fulfillment.Physical.TrackingNumber == "" {
+				return ErrInvalidFulfillment
+			}
+		case pb.Listing_Metadata_DIGITAL_GOOD:
+			if fulfillment.Digital == nil || fulfillment.Digital.Url == "" {
+				return ErrInvalidFulfillment
+			}
+		case pb.Listing_Metadata_SERVICE:
+			if fulfillment.Note == "" {
+				return ErrInvalidFulfillment
+			}
+		}
+	}
+	buyerKey, err := identityKey(order.BuyerID)
+	if err != nil {
+		return ErrInvalidFulfillment
+	}
+	ser, err := proto.Marshal(order)
+	if err != nil {
+		return err
+	}
+	if !signedBy(buyerKey, ser, pb.Signatures_ORDER, contract.Signatures) {
+		return ErrInvalidFulfillment
+	}
+	h := sha256.Sum256(ser)
+	if !bytes.Equal(fulfillment.OrderHash, h[:]) {
+		return ErrInvalidFulfillment
+	}
+	vendorKey, err := identityKey(contract.VendorListings[0].VendorID)
+	if err != nil {
+		return ErrInvalidFulfillment
+	}
+	ser, err = proto.Marshal(fulfillment)
+	if err != nil {
+		return err
+	}
+	if !signedBy(vendorKey, ser, pb.Signatures_ORDER_FULFILLMENT, contract.Signatures) {
+		return ErrInvalidFulfillment
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestVerifyFulfillment(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	fulfillment := &pb.OrderFulfillment{
+		Timestamp: 1470100000,
+		Physical:  &pb.OrderFulfillment_Physical{Carrier: "UPS", TrackingNumber: "1Z999"},
+	}
+	if err := SignFulfillment(vendorSk, contract, fulfillment); err != nil {
+		t.Fatal(err)
+	}
+	// The signed section survives the wire
+	ser, err := proto.Marshal(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := new(pb.RicardianContract)
+	if err := proto.Unmarshal(ser, received); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFulfillment(received); err != nil {
+		t.Error(err)
+	}
+
+	fulfillment.Physical.TrackingNumber = "1Z998"
+	if err := VerifyFulfillment(contract); err != ErrInvalidFulfillment {
+		t.Error("Accepted modified fulfillment")
+	}
+	fulfillment.Physical.TrackingNumber = "1Z999"
+
+	contract.BuyerOrder.Timestamp++
+	if err := VerifyFulfillment(contract); err != ErrInvalidFulfillment {
+		t.Error("Accepted modified order")
+	}
+	contract.BuyerOrder.Timestamp--
+
+	if err := VerifyFulfillment(contract); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyFulfillmentSignedByOther(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	// Only the vendor may fulfill the order
+	if err := SignFulfillment(buyerSk, contract, &pb.OrderFulfillment{Note: "Done"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFulfillment(contract); err != ErrInvalidFulfillment {
+		t.Error("Accepted fulfillment signed by the buyer")
+	}
+}
+
+func TestVerifyFulfillmentCategory(t *testing.T) {
+	tests := []struct {
+		category    pb.Listing_Metadata_Category
+		fulfillment *pb.OrderFulfillment
+		valid       bool
+	}{
+		{pb.Listing_Metadata_PHYSICAL_GOOD, &pb.OrderFulfillment{Physical: &pb.OrderFulfillment_Physical{Carrier: "UPS", TrackingNumber: "1Z999"}}, true},
+		{pb.Listing_Metadata_PHYSICAL_GOOD, &pb.OrderFulfillment{Physical: &pb.OrderFulfillment_Physical{Carrier: "UPS"}}, false},
+		{pb.Listing_Metadata_PHYSICAL_GOOD, &pb.OrderFulfillment{Note: "Shipped"}, false},
+		{pb.Listing_Metadata_DIGITAL_GOOD, &pb.OrderFulfillment{Digital: &pb.OrderFulfillment_Digital{Url: "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", Password: "hunter2"}}, true},
+		{pb.Listing_Metadata_DIGITAL_GOOD, &pb.OrderFulfillment{Digital: &pb.OrderFulfillment_Digital{Password: "hunter2"}}, false},
+		{pb.Listing_Metadata_SERVICE, &pb.OrderFulfillment{Note: "Lawn mowed"}, true},
+		{pb.Listing_Metadata_SERVICE, &pb.OrderFulfillment{}, false},
+	}
+	for _, test := range tests {
+		vendorSk, vendor := newTestIdentity(t)
+		buyerSk, buyer := newTestIdentity(t)
+		contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+		contract.VendorListings[0].Metadata = &pb.Listing_Metadata{Category: test.category}
+		if err := SignFulfillment(vendorSk, contract, test.fulfillment); err != nil {
+			t.Fatal(err)
+		}
+		err := VerifyFulfillment(contract)
+		if test.valid && err != nil {
+			t.Errorf("%s: %s", test.category, err)
+		}
+		if !test.valid && err != ErrInvalidFulfillment {
+			t.Errorf("%s: accepted invalid fulfillment", test.category)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"errors"
+	"time"
+
 	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
 )
 
 type serviceHandler func(peer.ID, *pb.Message) (*pb.Message, error)
@@ -15,6 +19,14 @@ func (service *OpenBazaarService) HandlerForMsgType(t pb.Message_MessageType) se
 		return service.handleFollow
 	case pb.Message_UNFOLLOW:
 		return service.handleUnFollow
+	case pb.Message_ORDER_CONFIRMATION:
+		return service.handleOrderConfirmation
+	case pb.Message_RATING:
+		return service.handleRating
+	case pb.Message_REFUND:
+		return service.handleRefund
+	case pb.Message_ORDER_FULFILLMENT:
+		return service.handleOrderFulfillment
 	case pb.Message_OFFLINE_ACK:
 		return service.handleOfflineAck
 	default:
@@ -29,21 +41,151 @@ func (service *OpenBazaarService) handlePing(peer peer.ID, pmes *pb.Message) (*p
 
 func (service *OpenBazaarService) handleFollow(peer peer.ID, pmes *pb.Message) (*pb.Message, error) {
 	log.Debugf("Received FOLLOW message from %s", peer.Pretty())
-	err := service.datastore.Followers().Put(peer.Pretty())
+	timestamp, err := service.verifyFollow(peer, pmes)
+	if err != nil {
+		return nil, err
+	}
+	err = service.datastore.Followers().Put(peer.Pretty(), pmes.Payload.Value, timestamp)
 	if err != nil {
 		return nil, err
 	}
 	service.broadcast <- []byte(`{"notification": {"follow":"` + peer.Pretty() + `"}}`)
+	if err := service.updateFollow(); err != nil {
+		log.Errorf("Failed to publish followers: %s", err)
+	}
 	return nil, nil
 }
 
 func (service *OpenBazaarService) handleUnFollow(peer peer.ID, pmes *pb.Message) (*pb.Message, error) {
 	log.Debugf("Received UNFOLLOW message from %s", peer.Pretty())
-	err := service.datastore.Followers().Delete(peer.Pretty())
+	timestamp, err := service.verifyFollow(peer, pmes)
+	if err != nil {
+		return nil, err
+	}
+	err = service.datastore.Followers().Delete(peer.Pretty(), timestamp)
 	if err != nil {
 		return nil, err
 	}
 	service.broadcast <- []byte(`{"notification": {"unfollow":"` + peer.Pretty() + `"}}`)
+	if err := service.updateFollow(); err != nil {
+		log.Errorf("Failed to publish followers: %s", err)
+	}
+	return nil, nil
+}
+
+var errStaleFollow = errors.New("Ignoring follow command older than the last one applied")
+
+// Check the signed proof in a FOLLOW or UNFOLLOW message and return its timestamp.
+// Offline messages can arrive out of order so commands older than the last one
+// applied for the peer are rejected.
+func (service *OpenBazaarService) verifyFollow(p peer.ID, pmes *pb.Message) (time.Time, error) {
+	if pmes.Payload == nil {
+		return time.Time{}, ErrInvalidFollowProof
+	}
+	cmd, err := VerifyFollowCommand(pmes.Payload.Value, pmes.MessageType, p, service.self.Pretty())
+	if err != nil {
+		return time.Time{}, err
+	}
+	timestamp := time.Unix(int64(cmd.Timestamp), 0)
+	last, err := service.datastore.Followers().LastUpdate(p.Pretty())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if timestamp.Before(last) {
+		return time.Time{}, errStaleFollow
+	}
+	return timestamp, nil
+}
+
+func (service *OpenBazaarService) handleOrderConfirmation(p peer.ID, pmes *pb.Message) (*pb.Message, error) {
+	log.Debugf("Received ORDER_CONFIRMATION message from %s", p.Pretty())
+	if pmes.Payload == nil {
+		return nil, ErrInvalidConfirmation
+	}
+	contract := new(pb.RicardianContract)
+	if err := proto.Unmarshal(pmes.Payload.Value, contract); err != nil {
+		return nil, err
+	}
+	if err := VerifyOrderConfirmation(contract); err != nil {
+		return nil, err
+	}
+	// Only confirmations of our orders sent by the vendor
+	if contract.BuyerOrder.BuyerID.Guid != service.self.Pretty() || contract.VendorListings[0].VendorID.Guid != p.Pretty() {
+		return nil, ErrInvalidConfirmation
+	}
+	if err := service.processConfirmation(contract); err != nil {
+		return nil, err
+	}
+	service.broadcast <- []byte(`{"notification": {"orderConfirmation":"` + p.Pretty() + `"}}`)
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleRating(p peer.ID, pmes *pb.Message) (*pb.Message, error) {
+	log.Debugf("Received RATING message from %s", p.Pretty())
+	if pmes.Payload == nil {
+		return nil, ErrInvalidRating
+	}
+	contract := new(pb.RicardianContract)
+	if err := proto.Unmarshal(pmes.Payload.Value, contract); err != nil {
+		return nil, err
+	}
+	if err := VerifyRating(contract, service.self.Pretty()); err != nil {
+		return nil, err
+	}
+	// Only the buyer may send us their rating
+	if contract.BuyerOrder.BuyerID.Guid != p.Pretty() {
+		return nil, ErrInvalidRating
+	}
+	if err := service.publishRating(contract); err != nil {
+		return nil, err
+	}
+	service.broadcast <- []byte(`{"notification": {"rating":"` + p.Pretty() + `"}}`)
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message) (*pb.Message, error) {
+	log.Debugf("Received REFUND message from %s", p.Pretty())
+	if pmes.Payload == nil {
+		return nil, ErrInvalidRefund
+	}
+	contract := new(pb.RicardianContract)
+	if err := proto.Unmarshal(pmes.Payload.Value, contract); err != nil {
+		return nil, err
+	}
+	if err := VerifyRefund(contract); err != nil {
+		return nil, err
+	}
+	// Only refunds of our orders sent by the vendor
+	if contract.BuyerOrder.BuyerID.Guid != service.self.Pretty() || contract.VendorListings[0].VendorID.Guid != p.Pretty() {
+		return nil, ErrInvalidRefund
+	}
+	if err := service.processRefund(contract); err != nil {
+		return nil, err
+	}
+	service.broadcast <- []byte(`{"notification": {"refund":"` + p.Pretty() + `"}}`)
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleOrderFulfillment(p peer.ID, pmes *pb.Message) (*pb.Message, error) {
+	log.Debugf("Received ORDER_FULFILLMENT message from %s", p.Pretty())
+	if pmes.Payload == nil {
+		return nil, ErrInvalidFulfillment
+	}
+	contract := new(pb.RicardianContract)
+	if err := proto.Unmarshal(pmes.Payload.Value, contract); err != nil {
+		return nil, err
+	}
+	if err := VerifyFulfillment(contract); err != nil {
+		return nil, err
+	}
+	// Only fulfillments of our orders sent by the vendor
+	if contract.BuyerOrder.BuyerID.Guid != service.self.Pretty() || contract.VendorListings[0].VendorID.Guid != p.Pretty() {
+		return nil, ErrInvalidFulfillment
+	}
+	if err := service.processFulfillment(contract); err != nil {
+		return nil, err
+	}
+	service.broadcast <- []byte(`{"notification": {"fulfillment":"` + p.Pretty() + `"}}`)
 	return nil, nil
 }
 
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	ErrInvalidRating = errors.New("Invalid rating")
+	errInvalidID     = errors.New("Invalid identity key")
+)
+
+// Attach the buyer's rating of the order to the contract. The rating references the
+// order by its hash and is signed with the buyer's identity key.
+func SignRating(sk libp2p.PrivKey, contract *pb.RicardianContract, rating *pb.Rating) error {
+	if contract.BuyerOrder == nil {
+		return ErrInvalidRating
+	}
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(ser)
+	rating.OrderHash = h[:]
+	ser, err = proto.Marshal(rating)
+	if err != nil {
+		return err
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		return err
+	}
+	contract.BuyerRating = rating
+	contract.Signatures = append(contract.Signatures, &pb.Signatures{
+		Section: pb.Signatures_RATING,
+		Guid:    sig,
+	})
+	return nil
+}
+
+// Check a rating of the vendor. The listings must be signed by the vendor, the order
+// must be for those listings and signed by the buyer, and the rating must reference
+// the order and be signed by the buyer.
+func VerifyRating(contract *pb.RicardianContract, vendorId string) error {
+	order, rating := contract.BuyerOrder, contract.BuyerRating
+	if order == nil || rating == nil || len(contract.VendorListings) == 0 || len(order.Items) == 0 {
+		return ErrInvalidRating
+	}
+	for _, stars := range []uint32{rating.Quality, rating.Delivery, rating.Communication} {
+		if stars < 1 || stars > 5 {
+			return ErrInvalidRating
+		}
+	}
+
+	listingHashes := make(map[string]bool)
+	for _, listing := range contract.VendorListings {
+		if listing.VendorID == nil || listing.VendorID.Guid != vendorId {
+			return ErrInvalidRating
+		}
+		vendorKey, err := identityKey(listing.VendorID)
+		if err != nil {
+			return ErrInvalidRating
+		}
+		ser, err := proto.Marshal(listing)
+		if err != nil {
+			return err
+		}
+		if !signedBy(vendorKey, ser, pb.Signatures_LISTING, contract.Signatures) {
+			return ErrInvalidRating
+		}
+		h := sha256.Sum256(ser)
+		listingHashes[string(h[:])] = true
+	}
+	for _, item := range order.Items {
+		if !listingHashes[string(item.ListingHash)] {
+			return ErrInvalidRating
+		}
+	}
+
+	buyerKey, err := identityKey(order.BuyerID)
+	if err != nil {
+		return ErrInvalidRating
+	}
+	ser, err := proto.Marshal(order)
+	if err != nil {
+		return err
+	}
+	if !signedBy(buyerKey, ser, pb.Signatures_ORDER, contract.Signatures) {
+		return ErrInvalidRating
+	}
+	h := sha256.Sum256(ser)
+	if !bytes.Equal(rating.OrderHash, h[:]) {
+		return ErrInvalidRating
+	}
+	ser, err = proto.Marshal(rating)
+	if err != nil {
+		return err
+	}
+	if !signedBy(buyerKey, ser, pb.Signatures_RATING, contract.Signatures) {
+		return ErrInvalidRating
+	}
+	return nil
+}
+
+// The identity key in the ID. It must match the ID's peer ID.
+func identityKey(id *pb.ID) (libp2p.PubKey, error) {
+	if id == nil || id.Pubkeys == nil {
+		return nil, errInvalidID
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(id.Pubkeys.Guid)
+	if err != nil {
+		return nil, errInvalidID
+	}
+	pid, err := peer.IDFromPublicKey(pubkey)
+	if err != nil || pid.Pretty() != id.Guid {
+		return nil, errInvalidID
+	}
+	return pubkey, nil
+}
+
+// Is one of the signatures for the section a valid guid signature of the data?
+func signedBy(pubkey libp2p.PubKey, data []byte, section pb.Signatures_Section, sigs []*pb.Signatures) bool {
+	for _, s := range sigs {
+		if s.Section != section {
+			continue
+		}
+		if valid, err := pubkey.Verify(data, s.Guid); err == nil && valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+	peer "gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+func testID(t *testing.T, sk libp2p.PrivKey, id peer.ID) *pb.ID {
+	pubkey, err := sk.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.ID{Guid: id.Pretty(), Pubkeys: &pb.ID_Pubkeys{Guid: pubkey}}
+}
+
+func sign(t *testing.T, sk libp2p.PrivKey, m proto.Message, section pb.Signatures_Section) *pb.Signatures {
+	ser, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.Signatures{Section: section, Guid: sig}
+}
+
+// A contract for an order of one of the vendor's listings, signed by the buyer
+func newTestOrder(t *testing.T, vendorSk libp2p.PrivKey, vendor peer.ID, buyerSk libp2p.PrivKey, buyer peer.ID) *pb.RicardianContract {
+	listing := &pb.Listing{
+		ListingName: "shoes",
+		VendorID:    testID(t, vendorSk, vendor),
+		Item:        &pb.Listing_Item{Title: "Red shoes"},
+	}
+	ser, err := proto.Marshal(listing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(ser)
+	order := &pb.Order{
+		BuyerID:   testID(t, buyerSk, buyer),
+		Timestamp: 1470000000,
+		Items:     []*pb.Order_Item{{ListingHash: h[:], Quantity: 1}},
+	}
+	return &pb.RicardianContract{
+		VendorListings: []*pb.Listing{listing},
+		BuyerOrder:     order,
+		Signatures: []*pb.Signatures{
+			sign(t, vendorSk, listing, pb.Signatures_LISTING),
+			sign(t, buyerSk, order, pb.Signatures_ORDER),
+		},
+	}
+}
+
+func TestVerifyRating(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	rating := &pb.Rating{Quality: 5, Delivery: 4, Communication: 3, Review: "Great shoes", Timestamp: 1470100000}
+	if err := SignRating(buyerSk, contract, rating); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRating(contract, vendor.Pretty()); err != nil {
+		t.Error(err)
+	}
+	if err := VerifyRating(contract, buyer.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted rating of the wrong vendor")
+	}
+
+	rating.Review = "Changed"
+	if err := VerifyRating(contract, vendor.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted modified rating")
+	}
+	rating.Review = "Great shoes"
+
+	contract.BuyerOrder.Timestamp++
+	if err := VerifyRating(contract, vendor.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted modified order")
+	}
+	contract.BuyerOrder.Timestamp--
+
+	contract.VendorListings[0].Item.Title = "Blue shoes"
+	if err := VerifyRating(contract, vendor.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted modified listing")
+	}
+	contract.VendorListings[0].Item.Title = "Red shoes"
+
+	if err := VerifyRating(contract, vendor.Pretty()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyRatingSignedByOther(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	otherSk, _ := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	// Only the buyer may rate the order
+	if err := SignRating(otherSk, contract, &pb.Rating{Quality: 1, Delivery: 1, Communication: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRating(contract, vendor.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted rating signed by another peer")
+	}
+}
+
+func TestVerifyRatingStars(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	if err := SignRating(buyerSk, contract, &pb.Rating{Quality: 6, Delivery: 5, Communication: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRating(contract, vendor.Pretty()); err != ErrInvalidRating {
+		t.Error("Accepted rating with too many stars")
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	libp2p "gx/ipfs/QmUEUu1CM8bxBJxc3ZLojAi8evhTr4byQogWstABet79oY/go-libp2p-crypto"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+var ErrInvalidRefund = errors.New("Invalid refund")
+
+// Attach the vendor's refund of the order to the contract, signed with the vendor's
+// identity key. The refund references the order by its hash.
+func SignRefund(sk libp2p.PrivKey, contract *pb.RicardianContract, refund *pb.Refund) error {
+	if contract.BuyerOrder == nil {
+		return ErrInvalidRefund
+	}
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(ser)
+	refund.OrderHash = h[:]
+	ser, err = proto.Marshal(refund)
+	if err != nil {
+		return err
+	}
+	sig, err := sk.Sign(ser)
+	if err != nil {
+		return err
+	}
+	contract.Refund = refund
+	contract.Signatures = append(contract.Signatures, &pb.Signatures{
+		Section: pb.Signatures_REFUND,
+		Guid:    sig,
+	})
+	return nil
+}
+
+// Check a refund of one of our orders. The order must be signed by the buyer and the
+// refund must reference the order, be no more than was paid and be signed by the vendor.
+func VerifyRefund(contract *pb.RicardianContract) error {
+	order, refund := contract.BuyerOrder, contract.Refund
+	if order == nil || refund == nil || len(contract.VendorListings) == 0 || refund.Amount == 0 {
+		return ErrInvalidRefund
+	}
+	if order.Payment != nil && order.Payment.Amount > 0 && refund.Amount > uint64(order.Payment.Amount) {
+		return ErrInvalidRefund
+	}
+	buyerKey, err := identityKey(order.BuyerID)
+	if err != nil {
+		return ErrInvalidRefund
+	}
+	ser, err := proto.Marshal(order)
+	if err != nil {
+		return err
+	}
+	if !signedBy(buyerKey, ser, pb.Signatures_ORDER, contract.Signatures) {
+		return ErrInvalidRefund
+	}
+	h := sha256.Sum256(ser)
+	if !bytes.Equal(refund.OrderHash, h[:]) {
+		return ErrInvalidRefund
+	}
+	vendorKey, err := identityKey(contract.VendorListings[0].VendorID)
+	if err != nil {
+		return ErrInvalidRefund
+	}
+	ser, err = proto.Marshal(refund)
+	if err != nil {
+		return err
+	}
+	if !signedBy(vendorKey, ser, pb.Signatures_REFUND, contract.Signatures) {
+		return ErrInvalidRefund
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func TestVerifyRefund(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	contract.BuyerOrder.Payment = &pb.Order_Payment{Method: pb.Order_Payment_DIRECT, Amount: 100000}
+	contract.Signatures[1] = sign(t, buyerSk, contract.BuyerOrder, pb.Signatures_ORDER)
+
+	refund := &pb.Refund{Amount: 40000, Memo: "Out of red shoes", Timestamp: 1470100000, Txid: "abcd"}
+	if err := SignRefund(vendorSk, contract, refund); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRefund(contract); err != nil {
+		t.Error(err)
+	}
+
+	refund.Amount = 40001
+	if err := VerifyRefund(contract); err != ErrInvalidRefund {
+		t.Error("Accepted modified refund")
+	}
+	refund.Amount = 40000
+
+	contract.BuyerOrder.Timestamp++
+	if err := VerifyRefund(contract); err != ErrInvalidRefund {
+		t.Error("Accepted modified order")
+	}
+	contract.BuyerOrder.Timestamp--
+
+	if err := VerifyRefund(contract); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyRefundAmount(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	contract.BuyerOrder.Payment = &pb.Order_Payment{Method: pb.Order_Payment_DIRECT, Amount: 100000}
+	contract.Signatures[1] = sign(t, buyerSk, contract.BuyerOrder, pb.Signatures_ORDER)
+	// A refund can't be more than was paid
+	if err := SignRefund(vendorSk, contract, &pb.Refund{Amount: 100001}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRefund(contract); err != ErrInvalidRefund {
+		t.Error("Accepted refund of more than the payment")
+	}
+}
+
+func TestVerifyRefundSignedByOther(t *testing.T) {
+	vendorSk, vendor := newTestIdentity(t)
+	buyerSk, buyer := newTestIdentity(t)
+	contract := newTestOrder(t, vendorSk, vendor, buyerSk, buyer)
+	// Only the vendor may refund the order
+	if err := SignRefund(buyerSk, contract, &pb.Refund{Amount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRefund(contract); err != ErrInvalidRefund {
+		t.Error("Accepted refund signed by the buyer")
+	}
+}
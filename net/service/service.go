@@ -29,19 +29,39 @@ type OpenBazaarService struct {
 	ctx       context.Context
 	broadcast chan []byte
 	datastore repo.Datastore
+
+	// Republish our followers list after it changes
+	updateFollow func() error
+
+	// Publish a verified rating of one of our orders to our store
+	publishRating func(contract *pb.RicardianContract) error
+
+	// Apply a verified refund of an order we placed
+	processRefund func(contract *pb.RicardianContract) error
+
+	// Apply a verified fulfillment of an order we placed
+	processFulfillment func(contract *pb.RicardianContract) error
+
+	// Apply a verified confirmation of an order we placed
+	processConfirmation func(contract *pb.RicardianContract) error
 }
 
 var OBService *OpenBazaarService
 
-func SetupOpenBazaarService(node *core.IpfsNode, broadcast chan []byte, ctx commands.Context, datastore repo.Datastore) *OpenBazaarService {
+func SetupOpenBazaarService(node *core.IpfsNode, broadcast chan []byte, ctx commands.Context, datastore repo.Datastore, updateFollow func() error, publishRating func(contract *pb.RicardianContract) error, processRefund func(contract *pb.RicardianContract) error, processFulfillment func(contract *pb.RicardianContract) error, processConfirmation func(contract *pb.RicardianContract) error) *OpenBazaarService {
 	OBService = &OpenBazaarService{
-		host:      node.PeerHost.(host.Host),
-		self:      node.Identity,
-		peerstore: node.PeerHost.Peerstore(),
-		cmdCtx:    ctx,
-		ctx:       node.Context(),
-		broadcast: broadcast,
-		datastore: datastore,
+		host:                node.PeerHost.(host.Host),
+		self:                node.Identity,
+		peerstore:           node.PeerHost.Peerstore(),
+		cmdCtx:              ctx,
+		ctx:                 node.Context(),
+		broadcast:           broadcast,
+		datastore:           datastore,
+		updateFollow:        updateFollow,
+		publishRating:       publishRating,
+		processRefund:       processRefund,
+		processFulfillment:  processFulfillment,
+		processConfirmation: processConfirmation,
 	}
 	node.PeerHost.SetStreamHandler(ProtocolOpenBazaar, OBService.HandleNewStream)
 	log.Infof("OpenBazaar service running at %s", ProtocolOpenBazaar)
@@ -8,7 +8,11 @@ import (
 	"path/filepath"
 	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 	"errors"
+	"io/ioutil"
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/openbazaar-go/repo/db"
 	"github.com/OpenBazaar/openbazaar-go/api"
@@ -16,6 +20,8 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/net/service"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin/bitcoind"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin/libbitcoin"
 	"github.com/OpenBazaar/openbazaar-go/storage/selfhosted"
 	"github.com/OpenBazaar/openbazaar-go/storage/dropbox"
@@ -28,10 +34,12 @@ import (
 	"github.com/op/go-logging"
 	"github.com/natefinch/lumberjack"
 	"github.com/btcsuite/btcd/chaincfg"
+	b32 "github.com/tyler-smith/go-bip32"
         "github.com/mitchellh/go-homedir"
 	"gx/ipfs/QmZy2y8t9zQH2a1b8q2ZSLKp17ATuJoCNxxyMFG5qFExpt/go-net/context"
 	sto "github.com/OpenBazaar/openbazaar-go/storage"
 	lockfile "github.com/ipfs/go-ipfs/repo/fsrepo/lock"
+	"golang.org/x/crypto/ssh/terminal"
 	ipfscore "github.com/ipfs/go-ipfs/core"
 	manet "gx/ipfs/QmUBa4w6CbHJUMeGJPDiMEDWsM93xToK1fTnFXnrC8Hksw/go-multiaddr-net"
 	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
@@ -59,29 +67,97 @@ type Start struct {
 	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
 	Daemon bool `short:"d" long:"daemon" description:"run the server in the background as a daemon"`
 	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
 	LogLevel string `short:"l" long:"loglevel" description:"set the logging level [debug, info, notice, warning, error, critical]"`
 	AllowIP []string `short:"a" long:"allowip" description:"only allow API connections from these IPs"`
 	GatewayPort int `short:"g" long:"gatewayport" description:"set the API port"`
 	STUN bool `short:"s" long:"stun" description:"use stun on µTP IPv4"`
-	PIDFile string `long:"pidfile" description:"name of the PID file if running as daemon"`
+	PIDFile string `long:"pidfile" description:"path of the PID file, defaults to openbazaard.pid in the repo"`
 	Storage string `long:"storage" description:"set the outgoing message storage option [self-hosted, dropbox] default=self-hosted"`
 }
-type Stop struct {}
-type Restart struct {}
-type EncryptDatabase struct {}
-type DecryptDatabase struct {}
+type Init struct {
+	Password string `short:"p" long:"password" description:"the encryption password if the database is to be encrypted"`
+	Mnemonic string `short:"m" long:"mnemonic" description:"restore the node's identity and wallet from this mnemonic seed instead of generating a new one"`
+	Passphrase string `long:"passphrase" description:"an optional BIP39 passphrase used with the mnemonic"`
+	SeedVersion int `long:"seedversion" description:"the key derivation version the restored node was created with" default:"2"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
+type Stop struct {
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+	PIDFile string `long:"pidfile" description:"path of the PID file, defaults to openbazaard.pid in the repo"`
+}
+type Restart struct {
+	Start
+}
+type Status struct {
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+	PIDFile string `long:"pidfile" description:"path of the PID file, defaults to openbazaard.pid in the repo"`
+}
+type Backup struct {
+	Output string `short:"o" long:"output" description:"the file to write the backup archive to" default:"openbazaar.backup"`
+	BackupPassword string `short:"b" long:"backuppassword" description:"the password the backup archive is encrypted with"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
+type Restore struct {
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	Archive string `long:"archive" description:"restore the repo from a backup archive instead of rescanning the wallet"`
+	BackupPassword string `short:"b" long:"backuppassword" description:"the password the backup archive is encrypted with"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
+type EncryptDatabase struct {
+	Password string `short:"p" long:"password" description:"the password to encrypt the database with, prompted for if not set"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
+type DecryptDatabase struct {
+	Password string `short:"p" long:"password" description:"the password the database is encrypted with, prompted for if not set"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
+type ChangePassword struct {
+	Password string `short:"p" long:"password" description:"the current password, prompted for if not set"`
+	NewPassword string `short:"n" long:"newpassword" description:"the new password, prompted for if not set"`
+	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Regtest bool `short:"r" long:"regtest" description:"run in regression test mode with a private network"`
+	DataDir string `long:"datadir" description:"the directory of the repo, defaults to ~/.openbazaar2 with a suffix for the network"`
+}
 
+var initRepo Init
 var startServer Start
 var stopServer Stop
 var restartServer Restart
+var statusServer Status
+var backupRepo Backup
+var restoreWallet Restore
 var encryptDatabase EncryptDatabase
 var decryptDatabase DecryptDatabase
+var changePassword ChangePassword
 
 var parser = flags.NewParser(nil, flags.Default)
 
+// The PID file of the running server. It's removed at shutdown.
+var pidFile string
+
+// How long stop waits for the server to shut down
+const stopTimeout = 30 * time.Second
+
 func main() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func(){
 		for sig := range c {
 			log.Noticef("Received %s\n", sig)
@@ -92,10 +168,17 @@ func main() {
 				os.Remove(repoLockFile)
 				core.Node.IpfsNode.Close()
 			}
+			if pidFile != "" {
+				os.Remove(pidFile)
+			}
 			os.Exit(1)
 		}
 	}()
 
+	parser.AddCommand("init",
+		"initialize a new repo",
+		"The init command creates a new repo. Pass a mnemonic and passphrase to restore an existing node's identity and wallet",
+		&initRepo)
 	parser.AddCommand("start",
 		"start the OpenBazaar-Server",
 		"The start command starts the OpenBazaar-Server",
@@ -108,6 +191,18 @@ func main() {
 		"restart the server",
 		"The restart command shuts down the server and restarts",
 		&restartServer)
+	parser.AddCommand("status",
+		"show the status of the node",
+		"The status command shows whether the server is running, its peer ID, swarm addresses and wallet balance",
+		&statusServer)
+	parser.AddCommand("backup",
+		"back up the repo",
+		"The backup command writes an encrypted archive of the database, config and root directory. The server must be stopped first",
+		&backupRepo)
+	parser.AddCommand("restore",
+		"restore your wallet or repo",
+		"The restore command rebuilds your wallet's keys, transactions and coins from the mnemonic seed by scanning the blockchain. With --archive it instead unpacks a backup into a new repo",
+		&restoreWallet)
 	parser.AddCommand("encryptdatabase",
 		"encrypt your database",
 		"This command encrypts the database containing your bitcoin private keys, identity key, and contracts",
@@ -116,6 +211,10 @@ func main() {
 		"decrypt your database",
 		"This command decrypts the database containing your bitcoin private keys, identity key, and contracts.\n [Warning] doing so may put your bitcoins at risk.",
 		&decryptDatabase)
+	parser.AddCommand("changepassword",
+		"change the database password",
+		"This command changes the password of an encrypted database",
+		&changePassword)
 
 	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
@@ -123,27 +222,256 @@ func main() {
 }
 
 func (x *EncryptDatabase) Execute(args []string) error {
-	return db.Encrypt()
+	sqliteDB, err := openStoppedDatastore(x.Testnet, x.Regtest, x.DataDir, "")
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	password := x.Password
+	if password == "" {
+		password = readPassword("Enter a password: ")
+		if readPassword("Confirm your password: ") != password {
+			return errors.New("Passwords do not match")
+		}
+	}
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("The password must be at least %d characters", minPasswordLength)
+	}
+	if err := sqliteDB.Encrypt(password); err != nil {
+		return err
+	}
+	fmt.Println("Success! You must now run openbazaard start with the --password flag.")
+	return nil
 }
 
 func (x *DecryptDatabase) Execute(args []string) error {
-	return db.Decrypt()
+	password := x.Password
+	if password == "" {
+		password = readPassword("Enter your password: ")
+	}
+	sqliteDB, err := openStoppedDatastore(x.Testnet, x.Regtest, x.DataDir, password)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	if err := sqliteDB.Decrypt(); err != nil {
+		return err
+	}
+	fmt.Println("Success!")
+	return nil
+}
+
+func (x *ChangePassword) Execute(args []string) error {
+	password := x.Password
+	if password == "" {
+		password = readPassword("Enter your current password: ")
+	}
+	sqliteDB, err := openStoppedDatastore(x.Testnet, x.Regtest, x.DataDir, password)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	newPassword := x.NewPassword
+	if newPassword == "" {
+		newPassword = readPassword("Enter a new password: ")
+		if readPassword("Confirm your new password: ") != newPassword {
+			return errors.New("Passwords do not match")
+		}
+	}
+	if len(newPassword) < minPasswordLength {
+		return fmt.Errorf("The password must be at least %d characters", minPasswordLength)
+	}
+	if err := sqliteDB.ChangePassword(newPassword); err != nil {
+		return err
+	}
+	fmt.Println("Success! Use the new password with the --password flag from now on.")
+	return nil
+}
+
+func (x *Init) Execute(args []string) error {
+	params, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+
+	sqliteDB, err := db.Create(expPath, x.Password, params)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	err = repo.DoInit(os.Stdout, expPath, 4096, params, x.Password, x.Mnemonic, x.Passphrase, x.SeedVersion, sqliteDB.Config().Init)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("OpenBazaar repo initialized at %s\n", expPath)
+	if x.Mnemonic != "" {
+		fmt.Println("Run the restore command to recover your wallet's transactions")
+	}
+	return nil
+}
+
+func (x *Stop) Execute(args []string) error {
+	_, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	return stopRunningServer(pidFilePath(expPath, x.PIDFile))
+}
+
+func (x *Restart) Execute(args []string) error {
+	_, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	if err := stopRunningServer(pidFilePath(expPath, x.PIDFile)); err != nil && err != errNotRunning {
+		return err
+	}
+	return x.Start.Execute(args)
+}
+
+func (x *Status) Execute(args []string) error {
+	params, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	if !fsrepo.IsInitialized(expPath) {
+		return errors.New("The repo is not initialized. Run the init command first")
+	}
+	if pid, running := serverPID(pidFilePath(expPath, x.PIDFile)); running {
+		fmt.Printf("Status: running (PID %d)\n", pid)
+	} else {
+		fmt.Println("Status: stopped")
+	}
+
+	sqliteDB, err := db.Create(expPath, x.Password, params)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	if sqliteDB.Config().IsEncrypted() {
+		return encryptedDatabaseError
+	}
+	identityKey, err := sqliteDB.Config().GetIdentityKey()
+	if err != nil {
+		return err
+	}
+	identity, err := ipfs.IdentityFromKey(identityKey)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Peer ID:", identity.PeerID)
+
+	cfg, err := fsrepo.ConfigAt(expPath)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Swarm addresses:")
+	for _, addr := range cfg.Addresses.Swarm {
+		fmt.Println("   ", addr)
+	}
+
+	var confirmed, unconfirmed int
+	for _, u := range sqliteDB.Coins().GetAll() {
+		if height, _ := sqliteDB.Transactions().GetHeight(u.Txid); height > 0 {
+			confirmed += u.Value
+		} else {
+			unconfirmed += u.Value
+		}
+	}
+	fmt.Printf("Balance: %d confirmed, %d unconfirmed\n", confirmed, unconfirmed)
+	fmt.Printf("Unconfirmed transactions: %d\n", len(sqliteDB.Transactions().GetUnconfirmed()))
+	return nil
+}
+
+func (x *Backup) Execute(args []string) error {
+	_, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	if !fsrepo.IsInitialized(expPath) {
+		return errors.New("The repo is not initialized")
+	}
+	if _, err := os.Stat(filepath.Join(expPath, lockfile.LockFile)); !os.IsNotExist(err) {
+		return errors.New("Cannot back up while the server is running")
+	}
+	password := x.BackupPassword
+	if password == "" {
+		password = readPassword("Enter a password for the backup: ")
+		if readPassword("Confirm the password: ") != password {
+			return errors.New("Passwords do not match")
+		}
+	}
+	if password == "" {
+		return errors.New("A backup password is required")
+	}
+	f, err := os.OpenFile(x.Output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if err := repo.Backup(expPath, f, password); err != nil {
+		f.Close()
+		os.Remove(x.Output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Backup written to %s\n", x.Output)
+	return nil
+}
+
+func (x *Restore) Execute(args []string) error {
+	params, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	if x.Archive != "" {
+		return restoreBackup(x, expPath, params)
+	}
+
+	sqliteDB, err := db.Create(expPath, x.Password, params)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	if sqliteDB.Config().IsEncrypted() {
+		return encryptedDatabaseError
+	}
+	if err := sqliteDB.Migrate(); err != nil {
+		return err
+	}
+	wallet, err := newWallet(expPath, sqliteDB, params)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if err := wallet.Rescan(); err != nil {
+		log.Error(err)
+		return err
+	}
+	unconfirmed, confirmed := wallet.GetBalance()
+	fmt.Printf("Wallet restored. Confirmed balance: %d, unconfirmed balance: %d\n", confirmed, unconfirmed)
+	return nil
 }
 
 func (x *Start) Execute(args []string) error {
 	printSplashScreen()
 
 	// set repo path
-	var repoPath string
-	if x.Testnet {
-		repoPath = "~/.openbazaar2-testnet"
-	} else {
-		repoPath = "~/.openbazaar2"
+	params, repoPath, err := getNetwork(x.Testnet, x.Regtest, x.DataDir)
+	if err != nil {
+		return err
 	}
 	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
 
 	// Database
-	sqliteDB, err := db.Create(expPath, x.Password, x.Testnet)
+	sqliteDB, err := db.Create(expPath, x.Password, params)
 	if err != nil {
 		return err
 	}
@@ -171,7 +499,7 @@ func (x *Start) Execute(args []string) error {
 	ipfslogging.Output(w2)()
 
 	// initalize the ipfs repo if it doesn't already exist
-	err = repo.DoInit(os.Stdout, expPath, 4096, x.Testnet, x.Password, sqliteDB.Config().Init)
+	err = repo.DoInit(os.Stdout, expPath, 4096, params, x.Password, "", "", repo.CurrentSeedVersion, sqliteDB.Config().Init)
 	if err != nil && err != repo.ErrRepoExists{
 		log.Error(err)
 		return err
@@ -182,8 +510,21 @@ func (x *Start) Execute(args []string) error {
 		return encryptedDatabaseError
 	}
 
+	if err := sqliteDB.Migrate(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	// Write the PID file used by the stop and restart commands
+	pidFile = pidFilePath(expPath, x.PIDFile)
+	if err := ioutil.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		log.Error(err)
+		return err
+	}
+	defer os.Remove(pidFile)
+
 	// ipfs node setup
-	r, err := fsrepo.Open(repoPath)
+	r, err := fsrepo.Open(expPath)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -258,38 +599,11 @@ func (x *Start) Execute(args []string) error {
 	proto.Unmarshal(dhtrec.GetValue(), e)
 
 	// Wallet
-	mn, err := sqliteDB.Config().GetMnemonic()
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	var params chaincfg.Params
-	if !x.Testnet {
-		params = chaincfg.MainNetParams
-	} else {
-		params = chaincfg.TestNet3Params
-	}
-	libbitcoinServers, err := repo.GetLibbitcoinServers(path.Join(expPath, "config"))
+	wallet, err := newWallet(expPath, sqliteDB, params)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-	maxFee, err := repo.GetMaxFee(path.Join(expPath, "config"))
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	feeApi, err := repo.GetFeeAPI(path.Join(expPath, "config"))
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	low, medium, high, err := repo.GetDefaultFees(path.Join(expPath, "config"))
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	wallet := libbitcoin.NewLibbitcoinWallet(mn, &params, sqliteDB, libbitcoinServers, maxFee, low, medium, high, feeApi)
 
 	// Offline messaging storage
 	var storage sto.OfflineMessagingStorage
@@ -343,7 +657,7 @@ func (x *Start) Execute(args []string) error {
 	// FIXME: There has to be a better way
 	for b := range cb {
 		if b == true {
-			OBService := service.SetupOpenBazaarService(nd, core.Node.Broadcast, ctx, sqliteDB)
+			OBService := service.SetupOpenBazaarService(nd, core.Node.Broadcast, ctx, sqliteDB, core.Node.UpdateFollow, core.Node.PublishRating, core.Node.ProcessRefund, core.Node.ProcessFulfillment, core.Node.ProcessOrderConfirmation)
 			core.Node.Service = OBService
 			MR := net.NewMessageRetriever(sqliteDB, ctx, nd, OBService, 16, core.Node.SendOfflineAck)
 			go MR.Run()
@@ -351,6 +665,17 @@ func (x *Start) Execute(args []string) error {
 			PR := net.NewPointerRepublisher(nd, sqliteDB)
 			go PR.Run()
 			core.Node.PointerRepublisher = PR
+			FA := net.NewFeedAggregator(sqliteDB, ctx)
+			go FA.Run()
+			core.Node.FeedAggregator = FA
+			SI := net.NewSearchIndexer(sqliteDB, ctx)
+			go SI.Run()
+			core.Node.SearchIndexer = SI
+			if w, ok := wallet.(*libbitcoin.LibbitcoinWallet); ok {
+				w.AddReorgListener(func(forkHeight int) {
+					core.Node.Broadcast <- []byte(`{"notification": {"reorg":` + strconv.Itoa(forkHeight) + `}}`)
+				})
+			}
 		}
 		break
 	}
@@ -362,6 +687,241 @@ func (x *Start) Execute(args []string) error {
 	return nil
 }
 
+// Unpack a backup archive into a new repo and initialize its IPNS keyspace
+func restoreBackup(x *Restore, expPath string, params *chaincfg.Params) error {
+	password := x.BackupPassword
+	if password == "" {
+		password = readPassword("Enter the backup password: ")
+	}
+	f, err := os.Open(x.Archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := repo.RestoreBackup(f, expPath, password); err != nil {
+		return err
+	}
+	sqliteDB, err := db.Create(expPath, x.Password, params)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	if sqliteDB.Config().IsEncrypted() {
+		return encryptedDatabaseError
+	}
+	identityKey, err := sqliteDB.Config().GetIdentityKey()
+	if err != nil {
+		return err
+	}
+	if err := repo.InitializeIpnsKeyspace(expPath, identityKey); err != nil {
+		return err
+	}
+	fmt.Printf("Repo restored to %s. Your store is republished the next time it is edited\n", expPath)
+	return nil
+}
+
+var errNotRunning = errors.New("OpenBazaar-Server is not running")
+
+// The shortest password accepted for database encryption
+const minPasswordLength = 8
+
+// Open the database of a repo whose server isn't running. If the database is
+// encrypted it must be opened with the right password.
+func openStoppedDatastore(testnet bool, regtest bool, dataDir string, password string) (*db.SQLiteDatastore, error) {
+	params, repoPath, err := getNetwork(testnet, regtest, dataDir)
+	if err != nil {
+		return nil, err
+	}
+	expPath, _ := homedir.Expand(filepath.Clean(repoPath))
+	if _, err := os.Stat(filepath.Join(expPath, lockfile.LockFile)); !os.IsNotExist(err) {
+		return nil, errors.New("Cannot change the database while the server is running")
+	}
+	if _, err := os.Stat(expPath); os.IsNotExist(err) {
+		return nil, errors.New("Database does not exist. You may need to run the init command first")
+	}
+	sqliteDB, err := db.Create(expPath, password, params)
+	if err != nil {
+		return nil, err
+	}
+	if sqliteDB.Config().IsEncrypted() {
+		sqliteDB.Close()
+		if password == "" {
+			return nil, encryptedDatabaseError
+		}
+		return nil, errors.New("Invalid password")
+	}
+	return sqliteDB, nil
+}
+
+func pidFilePath(expPath string, flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return path.Join(expPath, "openbazaard.pid")
+}
+
+// Return the PID in the PID file and whether that process is running
+func serverPID(pidPath string) (int, bool) {
+	b, err := ioutil.ReadFile(pidPath)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return pid, false
+	}
+	return pid, p.Signal(syscall.Signal(0)) == nil
+}
+
+// Ask the server in the PID file to shut down and wait for it to remove the PID file
+func stopRunningServer(pidPath string) error {
+	pid, running := serverPID(pidPath)
+	if !running {
+		// Remove a PID file left behind by a server that didn't shut down cleanly
+		os.Remove(pidPath)
+		return errNotRunning
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := p.Signal(syscall.SIGTERM); err != nil {
+		return err
+	}
+	fmt.Println("Waiting for OpenBazaar-Server to shut down...")
+	for start := time.Now(); time.Since(start) < stopTimeout; time.Sleep(100 * time.Millisecond) {
+		if _, err := os.Stat(pidPath); os.IsNotExist(err) {
+			fmt.Println("OpenBazaar-Server stopped")
+			return nil
+		}
+	}
+	return errors.New("Timed out waiting for OpenBazaar-Server to shut down")
+}
+
+func readPassword(prompt string) string {
+	fmt.Print(prompt)
+	bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
+	fmt.Println("")
+	return string(bytePassword)
+}
+
+// getNetwork returns the bitcoin network parameters and repo path for the
+// network selected on the command line
+func getNetwork(testnet bool, regtest bool, dataDir string) (*chaincfg.Params, string, error) {
+	var params *chaincfg.Params
+	var repoPath string
+	switch {
+	case testnet && regtest:
+		return nil, "", errors.New("Invalid combination of testnet and regtest modes")
+	case testnet:
+		params, repoPath = &chaincfg.TestNet3Params, "~/.openbazaar2-testnet"
+	case regtest:
+		params, repoPath = &chaincfg.RegressionNetParams, "~/.openbazaar2-regtest"
+	default:
+		params, repoPath = &chaincfg.MainNetParams, "~/.openbazaar2"
+	}
+	if dataDir != "" {
+		repoPath = dataDir
+	}
+	return params, repoPath, nil
+}
+
+// newWallet creates the wallet backend selected in the config file using the
+// seed in the database and the wallet settings in the config file
+func newWallet(expPath string, sqliteDB *db.SQLiteDatastore, params *chaincfg.Params) (bitcoin.BitcoinWallet, error) {
+	mn, err := sqliteDB.Config().GetMnemonic()
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := sqliteDB.Config().GetPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	seedVersion, err := sqliteDB.Config().GetSeedVersion()
+	if err != nil {
+		return nil, err
+	}
+	seed, err := repo.WalletSeed(mn, passphrase, seedVersion)
+	if err != nil {
+		return nil, err
+	}
+	maxFee, err := repo.GetMaxFee(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	low, medium, high, err := repo.GetDefaultFees(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	gapLimit, err := repo.GetGapLimit(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	if gapLimit <= 0 {
+		gapLimit = libbitcoin.DefaultGapLimit
+	}
+	xpub, err := repo.GetWatchOnlyXpub(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	var watchOnlyKey *b32.Key
+	if xpub != "" {
+		watchOnlyKey, err = b32.B58Deserialize(xpub)
+		if err != nil {
+			return nil, err
+		}
+		if watchOnlyKey.IsPrivate || watchOnlyKey.Depth != 1 {
+			return nil, errors.New("The watch-only key must be the extended public key of account m/0'")
+		}
+	}
+	walletType, err := repo.GetWalletType(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	switch walletType {
+	case "", "libbitcoin":
+	case "bitcoind":
+		host, user, password, err := repo.GetBitcoindConfig(path.Join(expPath, "config"))
+		if err != nil {
+			return nil, err
+		}
+		return bitcoind.NewBitcoindWallet(seed, params, sqliteDB, host, user, password, maxFee, low, medium, high, gapLimit, watchOnlyKey)
+	default:
+		return nil, fmt.Errorf("Unknown wallet type %s", walletType)
+	}
+
+	libbitcoinServers, err := repo.GetLibbitcoinServers(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	feeApi, err := repo.GetFeeAPI(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	coinSelection, err := repo.GetCoinSelection(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := libbitcoin.ParseCoinSelectionStrategy(coinSelection)
+	if err != nil {
+		return nil, err
+	}
+	addressType, err := repo.GetAddressType(path.Join(expPath, "config"))
+	if err != nil {
+		return nil, err
+	}
+	addrType, err := libbitcoin.ParseAddressType(addressType)
+	if err != nil {
+		return nil, err
+	}
+	return libbitcoin.NewLibbitcoinWallet(seed, params, sqliteDB, libbitcoinServers, maxFee, low, medium, high, feeApi,
+		strategy, addrType, gapLimit, watchOnlyKey), nil
+}
+
 // printSwarmAddrs prints the addresses of the host
 func printSwarmAddrs(node *ipfscore.IpfsNode) {
 	var addrs []string
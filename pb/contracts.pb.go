@@ -90,6 +90,35 @@ func (Listing_Metadata_Category) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor1, []int{1, 0, 1}
 }
 
+type Listing_Item_StockLevel int32
+
+const (
+	Listing_Item_UNTRACKED    Listing_Item_StockLevel = 0
+	Listing_Item_IN_STOCK     Listing_Item_StockLevel = 1
+	Listing_Item_LOW_STOCK    Listing_Item_StockLevel = 2
+	Listing_Item_OUT_OF_STOCK Listing_Item_StockLevel = 3
+)
+
+var Listing_Item_StockLevel_name = map[int32]string{
+	0: "UNTRACKED",
+	1: "IN_STOCK",
+	2: "LOW_STOCK",
+	3: "OUT_OF_STOCK",
+}
+var Listing_Item_StockLevel_value = map[string]int32{
+	"UNTRACKED":    0,
+	"IN_STOCK":     1,
+	"LOW_STOCK":    2,
+	"OUT_OF_STOCK": 3,
+}
+
+func (x Listing_Item_StockLevel) String() string {
+	return proto.EnumName(Listing_Item_StockLevel_name, int32(x))
+}
+func (Listing_Item_StockLevel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor1, []int{1, 1, 0}
+}
+
 type Order_Payment_Method int32
 
 const (
@@ -122,6 +151,7 @@ const (
 	Signatures_DISPUTE            Signatures_Section = 5
 	Signatures_DISPUTE_RESOLUTION Signatures_Section = 6
 	Signatures_REFUND             Signatures_Section = 7
+	Signatures_ORDER_FULFILLMENT  Signatures_Section = 8
 )
 
 var Signatures_Section_name = map[int32]string{
@@ -133,6 +163,7 @@ var Signatures_Section_name = map[int32]string{
 	5: "DISPUTE",
 	6: "DISPUTE_RESOLUTION",
 	7: "REFUND",
+	8: "ORDER_FULFILLMENT",
 }
 var Signatures_Section_value = map[string]int32{
 	"NA":                 0,
@@ -143,17 +174,19 @@ var Signatures_Section_value = map[string]int32{
 	"DISPUTE":            5,
 	"DISPUTE_RESOLUTION": 6,
 	"REFUND":             7,
+	"ORDER_FULFILLMENT":  8,
 }
 
 func (x Signatures_Section) String() string {
 	return proto.EnumName(Signatures_Section_name, int32(x))
 }
-func (Signatures_Section) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{9, 0} }
+func (Signatures_Section) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{10, 0} }
 
 type RicardianContract struct {
 	VendorListings          []*Listing         `protobuf:"bytes,1,rep,name=vendorListings" json:"vendorListings,omitempty"`
 	BuyerOrder              *Order             `protobuf:"bytes,2,opt,name=buyerOrder" json:"buyerOrder,omitempty"`
 	VendorOrderConfirmation *OrderConfirmation `protobuf:"bytes,3,opt,name=vendorOrderConfirmation" json:"vendorOrderConfirmation,omitempty"`
+	VendorOrderFulfillment  *OrderFulfillment  `protobuf:"bytes,9,opt,name=vendorOrderFulfillment" json:"vendorOrderFulfillment,omitempty"`
 	BuyerRating             *Rating            `protobuf:"bytes,4,opt,name=buyerRating" json:"buyerRating,omitempty"`
 	Dispute                 *Dispute           `protobuf:"bytes,5,opt,name=dispute" json:"dispute,omitempty"`
 	DisputeResolution       *DisputeResolution `protobuf:"bytes,6,opt,name=disputeResolution" json:"disputeResolution,omitempty"`
@@ -187,6 +220,13 @@ func (m *RicardianContract) GetVendorOrderConfirmation() *OrderConfirmation {
 	return nil
 }
 
+func (m *RicardianContract) GetVendorOrderFulfillment() *OrderFulfillment {
+	if m != nil {
+		return m.VendorOrderFulfillment
+	}
+	return nil
+}
+
 func (m *RicardianContract) GetBuyerRating() *Rating {
 	if m != nil {
 		return m.BuyerRating
@@ -279,16 +319,17 @@ func (*Listing_Metadata) ProtoMessage()               {}
 func (*Listing_Metadata) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1, 0} }
 
 type Listing_Item struct {
-	Title          string                 `protobuf:"bytes,1,opt,name=title" json:"title,omitempty"`
-	Description    string                 `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
-	ProcessingTime string                 `protobuf:"bytes,3,opt,name=processingTime" json:"processingTime,omitempty"`
-	PricePerUnit   *Listing_Price         `protobuf:"bytes,4,opt,name=pricePerUnit" json:"pricePerUnit,omitempty"`
-	Nsfw           bool                   `protobuf:"varint,5,opt,name=nsfw" json:"nsfw,omitempty"`
-	Tags           []string               `protobuf:"bytes,6,rep,name=tags" json:"tags,omitempty"`
-	ImageHashes    []string               `protobuf:"bytes,7,rep,name=imageHashes" json:"imageHashes,omitempty"`
-	SKU            string                 `protobuf:"bytes,8,opt,name=SKU,json=sKU" json:"SKU,omitempty"`
-	Condition      string                 `protobuf:"bytes,9,opt,name=condition" json:"condition,omitempty"`
-	Options        []*Listing_Item_Option `protobuf:"bytes,10,rep,name=options" json:"options,omitempty"`
+	Title          string                  `protobuf:"bytes,1,opt,name=title" json:"title,omitempty"`
+	Description    string                  `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
+	ProcessingTime string                  `protobuf:"bytes,3,opt,name=processingTime" json:"processingTime,omitempty"`
+	PricePerUnit   *Listing_Price          `protobuf:"bytes,4,opt,name=pricePerUnit" json:"pricePerUnit,omitempty"`
+	Nsfw           bool                    `protobuf:"varint,5,opt,name=nsfw" json:"nsfw,omitempty"`
+	Tags           []string                `protobuf:"bytes,6,rep,name=tags" json:"tags,omitempty"`
+	ImageHashes    []string                `protobuf:"bytes,7,rep,name=imageHashes" json:"imageHashes,omitempty"`
+	SKU            string                  `protobuf:"bytes,8,opt,name=SKU,json=sKU" json:"SKU,omitempty"`
+	Condition      string                  `protobuf:"bytes,9,opt,name=condition" json:"condition,omitempty"`
+	Options        []*Listing_Item_Option  `protobuf:"bytes,10,rep,name=options" json:"options,omitempty"`
+	Stock          Listing_Item_StockLevel `protobuf:"varint,11,opt,name=stock,enum=Listing_Item_StockLevel" json:"stock,omitempty"`
 }
 
 func (m *Listing_Item) Reset()                    { *m = Listing_Item{} }
@@ -492,8 +533,9 @@ func (m *Order_Payment) String() string            { return proto.CompactTextStr
 func (*Order_Payment) ProtoMessage()               {}
 func (*Order_Payment) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2, 2} }
 
-// TODO: complete other messages
 type OrderConfirmation struct {
+	OrderHash []byte `protobuf:"bytes,1,opt,name=orderHash,proto3" json:"orderHash,omitempty"`
+	Timestamp uint64 `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
 }
 
 func (m *OrderConfirmation) Reset()                    { *m = OrderConfirmation{} }
@@ -501,21 +543,75 @@ func (m *OrderConfirmation) String() string            { return proto.CompactTex
 func (*OrderConfirmation) ProtoMessage()               {}
 func (*OrderConfirmation) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
 
+type OrderFulfillment struct {
+	OrderHash []byte                     `protobuf:"bytes,1,opt,name=orderHash,proto3" json:"orderHash,omitempty"`
+	Timestamp uint64                     `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Physical  *OrderFulfillment_Physical `protobuf:"bytes,3,opt,name=physical" json:"physical,omitempty"`
+	Digital   *OrderFulfillment_Digital  `protobuf:"bytes,4,opt,name=digital" json:"digital,omitempty"`
+	Note      string                     `protobuf:"bytes,5,opt,name=note" json:"note,omitempty"`
+}
+
+func (m *OrderFulfillment) Reset()                    { *m = OrderFulfillment{} }
+func (m *OrderFulfillment) String() string            { return proto.CompactTextString(m) }
+func (*OrderFulfillment) ProtoMessage()               {}
+func (*OrderFulfillment) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func (m *OrderFulfillment) GetPhysical() *OrderFulfillment_Physical {
+	if m != nil {
+		return m.Physical
+	}
+	return nil
+}
+
+func (m *OrderFulfillment) GetDigital() *OrderFulfillment_Digital {
+	if m != nil {
+		return m.Digital
+	}
+	return nil
+}
+
+type OrderFulfillment_Physical struct {
+	Carrier        string `protobuf:"bytes,1,opt,name=carrier" json:"carrier,omitempty"`
+	TrackingNumber string `protobuf:"bytes,2,opt,name=trackingNumber" json:"trackingNumber,omitempty"`
+}
+
+func (m *OrderFulfillment_Physical) Reset()                    { *m = OrderFulfillment_Physical{} }
+func (m *OrderFulfillment_Physical) String() string            { return proto.CompactTextString(m) }
+func (*OrderFulfillment_Physical) ProtoMessage()               {}
+func (*OrderFulfillment_Physical) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4, 0} }
+
+type OrderFulfillment_Digital struct {
+	Url      string `protobuf:"bytes,1,opt,name=url" json:"url,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *OrderFulfillment_Digital) Reset()                    { *m = OrderFulfillment_Digital{} }
+func (m *OrderFulfillment_Digital) String() string            { return proto.CompactTextString(m) }
+func (*OrderFulfillment_Digital) ProtoMessage()               {}
+func (*OrderFulfillment_Digital) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4, 1} }
+
 type Rating struct {
+	OrderHash     []byte `protobuf:"bytes,1,opt,name=orderHash,proto3" json:"orderHash,omitempty"`
+	Quality       uint32 `protobuf:"varint,2,opt,name=quality" json:"quality,omitempty"`
+	Delivery      uint32 `protobuf:"varint,3,opt,name=delivery" json:"delivery,omitempty"`
+	Communication uint32 `protobuf:"varint,4,opt,name=communication" json:"communication,omitempty"`
+	Review        string `protobuf:"bytes,5,opt,name=review" json:"review,omitempty"`
+	Timestamp     uint64 `protobuf:"varint,6,opt,name=timestamp" json:"timestamp,omitempty"`
 }
 
 func (m *Rating) Reset()                    { *m = Rating{} }
 func (m *Rating) String() string            { return proto.CompactTextString(m) }
 func (*Rating) ProtoMessage()               {}
-func (*Rating) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*Rating) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
 
+// TODO: complete other messages
 type Dispute struct {
 }
 
 func (m *Dispute) Reset()                    { *m = Dispute{} }
 func (m *Dispute) String() string            { return proto.CompactTextString(m) }
 func (*Dispute) ProtoMessage()               {}
-func (*Dispute) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+func (*Dispute) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
 
 type DisputeResolution struct {
 }
@@ -523,15 +619,22 @@ type DisputeResolution struct {
 func (m *DisputeResolution) Reset()                    { *m = DisputeResolution{} }
 func (m *DisputeResolution) String() string            { return proto.CompactTextString(m) }
 func (*DisputeResolution) ProtoMessage()               {}
-func (*DisputeResolution) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+func (*DisputeResolution) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
 
 type Refund struct {
+	OrderHash         []byte   `protobuf:"bytes,1,opt,name=orderHash,proto3" json:"orderHash,omitempty"`
+	Amount            uint64   `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	Memo              string   `protobuf:"bytes,3,opt,name=memo" json:"memo,omitempty"`
+	Timestamp         uint64   `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	Txid              string   `protobuf:"bytes,5,opt,name=txid" json:"txid,omitempty"`
+	ReleaseTx         []byte   `protobuf:"bytes,6,opt,name=releaseTx,proto3" json:"releaseTx,omitempty"`
+	ReleaseSignatures [][]byte `protobuf:"bytes,7,rep,name=releaseSignatures,proto3" json:"releaseSignatures,omitempty"`
 }
 
 func (m *Refund) Reset()                    { *m = Refund{} }
 func (m *Refund) String() string            { return proto.CompactTextString(m) }
 func (*Refund) ProtoMessage()               {}
-func (*Refund) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+func (*Refund) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
 
 type ID struct {
 	Guid         string      `protobuf:"bytes,1,opt,name=guid" json:"guid,omitempty"`
@@ -542,7 +645,7 @@ type ID struct {
 func (m *ID) Reset()                    { *m = ID{} }
 func (m *ID) String() string            { return proto.CompactTextString(m) }
 func (*ID) ProtoMessage()               {}
-func (*ID) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+func (*ID) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9} }
 
 func (m *ID) GetPubkeys() *ID_Pubkeys {
 	if m != nil {
@@ -559,7 +662,7 @@ type ID_Pubkeys struct {
 func (m *ID_Pubkeys) Reset()                    { *m = ID_Pubkeys{} }
 func (m *ID_Pubkeys) String() string            { return proto.CompactTextString(m) }
 func (*ID_Pubkeys) ProtoMessage()               {}
-func (*ID_Pubkeys) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8, 0} }
+func (*ID_Pubkeys) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9, 0} }
 
 type Signatures struct {
 	Section Signatures_Section `protobuf:"varint,1,opt,name=section,enum=Signatures_Section" json:"section,omitempty"`
@@ -570,7 +673,7 @@ type Signatures struct {
 func (m *Signatures) Reset()                    { *m = Signatures{} }
 func (m *Signatures) String() string            { return proto.CompactTextString(m) }
 func (*Signatures) ProtoMessage()               {}
-func (*Signatures) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9} }
+func (*Signatures) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{10} }
 
 func init() {
 	proto.RegisterType((*RicardianContract)(nil), "RicardianContract")
@@ -588,6 +691,9 @@ func init() {
 	proto.RegisterType((*Order_Item_Option)(nil), "Order.Item.Option")
 	proto.RegisterType((*Order_Payment)(nil), "Order.Payment")
 	proto.RegisterType((*OrderConfirmation)(nil), "OrderConfirmation")
+	proto.RegisterType((*OrderFulfillment)(nil), "OrderFulfillment")
+	proto.RegisterType((*OrderFulfillment_Physical)(nil), "OrderFulfillment.Physical")
+	proto.RegisterType((*OrderFulfillment_Digital)(nil), "OrderFulfillment.Digital")
 	proto.RegisterType((*Rating)(nil), "Rating")
 	proto.RegisterType((*Dispute)(nil), "Dispute")
 	proto.RegisterType((*DisputeResolution)(nil), "DisputeResolution")
@@ -597,102 +703,126 @@ func init() {
 	proto.RegisterType((*Signatures)(nil), "Signatures")
 	proto.RegisterEnum("Listing_Metadata_CategorySub", Listing_Metadata_CategorySub_name, Listing_Metadata_CategorySub_value)
 	proto.RegisterEnum("Listing_Metadata_Category", Listing_Metadata_Category_name, Listing_Metadata_Category_value)
+	proto.RegisterEnum("Listing_Item_StockLevel", Listing_Item_StockLevel_name, Listing_Item_StockLevel_value)
 	proto.RegisterEnum("Order_Payment_Method", Order_Payment_Method_name, Order_Payment_Method_value)
 	proto.RegisterEnum("Signatures_Section", Signatures_Section_name, Signatures_Section_value)
 }
 
 var fileDescriptor1 = []byte{
-	// 1466 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x57, 0x4b, 0x93, 0xdb, 0xc4,
-	0x16, 0xbe, 0xb6, 0xfc, 0x3c, 0x9e, 0xf1, 0xcc, 0x74, 0x72, 0x73, 0x5d, 0xae, 0x1b, 0x08, 0x0e,
-	0x8f, 0xf0, 0x52, 0x51, 0x13, 0x0a, 0x96, 0xc4, 0x65, 0x7b, 0x12, 0x55, 0x26, 0xb6, 0x69, 0xdb,
-	0x01, 0x56, 0x53, 0x1a, 0xa9, 0xc7, 0xd3, 0x15, 0x5b, 0x32, 0x92, 0x1c, 0xf0, 0x86, 0x15, 0x4b,
-	0x7e, 0x01, 0x95, 0x35, 0xcb, 0x14, 0x1b, 0x7e, 0x14, 0x2b, 0xfe, 0x02, 0xa7, 0x4f, 0xb7, 0x6c,
-	0xd9, 0x9e, 0x50, 0xec, 0xfa, 0xbc, 0xd4, 0xa7, 0xbf, 0xf3, 0x14, 0x1c, 0x79, 0x61, 0x90, 0x44,
-	0xae, 0x97, 0xc4, 0xf6, 0x22, 0x0a, 0x93, 0xb0, 0xc9, 0xbc, 0x70, 0x89, 0x9c, 0x95, 0x17, 0xfa,
-	0xc2, 0xf0, 0x5a, 0xbf, 0x5a, 0x70, 0xc2, 0xa5, 0xe7, 0x46, 0xbe, 0x74, 0x83, 0x8e, 0x31, 0x60,
-	0x9f, 0x41, 0xfd, 0xa5, 0x08, 0xfc, 0x30, 0x3a, 0x97, 0x71, 0x22, 0x83, 0x69, 0xdc, 0xc8, 0xdd,
-	0xb3, 0x1e, 0xd4, 0x4e, 0x2b, 0xb6, 0x61, 0xf0, 0x1d, 0x39, 0x7b, 0x1f, 0xe0, 0x72, 0xb9, 0x12,
-	0xd1, 0x20, 0xf2, 0x45, 0xd4, 0xc8, 0xdf, 0xcb, 0xa1, 0x76, 0xc9, 0x26, 0x8a, 0x67, 0x24, 0xec,
-	0x1c, 0xfe, 0xa7, 0x2d, 0x89, 0xc4, 0x0b, 0xaf, 0x64, 0x34, 0x77, 0x13, 0x19, 0x06, 0x0d, 0x8b,
-	0x8c, 0x98, 0xbd, 0x27, 0xe1, 0x6f, 0x32, 0x61, 0x1f, 0x42, 0x8d, 0xbe, 0xcd, 0x5d, 0xe5, 0x45,
-	0xa3, 0x40, 0x5f, 0x28, 0xdb, 0x9a, 0xe4, 0x59, 0x19, 0x6b, 0x41, 0xd9, 0x97, 0xf1, 0x62, 0x99,
-	0x88, 0x46, 0x91, 0xd4, 0x2a, 0x76, 0x57, 0xd3, 0x3c, 0x15, 0xb0, 0x47, 0x70, 0x62, 0x8e, 0x5c,
-	0xc4, 0xe1, 0x6c, 0x49, 0x6e, 0x95, 0x8c, 0x5b, 0xdd, 0x5d, 0x09, 0xdf, 0x57, 0x66, 0x6f, 0x43,
-	0x29, 0x12, 0x57, 0xcb, 0xc0, 0x6f, 0x94, 0x53, 0x5f, 0x88, 0xe4, 0x86, 0xcd, 0x3e, 0x06, 0x88,
-	0xe5, 0x34, 0x70, 0x93, 0x65, 0x24, 0xe2, 0x46, 0x85, 0x50, 0xad, 0xd9, 0xa3, 0x35, 0x8b, 0x67,
-	0xc4, 0xad, 0xd7, 0x07, 0x50, 0x36, 0x08, 0xb3, 0x7b, 0x50, 0x9b, 0xe9, 0x63, 0xdf, 0x9d, 0x0b,
-	0x8c, 0x47, 0xee, 0x41, 0x95, 0x67, 0x59, 0x78, 0x77, 0x45, 0xe3, 0xe4, 0x74, 0x4d, 0x00, 0x2c,
-	0xdb, 0xe9, 0xf2, 0x35, 0x93, 0x7d, 0x0a, 0x95, 0xb9, 0x48, 0x5c, 0xdf, 0x4d, 0x5c, 0x03, 0xf6,
-	0x49, 0x1a, 0x4f, 0xfb, 0x99, 0x11, 0xf0, 0xb5, 0x0a, 0x7b, 0x07, 0x0a, 0x32, 0x11, 0x73, 0x83,
-	0xea, 0xe1, 0x5a, 0xd5, 0x41, 0x26, 0x27, 0x91, 0xfa, 0x62, 0x7c, 0x2d, 0x17, 0x0b, 0x05, 0x7e,
-	0x71, 0xe7, 0x8b, 0x23, 0x23, 0xe0, 0x6b, 0x15, 0xf6, 0x16, 0xc0, 0x1c, 0x73, 0x2f, 0x72, 0x93,
-	0x30, 0x8a, 0x11, 0x58, 0x0b, 0x9f, 0x90, 0xe1, 0x30, 0x1b, 0x58, 0x22, 0xa2, 0x79, 0xdc, 0x0e,
-	0x7c, 0x0c, 0xb3, 0x2f, 0x15, 0xa4, 0x31, 0x21, 0x59, 0xe5, 0x37, 0x48, 0x30, 0xa6, 0x07, 0x1a,
-	0xd6, 0x61, 0x38, 0x93, 0xde, 0x0a, 0xe1, 0x54, 0x9a, 0x5b, 0xbc, 0xe6, 0x1f, 0x79, 0xa8, 0xa4,
-	0x8f, 0x63, 0x0d, 0x28, 0xbf, 0x14, 0x51, 0xac, 0xc2, 0xaa, 0x00, 0x3c, 0xe4, 0x29, 0xc9, 0xbe,
-	0x80, 0x8a, 0xe7, 0x26, 0x62, 0x1a, 0x46, 0x2b, 0x02, 0xaf, 0x7e, 0xda, 0xdc, 0xc3, 0xc6, 0xee,
-	0x18, 0x0d, 0xbe, 0xd6, 0x65, 0x5f, 0x41, 0x2d, 0x3d, 0x8f, 0x96, 0x97, 0x04, 0x6b, 0xfd, 0xf4,
-	0xee, 0x9b, 0x4d, 0x51, 0x89, 0x67, 0x2d, 0xd8, 0x1d, 0x28, 0x89, 0x1f, 0x17, 0x12, 0xaf, 0x55,
-	0x38, 0x17, 0xb8, 0xa1, 0x5a, 0x0f, 0xa1, 0x96, 0xb1, 0x61, 0x25, 0xc8, 0xf7, 0xdb, 0xc7, 0xff,
-	0x61, 0x47, 0x50, 0x3b, 0x73, 0xbe, 0xed, 0x75, 0x2f, 0x86, 0xdc, 0xe9, 0xf4, 0x8e, 0x73, 0xac,
-	0x06, 0xe5, 0xf6, 0xa4, 0x33, 0x76, 0x06, 0xfd, 0xe3, 0x7c, 0xcb, 0x81, 0x4a, 0x6a, 0xa4, 0x04,
-	0x93, 0xfe, 0xd3, 0xfe, 0xe0, 0x9b, 0x3e, 0x9a, 0x9d, 0xc0, 0xe1, 0xf0, 0xc9, 0x77, 0x23, 0xa7,
-	0xd3, 0x3e, 0xbf, 0x78, 0x3c, 0x18, 0x74, 0xd1, 0xf0, 0x18, 0x0e, 0xba, 0xce, 0x63, 0x67, 0x9c,
-	0x72, 0xf2, 0xca, 0x62, 0xd4, 0xe3, 0xcf, 0xd5, 0x77, 0xad, 0xe6, 0x2b, 0x0b, 0x0a, 0x2a, 0xd2,
-	0xec, 0x36, 0x14, 0x13, 0x99, 0xcc, 0xd2, 0x94, 0xd3, 0x84, 0x4a, 0x47, 0x6c, 0x22, 0x5e, 0x24,
-	0x17, 0x54, 0x24, 0x79, 0x9d, 0x8e, 0x19, 0x16, 0x76, 0x84, 0x3a, 0xb6, 0x18, 0x4f, 0xc4, 0x31,
-	0x02, 0x31, 0x96, 0x98, 0xb3, 0x16, 0x29, 0xed, 0x70, 0xd9, 0x29, 0x1c, 0x2c, 0x22, 0xe9, 0x89,
-	0xa1, 0x88, 0x26, 0x81, 0x4c, 0x4c, 0xba, 0xd5, 0xd7, 0x10, 0x0e, 0x95, 0x90, 0x6f, 0xe9, 0x30,
-	0x06, 0x85, 0x20, 0xbe, 0xfa, 0x81, 0x72, 0xae, 0xc2, 0xe9, 0xac, 0x78, 0x89, 0x3b, 0x4d, 0xd3,
-	0x8a, 0xce, 0xca, 0x4b, 0x39, 0x77, 0xa7, 0xe2, 0x89, 0x1b, 0x5f, 0x0b, 0x95, 0x49, 0x4a, 0x94,
-	0x65, 0x21, 0x0a, 0xd6, 0xe8, 0xe9, 0xc4, 0x64, 0x8e, 0x15, 0x3f, 0x9d, 0xb0, 0xff, 0x43, 0xd5,
-	0x4b, 0x53, 0xac, 0x51, 0x25, 0xfe, 0x86, 0x81, 0x29, 0x5a, 0x0e, 0x17, 0x3a, 0x2f, 0x81, 0x8a,
-	0xf7, 0xf6, 0x56, 0x5d, 0xd8, 0x03, 0x12, 0xf2, 0x54, 0xa9, 0xf9, 0x1c, 0x4a, 0x9a, 0x45, 0x3e,
-	0x6f, 0x2a, 0x97, 0xce, 0xff, 0x02, 0x45, 0x4c, 0x8f, 0x97, 0xee, 0x6c, 0x89, 0xce, 0x5b, 0xe4,
-	0xbc, 0xa1, 0x9a, 0x3f, 0x5b, 0x50, 0x49, 0x2b, 0x8c, 0x7d, 0x04, 0x15, 0x3f, 0x9c, 0x0b, 0x74,
-	0xc3, 0xa3, 0xcf, 0xef, 0xc3, 0xb7, 0x96, 0xb3, 0xcf, 0xe1, 0x50, 0x06, 0x58, 0x4b, 0x01, 0x75,
-	0x50, 0x77, 0x66, 0x5a, 0xc5, 0xae, 0xc1, 0xb6, 0x12, 0x96, 0xc7, 0x51, 0x5a, 0xc5, 0x5c, 0x4c,
-	0xe9, 0xf9, 0xca, 0x9f, 0xfa, 0xe9, 0x81, 0xdd, 0xd1, 0x43, 0xa5, 0x83, 0x65, 0xcc, 0x77, 0x95,
-	0xd8, 0xd7, 0x70, 0xa2, 0xae, 0xc5, 0x76, 0x2d, 0xfc, 0xae, 0x98, 0x49, 0x2c, 0xb7, 0x95, 0x89,
-	0xf0, 0xfd, 0xbd, 0x4e, 0x61, 0xf7, 0x76, 0x55, 0xf9, 0xbe, 0x35, 0x3e, 0xa0, 0x9e, 0xde, 0x32,
-	0x88, 0xe4, 0x54, 0x06, 0x94, 0x05, 0xbb, 0x9e, 0xec, 0xe8, 0x34, 0x27, 0x70, 0xb2, 0xf7, 0x75,
-	0xd6, 0xdc, 0xc1, 0xad, 0x9a, 0xc1, 0xe9, 0xdd, 0x9b, 0x70, 0xaa, 0xee, 0xe0, 0xd2, 0xfc, 0x25,
-	0x07, 0x45, 0x02, 0x4c, 0xb5, 0x96, 0x4b, 0x99, 0x78, 0xa1, 0x5c, 0xb7, 0x16, 0x43, 0xb2, 0x0f,
-	0xa0, 0x70, 0x25, 0xdd, 0xc4, 0x00, 0x7d, 0x6b, 0x1b, 0x68, 0xfb, 0x0c, 0x45, 0x9c, 0x14, 0x9a,
-	0x8f, 0xa0, 0xa0, 0x28, 0xd5, 0xd6, 0xbc, 0x65, 0x14, 0x89, 0xc0, 0xa3, 0xb7, 0x18, 0xd7, 0xb6,
-	0x78, 0xaa, 0x2a, 0xa9, 0x22, 0xe8, 0xab, 0x79, 0xae, 0x89, 0xd6, 0xef, 0x25, 0x28, 0xea, 0x39,
-	0x8b, 0xee, 0xeb, 0x36, 0xd8, 0xf6, 0x7d, 0x1c, 0x25, 0xb1, 0xf9, 0xc8, 0x36, 0x13, 0xa7, 0xd1,
-	0xa6, 0x7f, 0x6b, 0xf7, 0x8e, 0xf4, 0xf8, 0xbd, 0xa9, 0x7b, 0xdf, 0xc5, 0x17, 0xaa, 0x81, 0x8a,
-	0xe3, 0xc5, 0xda, 0x8c, 0x97, 0x94, 0xa7, 0xea, 0x06, 0xf1, 0x45, 0xf0, 0xdc, 0xf9, 0xc2, 0xf4,
-	0xb2, 0x0d, 0x03, 0x87, 0x49, 0x51, 0x4d, 0x8c, 0x18, 0x83, 0xa5, 0x47, 0x9e, 0xbe, 0x86, 0x66,
-	0x89, 0x96, 0xb0, 0x07, 0x50, 0x5e, 0xb8, 0xab, 0xb9, 0x08, 0x12, 0x33, 0x73, 0xeb, 0x46, 0x69,
-	0xa8, 0xb9, 0x3c, 0x15, 0x37, 0x5f, 0xe7, 0x32, 0xc9, 0x8f, 0x15, 0xa2, 0x5c, 0x1c, 0x87, 0xe6,
-	0x89, 0x86, 0x52, 0x01, 0x71, 0xcd, 0xdb, 0x75, 0xe8, 0x52, 0x52, 0x55, 0xa2, 0x27, 0x93, 0x95,
-	0xe9, 0x47, 0x74, 0x56, 0x78, 0xa2, 0xa3, 0xb8, 0x1c, 0x14, 0x74, 0x97, 0x23, 0x42, 0x0d, 0xac,
-	0x45, 0x88, 0xc7, 0x19, 0xc5, 0xa1, 0x48, 0xa2, 0x0c, 0x07, 0x7b, 0x5c, 0xd9, 0xec, 0x54, 0xe4,
-	0xf2, 0x6e, 0x12, 0xa6, 0xc2, 0xe6, 0x6f, 0x39, 0xd3, 0x4c, 0x37, 0x53, 0x5c, 0xf5, 0x1f, 0xf2,
-	0xf8, 0x80, 0x67, 0x59, 0x2a, 0x27, 0xbf, 0x5f, 0xba, 0x41, 0xa2, 0x1c, 0xcc, 0x53, 0x22, 0xad,
-	0x69, 0xf6, 0xc9, 0xa6, 0xf9, 0x58, 0x04, 0x23, 0xcb, 0xc0, 0xb8, 0xd7, 0x7a, 0x4e, 0xff, 0xb1,
-	0xf5, 0xe0, 0x83, 0xa9, 0x95, 0x18, 0x70, 0x34, 0xd1, 0xfc, 0x2b, 0x07, 0x65, 0x03, 0x37, 0x0e,
-	0xf7, 0x12, 0xee, 0x02, 0xd7, 0xa1, 0x4f, 0x76, 0xf5, 0xd3, 0xff, 0x6e, 0x87, 0x43, 0xcd, 0x36,
-	0x14, 0x72, 0xa3, 0xa4, 0xe2, 0xbf, 0x1e, 0xe5, 0xe6, 0xa3, 0x1b, 0x86, 0x8a, 0x92, 0x3b, 0x57,
-	0x68, 0x10, 0xea, 0x87, 0xdc, 0x50, 0xd4, 0x6d, 0xaf, 0x5d, 0x19, 0xa8, 0x9d, 0xd4, 0x60, 0xbf,
-	0x61, 0x64, 0x63, 0x58, 0xdc, 0x8e, 0x21, 0x8d, 0x7e, 0x5f, 0x88, 0xf9, 0x88, 0x5a, 0x25, 0xc1,
-	0x4f, 0xa3, 0x7f, 0xc3, 0x6b, 0xdd, 0x87, 0x92, 0xf6, 0x91, 0x01, 0x94, 0xba, 0x0e, 0xef, 0x75,
-	0xc6, 0x38, 0x0a, 0x0f, 0xa1, 0xfa, 0x6c, 0xd0, 0xed, 0xf1, 0xf6, 0xb8, 0x87, 0x63, 0xb0, 0x75,
-	0x0b, 0x4e, 0xf6, 0xf6, 0xca, 0x56, 0x05, 0x4a, 0x7a, 0x6d, 0x6c, 0x55, 0xa1, 0x6c, 0x16, 0x3f,
-	0xa5, 0xb9, 0xb7, 0x03, 0x92, 0x26, 0x95, 0x54, 0xeb, 0x55, 0x0e, 0xf2, 0x58, 0x06, 0x88, 0xf5,
-	0x74, 0x29, 0xfd, 0x14, 0x6b, 0x75, 0x56, 0xce, 0x5e, 0xce, 0x42, 0xef, 0x05, 0x3d, 0xcc, 0x6c,
-	0x67, 0xe8, 0x6c, 0x96, 0xc7, 0xde, 0xc3, 0xec, 0x5f, 0x5e, 0xbe, 0x10, 0xab, 0xd8, 0x54, 0x57,
-	0x0d, 0xab, 0xcb, 0x1e, 0x6a, 0x16, 0x4f, 0x65, 0xcd, 0x2f, 0x31, 0x3e, 0xfa, 0xb8, 0x75, 0xd3,
-	0x81, 0xb9, 0x29, 0xd3, 0x85, 0xf2, 0xc4, 0x4e, 0xc9, 0xd6, 0x9f, 0x39, 0x80, 0xcd, 0x9a, 0x89,
-	0xc1, 0x2d, 0xc7, 0xc2, 0x4b, 0xd2, 0x4d, 0xa8, 0x8e, 0x7d, 0x69, 0x23, 0xb5, 0x47, 0x5a, 0xc4,
-	0x53, 0x9d, 0xf5, 0x5d, 0xf9, 0x9b, 0xef, 0xb2, 0xb6, 0xef, 0xfa, 0x09, 0x17, 0x09, 0x63, 0x98,
-	0xee, 0x2d, 0xb8, 0x5b, 0x9c, 0x3b, 0xa3, 0xb1, 0xd3, 0x7f, 0x8c, 0xab, 0x47, 0x15, 0xbb, 0x14,
-	0xc7, 0x10, 0xe0, 0xce, 0x71, 0x07, 0x18, 0x1d, 0x2f, 0x3a, 0x83, 0xfe, 0x99, 0xc3, 0x9f, 0xb5,
-	0x69, 0x93, 0xb1, 0x54, 0xc4, 0x30, 0x42, 0x4a, 0xbd, 0xa0, 0x6c, 0xbb, 0xce, 0x68, 0x38, 0x19,
-	0xf7, 0x8e, 0x8b, 0xca, 0xc0, 0x10, 0x17, 0xbc, 0x37, 0x1a, 0x9c, 0x4f, 0xc8, 0xa0, 0x44, 0x06,
-	0xbd, 0xb3, 0x49, 0xbf, 0x7b, 0x5c, 0xbe, 0x2c, 0xd1, 0xbf, 0xcd, 0xc3, 0xbf, 0x03, 0x00, 0x00,
-	0xff, 0xff, 0x9a, 0xe6, 0xde, 0x75, 0x02, 0x0d, 0x00, 0x00,
+	// 1838 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x58, 0x4f, 0x8f, 0xe3, 0x48,
+	0x15, 0x9f, 0xfc, 0xb3, 0x93, 0x97, 0x74, 0x26, 0xa9, 0x99, 0x6d, 0x4c, 0xb4, 0xcb, 0xf6, 0x66,
+	0x97, 0xa1, 0x61, 0x77, 0x2c, 0x94, 0x59, 0x2d, 0x47, 0x36, 0x8a, 0xd3, 0xb3, 0xa6, 0x33, 0x49,
+	0xa8, 0x24, 0xbb, 0x70, 0x6a, 0xb9, 0xed, 0xea, 0x74, 0xa9, 0xfd, 0x27, 0x63, 0x97, 0x7b, 0x26,
+	0x47, 0x24, 0x8e, 0x5c, 0x91, 0x38, 0x70, 0xe6, 0xc0, 0x01, 0x71, 0x41, 0xe2, 0x63, 0x70, 0xe2,
+	0x1b, 0x70, 0xe6, 0x2b, 0xa0, 0x2a, 0x57, 0xc5, 0x4e, 0xd2, 0x33, 0x20, 0x71, 0xab, 0xf7, 0x7b,
+	0xef, 0x95, 0xab, 0xde, 0xfb, 0xd5, 0xab, 0x57, 0x86, 0xc7, 0x6e, 0x14, 0xb2, 0xd8, 0x71, 0x59,
+	0x62, 0x6e, 0xe2, 0x88, 0x45, 0x3d, 0xe4, 0x46, 0x69, 0xc8, 0xe2, 0xad, 0x1b, 0x79, 0x44, 0x62,
+	0xfd, 0x7f, 0x55, 0xa0, 0x8b, 0xa9, 0xeb, 0xc4, 0x1e, 0x75, 0xc2, 0x91, 0x74, 0x40, 0x3f, 0x85,
+	0xf6, 0x3d, 0x09, 0xbd, 0x28, 0x9e, 0xd0, 0x84, 0xd1, 0x70, 0x9d, 0x18, 0xa5, 0xb3, 0xca, 0x79,
+	0x73, 0x50, 0x37, 0x25, 0x80, 0x0f, 0xf4, 0xe8, 0x19, 0xc0, 0x75, 0xba, 0x25, 0xf1, 0x2c, 0xf6,
+	0x48, 0x6c, 0x94, 0xcf, 0x4a, 0xe7, 0xcd, 0x81, 0x66, 0x0a, 0x09, 0x17, 0x34, 0x68, 0x02, 0xdf,
+	0xcb, 0x3c, 0x85, 0x38, 0x8a, 0xc2, 0x1b, 0x1a, 0x07, 0x0e, 0xa3, 0x51, 0x68, 0x54, 0x84, 0x13,
+	0x32, 0x8f, 0x34, 0xf8, 0x5d, 0x2e, 0xc8, 0x86, 0xd3, 0x82, 0xea, 0x22, 0xf5, 0x6f, 0xa8, 0xef,
+	0x07, 0x24, 0x64, 0x46, 0x43, 0x4c, 0xd6, 0x35, 0x0f, 0x15, 0xf8, 0x1d, 0x0e, 0xe8, 0xc7, 0xd0,
+	0x14, 0xcb, 0xc4, 0x0e, 0xdf, 0x90, 0x51, 0x15, 0xfe, 0xba, 0x99, 0x89, 0xb8, 0xa8, 0x43, 0x7d,
+	0xd0, 0x3d, 0x9a, 0x6c, 0x52, 0x46, 0x8c, 0x9a, 0x30, 0xab, 0x9b, 0x56, 0x26, 0x63, 0xa5, 0x40,
+	0x5f, 0x43, 0x57, 0x0e, 0x31, 0x49, 0x22, 0x3f, 0x15, 0x3b, 0xd4, 0xe4, 0x0e, 0xad, 0x43, 0x0d,
+	0x3e, 0x36, 0x46, 0x1f, 0x83, 0x16, 0x93, 0x9b, 0x34, 0xf4, 0x0c, 0x5d, 0xad, 0x45, 0x88, 0x58,
+	0xc2, 0xe8, 0x73, 0x80, 0x84, 0xae, 0x43, 0x87, 0xa5, 0x31, 0x49, 0x8c, 0xba, 0x48, 0x50, 0xd3,
+	0x5c, 0xec, 0x20, 0x5c, 0x50, 0xf7, 0xff, 0x7c, 0x02, 0xba, 0x4c, 0x16, 0x3a, 0x83, 0xa6, 0x9f,
+	0x0d, 0xa7, 0x4e, 0x40, 0x8c, 0xd2, 0x59, 0xe9, 0xbc, 0x81, 0x8b, 0x10, 0xfa, 0x18, 0xea, 0x59,
+	0x98, 0x6c, 0x4b, 0xe6, 0xb2, 0x62, 0xda, 0x16, 0xde, 0x81, 0xe8, 0x39, 0xd4, 0x03, 0xc2, 0x1c,
+	0xcf, 0x61, 0x8e, 0xcc, 0x5b, 0x57, 0x51, 0xc3, 0x7c, 0x25, 0x15, 0x78, 0x67, 0x82, 0x3e, 0x81,
+	0x2a, 0x65, 0x24, 0x90, 0x51, 0x3d, 0xd9, 0x99, 0xda, 0x8c, 0x04, 0x58, 0xa8, 0xf8, 0x8c, 0xc9,
+	0x2d, 0xdd, 0x6c, 0x78, 0xf0, 0x6b, 0x07, 0x33, 0x2e, 0xa4, 0x02, 0xef, 0x4c, 0xd0, 0x0f, 0x00,
+	0x82, 0xc8, 0x23, 0xb1, 0xc3, 0xa2, 0x38, 0x31, 0xb4, 0xb3, 0xca, 0x79, 0x03, 0x17, 0x10, 0x64,
+	0x02, 0x62, 0x24, 0x0e, 0x92, 0x61, 0xe8, 0x8d, 0xa2, 0xd0, 0xa3, 0x3c, 0xa4, 0x89, 0x88, 0x64,
+	0x03, 0x3f, 0xa0, 0x41, 0x7d, 0x68, 0x65, 0x61, 0x9d, 0x47, 0x3e, 0x75, 0xb7, 0x46, 0x5d, 0x58,
+	0xee, 0x61, 0xbd, 0xbf, 0x95, 0xa1, 0xae, 0x36, 0x87, 0x0c, 0xd0, 0xef, 0x49, 0x9c, 0xf0, 0xb4,
+	0xf2, 0x00, 0x9e, 0x60, 0x25, 0xa2, 0xaf, 0xa0, 0xee, 0x3a, 0x8c, 0xac, 0xa3, 0x78, 0x2b, 0x82,
+	0xd7, 0x1e, 0xf4, 0x8e, 0x62, 0x63, 0x8e, 0xa4, 0x05, 0xde, 0xd9, 0xa2, 0x9f, 0x43, 0x53, 0x8d,
+	0x17, 0xe9, 0xb5, 0x08, 0x6b, 0x7b, 0xf0, 0xd1, 0xbb, 0x5d, 0x17, 0xe9, 0x35, 0x2e, 0x7a, 0xa0,
+	0x53, 0xd0, 0xc8, 0xdb, 0x0d, 0x8d, 0xb7, 0x22, 0xce, 0x55, 0x2c, 0xa5, 0xfe, 0x0b, 0x68, 0x16,
+	0x7c, 0x90, 0x06, 0xe5, 0xe9, 0xb0, 0xf3, 0x08, 0x3d, 0x86, 0xe6, 0x85, 0xfd, 0xab, 0xb1, 0x75,
+	0x35, 0xc7, 0xf6, 0x68, 0xdc, 0x29, 0xa1, 0x26, 0xe8, 0xc3, 0xd5, 0x68, 0x69, 0xcf, 0xa6, 0x9d,
+	0x72, 0xdf, 0x86, 0xba, 0x72, 0xe2, 0x8a, 0xd5, 0xf4, 0x72, 0x3a, 0xfb, 0x6e, 0xda, 0x79, 0x84,
+	0xba, 0x70, 0x32, 0xff, 0xe6, 0xd7, 0x0b, 0x7b, 0x34, 0x9c, 0x5c, 0xbd, 0x9c, 0xcd, 0xac, 0x4e,
+	0x09, 0x75, 0xa0, 0x65, 0xd9, 0x2f, 0xed, 0xa5, 0x42, 0xca, 0xdc, 0x63, 0x31, 0xc6, 0xdf, 0xf2,
+	0x79, 0x2b, 0xbd, 0x3f, 0x54, 0xa1, 0xca, 0x33, 0x8d, 0x9e, 0x42, 0x8d, 0x51, 0xe6, 0x2b, 0xca,
+	0x65, 0x02, 0xa7, 0xa3, 0x47, 0x12, 0x37, 0xa6, 0x1b, 0x71, 0x48, 0xca, 0x19, 0x1d, 0x0b, 0x10,
+	0x7a, 0x06, 0xed, 0x4d, 0x1c, 0xb9, 0x24, 0x49, 0x68, 0xb8, 0x5e, 0xd2, 0x80, 0x88, 0xe0, 0x34,
+	0xf0, 0x01, 0x8a, 0x06, 0xd0, 0xda, 0xc4, 0xd4, 0x25, 0x73, 0x12, 0xaf, 0x42, 0xca, 0x24, 0xdd,
+	0xda, 0xbb, 0x10, 0xce, 0xb9, 0x12, 0xef, 0xd9, 0x20, 0x04, 0xd5, 0x30, 0xb9, 0x79, 0x23, 0x38,
+	0x57, 0xc7, 0x62, 0xcc, 0x31, 0xe6, 0xac, 0x15, 0xad, 0xc4, 0x98, 0xaf, 0x92, 0x06, 0xce, 0x9a,
+	0x7c, 0xe3, 0x24, 0xb7, 0x84, 0x33, 0x89, 0xab, 0x8a, 0x10, 0xea, 0x40, 0x65, 0x71, 0xb9, 0x92,
+	0xcc, 0xa9, 0x24, 0x97, 0x2b, 0xf4, 0x21, 0x34, 0x5c, 0x45, 0x31, 0x51, 0x91, 0x1a, 0x38, 0x07,
+	0x90, 0x09, 0x7a, 0xb4, 0xc9, 0x78, 0x09, 0xe2, 0xf0, 0x3e, 0xdd, 0x3b, 0x17, 0xe6, 0x4c, 0x28,
+	0xb1, 0x32, 0x42, 0x26, 0xd4, 0x12, 0x16, 0xb9, 0x77, 0x46, 0x53, 0x30, 0xc3, 0xd8, 0xb7, 0x5e,
+	0x70, 0xd5, 0x84, 0xdc, 0x13, 0x1f, 0x67, 0x66, 0xbd, 0x6f, 0x41, 0xcb, 0xa6, 0x10, 0x7b, 0xcc,
+	0x4f, 0xba, 0x18, 0xff, 0x0f, 0x51, 0x3f, 0x05, 0xed, 0xde, 0xf1, 0x53, 0x92, 0x18, 0x15, 0xb1,
+	0x59, 0x29, 0xf5, 0x7f, 0x01, 0x90, 0x7f, 0x0c, 0x9d, 0x40, 0x63, 0x35, 0x5d, 0xe2, 0xe1, 0xe8,
+	0x72, 0x6c, 0x75, 0x1e, 0xa1, 0x16, 0xd4, 0xed, 0xe9, 0xd5, 0x62, 0x39, 0x1b, 0x5d, 0x76, 0x4a,
+	0x5c, 0x39, 0x99, 0x7d, 0x27, 0xc5, 0x32, 0xe7, 0xc9, 0x6c, 0xb5, 0xbc, 0x9a, 0x5d, 0x48, 0xa4,
+	0xd2, 0xfb, 0x6d, 0x05, 0xea, 0xea, 0x74, 0xa3, 0x9f, 0x40, 0xdd, 0x8b, 0x02, 0x92, 0x30, 0xea,
+	0x1a, 0xa5, 0x07, 0x53, 0xb7, 0xd3, 0xa3, 0x2f, 0xe1, 0x84, 0x86, 0x8c, 0xc4, 0xa1, 0xb8, 0x08,
+	0x1c, 0xdf, 0x28, 0x3f, 0xe8, 0xb0, 0x6f, 0x84, 0xbe, 0x82, 0xc7, 0xaa, 0x82, 0x60, 0xb2, 0x16,
+	0xa1, 0xe7, 0x7b, 0x6b, 0x0f, 0x5a, 0xe6, 0x28, 0xbb, 0x1b, 0x47, 0x91, 0x47, 0xf0, 0xa1, 0x11,
+	0xfa, 0x25, 0x74, 0xf9, 0x67, 0x03, 0x87, 0x11, 0xcf, 0x22, 0x3e, 0xbd, 0x27, 0xf2, 0x90, 0x35,
+	0x07, 0x9f, 0x1e, 0x55, 0x29, 0x73, 0x7c, 0x68, 0x8a, 0x8f, 0xbd, 0xd1, 0x97, 0xd0, 0x56, 0x5f,
+	0x99, 0xc5, 0x74, 0x4d, 0x43, 0xc1, 0xc0, 0xc3, 0x95, 0x1c, 0xd8, 0xf4, 0x56, 0xd0, 0x3d, 0x9a,
+	0x1d, 0xf5, 0x0e, 0xe2, 0xd6, 0x28, 0xc4, 0xe9, 0xb3, 0x87, 0xe2, 0xd4, 0x38, 0x88, 0x4b, 0xef,
+	0x77, 0x25, 0xa8, 0x89, 0x80, 0xf1, 0xb2, 0x76, 0x4d, 0x99, 0x1b, 0xd1, 0x5d, 0x59, 0x93, 0x22,
+	0xfa, 0x11, 0x54, 0x6f, 0xa8, 0xc3, 0x64, 0xa0, 0x9f, 0xec, 0x07, 0xda, 0xbc, 0xa0, 0x0e, 0xc3,
+	0xc2, 0xa0, 0xf7, 0x35, 0x54, 0xb9, 0xc4, 0x4b, 0xaa, 0x9b, 0xc6, 0x31, 0x09, 0x5d, 0xb1, 0x17,
+	0xb9, 0xb4, 0x3d, 0x8c, 0x57, 0x04, 0x71, 0x1a, 0xc5, 0xac, 0x65, 0x9c, 0x09, 0xfd, 0xbf, 0x6a,
+	0x50, 0xcb, 0xda, 0x85, 0xcf, 0xe0, 0x24, 0x2b, 0xc1, 0x43, 0xcf, 0x8b, 0x49, 0x92, 0xc8, 0x49,
+	0xf6, 0x41, 0xf4, 0x79, 0xe1, 0xee, 0xc8, 0x96, 0xf7, 0x38, 0xbb, 0xf8, 0x1f, 0xba, 0x39, 0x3e,
+	0x02, 0x5d, 0x5c, 0xe6, 0xb6, 0x65, 0x54, 0xf2, 0xab, 0x4d, 0x61, 0xfc, 0xcc, 0x32, 0xca, 0x83,
+	0xe7, 0x04, 0x1b, 0x59, 0x47, 0x73, 0x00, 0x7d, 0x02, 0x35, 0x7e, 0x5b, 0x25, 0x46, 0x4d, 0x5e,
+	0xb7, 0xd9, 0x67, 0xc4, 0x3d, 0x96, 0x69, 0xd0, 0x39, 0xe8, 0x1b, 0x67, 0x2b, 0x9a, 0x10, 0x4d,
+	0x72, 0x32, 0x33, 0x9a, 0x67, 0x28, 0x56, 0xea, 0xde, 0x5f, 0x4a, 0x05, 0xf2, 0x9f, 0x82, 0xc6,
+	0x97, 0xb8, 0x8c, 0xe4, 0x16, 0xa5, 0xc4, 0x13, 0xe2, 0xc8, 0xbd, 0x67, 0xa9, 0x53, 0x22, 0x3f,
+	0xd5, 0x2e, 0x65, 0x5b, 0x59, 0x0b, 0xc5, 0x98, 0xc7, 0x33, 0x61, 0x0e, 0x23, 0x62, 0xe5, 0x0d,
+	0x9c, 0x09, 0xfc, 0xb2, 0xdc, 0x44, 0x09, 0x73, 0x7c, 0x91, 0x87, 0x9a, 0x50, 0x15, 0x10, 0xf4,
+	0x0c, 0x74, 0xd9, 0x1a, 0x1a, 0xda, 0x03, 0x24, 0x54, 0xca, 0xde, 0x9f, 0x4a, 0xb2, 0x90, 0xe7,
+	0x1d, 0x04, 0xaf, 0x7d, 0x62, 0xc5, 0x2d, 0x5c, 0x84, 0x38, 0x27, 0x5f, 0xa7, 0x4e, 0xc8, 0xf8,
+	0x02, 0xcb, 0x82, 0x48, 0x3b, 0x19, 0x7d, 0x91, 0x17, 0xbe, 0xca, 0x59, 0x25, 0xef, 0xf9, 0x1e,
+	0x2c, 0x7b, 0xbd, 0xc1, 0x7b, 0xcb, 0xd8, 0x53, 0xa8, 0x89, 0xb2, 0x24, 0x83, 0x93, 0x09, 0xbd,
+	0x7f, 0x97, 0x40, 0x97, 0xe1, 0x46, 0xcf, 0x41, 0x0b, 0x08, 0xbb, 0x8d, 0x3c, 0xe1, 0xd7, 0x1e,
+	0x7c, 0xb0, 0x9f, 0x0e, 0x7e, 0xaf, 0xde, 0x46, 0x1e, 0x96, 0x46, 0x3c, 0xff, 0xbb, 0x36, 0x42,
+	0x4e, 0x9a, 0x03, 0x3c, 0x4b, 0x4e, 0xc0, 0xa3, 0x21, 0xa2, 0x7e, 0x82, 0xa5, 0x24, 0x2a, 0xfd,
+	0xad, 0x43, 0x43, 0xde, 0x5a, 0xcb, 0xd8, 0xe7, 0x40, 0x31, 0x87, 0xb5, 0xfd, 0x1c, 0x8a, 0xb6,
+	0xc3, 0x23, 0x24, 0x58, 0x88, 0xb2, 0x6b, 0x68, 0xaa, 0xed, 0xc8, 0xb1, 0xfe, 0xa7, 0xa0, 0x65,
+	0x6b, 0x44, 0x00, 0x9a, 0x65, 0xe3, 0xf1, 0x68, 0xd9, 0x79, 0xc4, 0x4b, 0xeb, 0xab, 0x99, 0x35,
+	0xc6, 0xc3, 0xe5, 0xd8, 0xea, 0x94, 0xfa, 0x33, 0xe8, 0x1e, 0xb7, 0xc7, 0x1f, 0x42, 0x23, 0xe2,
+	0x60, 0x21, 0x49, 0x39, 0xb0, 0xcf, 0xf4, 0xf2, 0x01, 0xd3, 0xfb, 0xff, 0x2c, 0x43, 0xe7, 0xa8,
+	0x49, 0xfe, 0x3f, 0x26, 0xe4, 0x6d, 0xd1, 0xe6, 0x76, 0x9b, 0x50, 0xd7, 0xf1, 0xe5, 0xc1, 0xeb,
+	0x1d, 0x75, 0xe7, 0xe6, 0x5c, 0x5a, 0xe0, 0x9d, 0x2d, 0x7a, 0xc1, 0xbb, 0xed, 0x35, 0x65, 0x8e,
+	0x2f, 0x2b, 0xee, 0xf7, 0x8f, 0xdd, 0xac, 0xcc, 0x00, 0x2b, 0x4b, 0x41, 0x95, 0x88, 0x29, 0xae,
+	0x8b, 0x71, 0x6f, 0x02, 0x75, 0x35, 0x3d, 0xcf, 0x88, 0xeb, 0xc4, 0x31, 0x25, 0xb1, 0x64, 0x93,
+	0x12, 0x79, 0xaf, 0xc1, 0xdf, 0x40, 0x77, 0xbc, 0x15, 0x4e, 0x83, 0x6b, 0xa2, 0x48, 0x70, 0x80,
+	0xf6, 0x7e, 0x06, 0xba, 0xfc, 0x2a, 0xbf, 0xf8, 0xd3, 0xd8, 0x97, 0x13, 0xf1, 0x21, 0x67, 0xff,
+	0xc6, 0x49, 0x92, 0x37, 0x51, 0xec, 0x49, 0xf7, 0x9d, 0xdc, 0xff, 0x7b, 0x09, 0x34, 0xf9, 0x90,
+	0x78, 0x7f, 0x38, 0x0d, 0xd0, 0x5f, 0xa7, 0x8e, 0x9f, 0x9f, 0x20, 0x25, 0x8a, 0x82, 0xaf, 0x6e,
+	0xa1, 0x8c, 0x87, 0x3b, 0x99, 0x57, 0x4c, 0x37, 0x0a, 0x82, 0x34, 0xa4, 0x6e, 0xf6, 0xac, 0xaa,
+	0x0a, 0x83, 0x7d, 0x90, 0xf3, 0x38, 0x26, 0xf7, 0x94, 0xbc, 0x91, 0x11, 0x92, 0xd2, 0x7e, 0x0a,
+	0xb5, 0x43, 0x4e, 0x34, 0xf8, 0x9e, 0xc5, 0x3b, 0xa5, 0xff, 0x04, 0xba, 0x47, 0xaf, 0x98, 0xfe,
+	0x3f, 0xf8, 0xd6, 0xb2, 0xc7, 0xc9, 0xfb, 0xb7, 0x96, 0x1f, 0xa3, 0x8c, 0x26, 0x52, 0xe2, 0x69,
+	0x0b, 0x48, 0x10, 0xa9, 0x92, 0xc6, 0xc7, 0xff, 0xa5, 0x20, 0xf3, 0x56, 0xed, 0x2d, 0xf5, 0x54,
+	0xa2, 0xf9, 0x98, 0x7b, 0xc4, 0xc4, 0x27, 0x4e, 0x42, 0x96, 0x6f, 0xc5, 0x26, 0x5a, 0x38, 0x07,
+	0xd0, 0x17, 0xd0, 0x95, 0x42, 0xfe, 0x54, 0x12, 0xed, 0x5c, 0x0b, 0x1f, 0x2b, 0xfa, 0x7f, 0x2c,
+	0x41, 0xd9, 0xb6, 0xf8, 0x67, 0xd6, 0x29, 0xf5, 0x54, 0xe9, 0xe1, 0x63, 0x7e, 0x76, 0xaf, 0xfd,
+	0xc8, 0xbd, 0x13, 0xe7, 0x5c, 0x3e, 0x94, 0x1a, 0x78, 0x0f, 0x43, 0x3f, 0x04, 0x7d, 0x93, 0x5e,
+	0xdf, 0x91, 0x6d, 0x22, 0x39, 0xdf, 0x34, 0x6d, 0xcb, 0x9c, 0x67, 0x10, 0x56, 0x3a, 0x4e, 0x26,
+	0x89, 0xed, 0x7d, 0xa9, 0x25, 0xbf, 0x54, 0xb8, 0x94, 0xcb, 0x02, 0x56, 0x62, 0xff, 0x37, 0x65,
+	0x80, 0x7c, 0xb5, 0xe8, 0x39, 0xe8, 0x09, 0x71, 0x99, 0x7a, 0x94, 0xb4, 0x07, 0x4f, 0x0a, 0xef,
+	0x41, 0x73, 0x91, 0xa9, 0xb0, 0xb2, 0xd9, 0x7d, 0xab, 0xfc, 0xf0, 0xb7, 0x2a, 0xfb, 0xdf, 0xfa,
+	0x7d, 0x09, 0x74, 0x39, 0xc5, 0xee, 0x0d, 0xd1, 0x04, 0x7d, 0x62, 0x2f, 0x96, 0xf6, 0xf4, 0x65,
+	0xa7, 0x84, 0x1a, 0x50, 0x9b, 0x61, 0x6b, 0x8c, 0x3b, 0x65, 0x74, 0x0a, 0x48, 0x0c, 0xaf, 0x46,
+	0xb3, 0xe9, 0x85, 0x8d, 0x5f, 0x0d, 0xc5, 0xab, 0xa2, 0xc2, 0x2b, 0x18, 0x1e, 0x0a, 0xf3, 0x2a,
+	0xf7, 0xb5, 0xec, 0xc5, 0x7c, 0xb5, 0x1c, 0x77, 0x6a, 0xdc, 0x41, 0x0a, 0x57, 0x78, 0xbc, 0x98,
+	0x4d, 0x56, 0xc2, 0x41, 0x13, 0x0e, 0xe3, 0x8b, 0xd5, 0xd4, 0xea, 0xe8, 0xe8, 0x03, 0xe8, 0x66,
+	0x93, 0x5e, 0xac, 0x26, 0x17, 0xf6, 0x64, 0xf2, 0x6a, 0x3c, 0x5d, 0x76, 0xea, 0xd7, 0x9a, 0xf8,
+	0x93, 0xf1, 0xe2, 0x3f, 0x03, 0x00, 0x75, 0x36, 0xd4, 0xbf, 0xf0, 0x10, 0x00, 0x00,
 }
@@ -11,6 +11,7 @@ It is generated from these files:
 It has these top-level messages:
 	Message
 	Envelope
+	SignedData
 */
 package pb
 
@@ -43,6 +44,7 @@ const (
 	Message_DISPUTE_CLOSE      Message_MessageType = 9
 	Message_REFUND             Message_MessageType = 10
 	Message_OFFLINE_ACK        Message_MessageType = 11
+	Message_ORDER_FULFILLMENT  Message_MessageType = 12
 )
 
 var Message_MessageType_name = map[int32]string{
@@ -58,6 +60,7 @@ var Message_MessageType_name = map[int32]string{
 	9:  "DISPUTE_CLOSE",
 	10: "REFUND",
 	11: "OFFLINE_ACK",
+	12: "ORDER_FULFILLMENT",
 }
 var Message_MessageType_value = map[string]int32{
 	"PING":               0,
@@ -72,6 +75,7 @@ var Message_MessageType_value = map[string]int32{
 	"DISPUTE_CLOSE":      9,
 	"REFUND":             10,
 	"OFFLINE_ACK":        11,
+	"ORDER_FULFILLMENT":  12,
 }
 
 func (x Message_MessageType) String() string {
@@ -113,32 +117,64 @@ func (m *Envelope) GetMessage() *Message {
 	return nil
 }
 
+type SignedData struct {
+	SenderPubkey   []byte `protobuf:"bytes,1,opt,name=senderPubkey,proto3" json:"senderPubkey,omitempty"`
+	SerializedData []byte `protobuf:"bytes,2,opt,name=serializedData,proto3" json:"serializedData,omitempty"`
+	Signature      []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedData) Reset()                    { *m = SignedData{} }
+func (m *SignedData) String() string            { return proto.CompactTextString(m) }
+func (*SignedData) ProtoMessage()               {}
+func (*SignedData) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+
+type SignedData_Command struct {
+	PeerID    string              `protobuf:"bytes,1,opt,name=peerID" json:"peerID,omitempty"`
+	Type      Message_MessageType `protobuf:"varint,2,opt,name=type,enum=Message_MessageType" json:"type,omitempty"`
+	Timestamp uint64              `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *SignedData_Command) Reset()                    { *m = SignedData_Command{} }
+func (m *SignedData_Command) String() string            { return proto.CompactTextString(m) }
+func (*SignedData_Command) ProtoMessage()               {}
+func (*SignedData_Command) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2, 0} }
+
 func init() {
 	proto.RegisterType((*Message)(nil), "Message")
 	proto.RegisterType((*Envelope)(nil), "Envelope")
+	proto.RegisterType((*SignedData)(nil), "SignedData")
+	proto.RegisterType((*SignedData_Command)(nil), "SignedData.Command")
 	proto.RegisterEnum("Message_MessageType", Message_MessageType_name, Message_MessageType_value)
 }
 
 var fileDescriptor2 = []byte{
-	// 311 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x50, 0x4d, 0x4f, 0xc2, 0x40,
-	0x14, 0xb4, 0x08, 0xfd, 0x78, 0x0b, 0xba, 0xbe, 0x18, 0x83, 0x9e, 0x4c, 0x4f, 0x9e, 0x4a, 0x82,
-	0x89, 0xf7, 0x86, 0x6e, 0x49, 0x63, 0xd9, 0x25, 0x5b, 0x88, 0x47, 0x52, 0xe2, 0xca, 0x05, 0xdb,
-	0x06, 0xd4, 0xa4, 0x7f, 0xcb, 0x1f, 0xe0, 0x6f, 0x73, 0xd9, 0xb6, 0x09, 0xa7, 0x7d, 0xb3, 0xf3,
-	0xe6, 0xcd, 0x64, 0x60, 0xf4, 0xa9, 0x8e, 0xc7, 0x7c, 0xa7, 0x82, 0xea, 0x50, 0x7e, 0x95, 0x0f,
-	0xf7, 0xbb, 0xb2, 0xdc, 0xed, 0xd5, 0xc4, 0xa0, 0xed, 0xf7, 0xc7, 0x24, 0x2f, 0xea, 0x86, 0xf2,
-	0x7f, 0x7b, 0xe0, 0x2c, 0x9a, 0x65, 0x7c, 0x01, 0xd2, 0xea, 0x56, 0x75, 0xa5, 0xc6, 0xd6, 0xa3,
-	0xf5, 0x74, 0x35, 0xbd, 0x0d, 0x5a, 0xba, 0x7b, 0x4f, 0x9c, 0x3c, 0x5f, 0xc4, 0x00, 0x9c, 0x2a,
-	0xaf, 0xf7, 0x65, 0xfe, 0x3e, 0xee, 0x69, 0x0d, 0xd1, 0x9a, 0xc6, 0x30, 0xe8, 0x0c, 0x83, 0xb0,
-	0xa8, 0x65, 0xb7, 0xe4, 0xff, 0x59, 0x40, 0xce, 0x8e, 0xa1, 0x0b, 0xfd, 0x65, 0xc2, 0xe7, 0xf4,
-	0x02, 0x89, 0x0e, 0xc3, 0xb2, 0x2c, 0x9c, 0x33, 0x6a, 0x21, 0x80, 0x1d, 0x8b, 0x34, 0x15, 0x6f,
-	0xb4, 0x87, 0x43, 0x70, 0xd7, 0xbc, 0x45, 0x97, 0xe8, 0xc1, 0x40, 0xc8, 0x88, 0x49, 0xda, 0xc7,
-	0x11, 0x78, 0x66, 0xdc, 0x84, 0xb3, 0x57, 0x3a, 0xc0, 0x3b, 0xc0, 0x06, 0xce, 0x04, 0x8f, 0x13,
-	0xb9, 0x08, 0x57, 0x89, 0xe0, 0xd4, 0x3e, 0xdd, 0x92, 0x7a, 0xd6, 0x26, 0x0e, 0x52, 0x18, 0x46,
-	0x49, 0xb6, 0x5c, 0xaf, 0xd8, 0x46, 0x2c, 0x19, 0xa7, 0x2e, 0xde, 0xc0, 0xa8, 0xfb, 0x99, 0xa5,
-	0x22, 0x63, 0xd4, 0x33, 0x02, 0x16, 0xaf, 0x79, 0x44, 0x01, 0xaf, 0x81, 0x88, 0x38, 0x4e, 0x13,
-	0xce, 0x8c, 0x0b, 0xf1, 0x63, 0x70, 0x59, 0xf1, 0xa3, 0xf6, 0xa5, 0x0e, 0xef, 0x83, 0xd3, 0x76,
-	0x61, 0x0a, 0x23, 0x53, 0xb7, 0x2b, 0x4a, 0x76, 0x84, 0x4e, 0x65, 0x57, 0x4a, 0x1d, 0x92, 0xc8,
-	0xf4, 0xe3, 0xc9, 0x16, 0x6d, 0x6d, 0xd3, 0xcf, 0xf3, 0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2d,
-	0xfb, 0x7d, 0xb3, 0xaf, 0x01, 0x00, 0x00,
+	// 435 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x52, 0x4d, 0x6b, 0xdb, 0x40,
+	0x14, 0xac, 0x1c, 0xc7, 0xb2, 0x9f, 0xe4, 0x74, 0xf3, 0x48, 0x83, 0x1b, 0x7a, 0x08, 0x3a, 0x14,
+	0x9f, 0x14, 0x70, 0xa1, 0x77, 0x61, 0xad, 0x82, 0xa8, 0x3e, 0xcc, 0x4a, 0xa6, 0xc7, 0xb0, 0xc6,
+	0x5b, 0x21, 0x6a, 0x7d, 0x20, 0xc9, 0x05, 0xf5, 0x1f, 0xf6, 0x07, 0xf4, 0x07, 0xf4, 0x9f, 0x14,
+	0xaf, 0x24, 0xec, 0xf6, 0x90, 0xdb, 0xbe, 0x99, 0x79, 0x33, 0xcb, 0xf0, 0x60, 0x9e, 0x89, 0xba,
+	0xe6, 0x89, 0x30, 0xcb, 0xaa, 0x68, 0x8a, 0x87, 0xf7, 0x49, 0x51, 0x24, 0x07, 0xf1, 0x24, 0xa7,
+	0xdd, 0xf1, 0xdb, 0x13, 0xcf, 0xdb, 0x8e, 0x32, 0x7e, 0x8d, 0x40, 0xf5, 0x3b, 0x31, 0x7e, 0x06,
+	0xad, 0xdf, 0x8b, 0xdb, 0x52, 0x2c, 0x94, 0x47, 0x65, 0x79, 0xb3, 0xba, 0x33, 0x7b, 0xda, 0xf4,
+	0xcf, 0x1c, 0xbb, 0x14, 0xa2, 0x09, 0x6a, 0xc9, 0xdb, 0x43, 0xc1, 0xf7, 0x8b, 0xd1, 0xa3, 0xb2,
+	0xd4, 0x56, 0x77, 0x66, 0x17, 0x68, 0x0e, 0x81, 0xa6, 0x95, 0xb7, 0x6c, 0x10, 0x19, 0xbf, 0x15,
+	0xd0, 0x2e, 0xcc, 0x70, 0x0a, 0xe3, 0x8d, 0x1b, 0x3c, 0x93, 0x37, 0xa8, 0x81, 0xea, 0xd3, 0x28,
+	0xb2, 0x9e, 0x29, 0x51, 0x10, 0x60, 0xe2, 0x84, 0x9e, 0x17, 0x7e, 0x25, 0x23, 0xd4, 0x61, 0xba,
+	0x0d, 0xfa, 0xe9, 0x0a, 0x67, 0x70, 0x1d, 0x32, 0x9b, 0x32, 0x32, 0xc6, 0x39, 0xcc, 0xe4, 0xf3,
+	0xc5, 0x5a, 0x7f, 0x21, 0xd7, 0x78, 0x0f, 0xd8, 0x8d, 0xeb, 0x30, 0x70, 0x5c, 0xe6, 0x5b, 0xb1,
+	0x1b, 0x06, 0x64, 0x72, 0xf2, 0x62, 0x56, 0x7c, 0x0a, 0x51, 0x91, 0x80, 0x6e, 0xbb, 0xd1, 0x66,
+	0x1b, 0xd3, 0x97, 0x70, 0x43, 0x03, 0x32, 0xc5, 0x5b, 0x98, 0x0f, 0xc8, 0xda, 0x0b, 0x23, 0x4a,
+	0x66, 0x72, 0x81, 0x3a, 0xdb, 0xc0, 0x26, 0x80, 0x6f, 0x41, 0x0b, 0x1d, 0xc7, 0x73, 0x03, 0x2a,
+	0x53, 0x34, 0x7c, 0x07, 0xb7, 0x5d, 0x8a, 0xb3, 0xf5, 0x1c, 0xd7, 0xf3, 0x7c, 0x1a, 0xc4, 0x44,
+	0x37, 0x1c, 0x98, 0xd2, 0xfc, 0x87, 0x38, 0x14, 0xa5, 0x40, 0x03, 0xd4, 0xbe, 0x22, 0xd9, 0xa3,
+	0xb6, 0x9a, 0x0e, 0xfd, 0xb1, 0x81, 0xc0, 0x7b, 0x98, 0x94, 0x42, 0x54, 0xae, 0x2d, 0x6b, 0x9b,
+	0xb1, 0x7e, 0x32, 0xfe, 0x28, 0x00, 0x51, 0x9a, 0xe4, 0x62, 0x6f, 0xf3, 0x86, 0xa3, 0x01, 0x7a,
+	0x2d, 0xf2, 0xbd, 0xa8, 0x36, 0xc7, 0xdd, 0x77, 0xd1, 0x4a, 0x3f, 0x9d, 0xfd, 0x83, 0xe1, 0x47,
+	0xb8, 0xa9, 0x45, 0x95, 0xf2, 0x43, 0xfa, 0xb3, 0xdb, 0x92, 0x96, 0x3a, 0xfb, 0x0f, 0xc5, 0x0f,
+	0x30, 0xab, 0xd3, 0x24, 0xe7, 0xcd, 0xb1, 0x12, 0x8b, 0x2b, 0x29, 0x39, 0x03, 0x0f, 0x29, 0xa8,
+	0xeb, 0x22, 0xcb, 0x78, 0xbe, 0xbf, 0xf8, 0x9b, 0x72, 0xf9, 0x37, 0x5c, 0xc2, 0xb8, 0x39, 0x1d,
+	0xc7, 0xe8, 0x95, 0xe3, 0x90, 0x8a, 0x53, 0x54, 0x93, 0x66, 0xa2, 0x6e, 0x78, 0x56, 0xca, 0xa8,
+	0x31, 0x3b, 0x03, 0xbb, 0x89, 0x3c, 0x8d, 0x4f, 0x7f, 0x07, 0x00, 0xf7, 0xea, 0xe1, 0x38, 0xaa,
+	0x02, 0x00, 0x00,
 }
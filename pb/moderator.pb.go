@@ -0,0 +1,100 @@
+// Code generated by protoc-gen-go.
+// source: moderator.proto
+// DO NOT EDIT!
+
+/*
+Package moderator is a generated protocol buffer package.
+
+It is generated from these files:
+	moderator.proto
+
+It has these top-level messages:
+	Moderator
+	SignedModerator
+*/
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+const _ = proto.ProtoPackageIsVersion1
+
+type Moderator struct {
+	Description   string         `protobuf:"bytes,1,opt,name=description" json:"description,omitempty"`
+	Languages     []string       `protobuf:"bytes,2,rep,name=languages" json:"languages,omitempty"`
+	Fee           *Moderator_Fee `protobuf:"bytes,3,opt,name=fee" json:"fee,omitempty"`
+	BitcoinPubkey []byte         `protobuf:"bytes,4,opt,name=bitcoinPubkey,proto3" json:"bitcoinPubkey,omitempty"`
+}
+
+func (m *Moderator) Reset()                    { *m = Moderator{} }
+func (m *Moderator) String() string            { return proto.CompactTextString(m) }
+func (*Moderator) ProtoMessage()               {}
+func (*Moderator) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0} }
+
+func (m *Moderator) GetFee() *Moderator_Fee {
+	if m != nil {
+		return m.Fee
+	}
+	return nil
+}
+
+type Moderator_Fee struct {
+	Percentage float32 `protobuf:"fixed32,1,opt,name=percentage" json:"percentage,omitempty"`
+	Fixed      uint64  `protobuf:"varint,2,opt,name=fixed" json:"fixed,omitempty"`
+}
+
+func (m *Moderator_Fee) Reset()                    { *m = Moderator_Fee{} }
+func (m *Moderator_Fee) String() string            { return proto.CompactTextString(m) }
+func (*Moderator_Fee) ProtoMessage()               {}
+func (*Moderator_Fee) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0, 0} }
+
+type SignedModerator struct {
+	Moderator  *Moderator `protobuf:"bytes,1,opt,name=moderator" json:"moderator,omitempty"`
+	GuidPubkey []byte     `protobuf:"bytes,2,opt,name=guidPubkey,proto3" json:"guidPubkey,omitempty"`
+	Signature  []byte     `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedModerator) Reset()                    { *m = SignedModerator{} }
+func (m *SignedModerator) String() string            { return proto.CompactTextString(m) }
+func (*SignedModerator) ProtoMessage()               {}
+func (*SignedModerator) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{1} }
+
+func (m *SignedModerator) GetModerator() *Moderator {
+	if m != nil {
+		return m.Moderator
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Moderator)(nil), "Moderator")
+	proto.RegisterType((*Moderator_Fee)(nil), "Moderator.Fee")
+	proto.RegisterType((*SignedModerator)(nil), "SignedModerator")
+}
+
+var fileDescriptor4 = []byte{
+	// 236 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x90, 0x41, 0x4b, 0xc3, 0x30,
+	0x14, 0x80, 0x49, 0x3b, 0x85, 0xbc, 0x4e, 0x07, 0xc1, 0x43, 0x10, 0x91, 0x30, 0x3c, 0xe4, 0xd4,
+	0xc3, 0x3c, 0x7a, 0xdf, 0x4d, 0x90, 0xf8, 0x0b, 0xd2, 0xe6, 0x2d, 0x04, 0x35, 0x29, 0x69, 0x0a,
+	0xee, 0x5f, 0xfa, 0x93, 0xa4, 0x99, 0x2e, 0xdd, 0x31, 0xdf, 0x83, 0x97, 0xef, 0x7b, 0xb0, 0xf9,
+	0x0a, 0x06, 0xa3, 0x4e, 0x21, 0xb6, 0x43, 0x0c, 0x29, 0x6c, 0x7f, 0x08, 0xd0, 0xd7, 0x7f, 0xc6,
+	0x04, 0x34, 0x06, 0xc7, 0x3e, 0xba, 0x21, 0xb9, 0xe0, 0x39, 0x11, 0x44, 0x52, 0xb5, 0x44, 0xec,
+	0x01, 0xe8, 0xa7, 0xf6, 0x76, 0xd2, 0x16, 0x47, 0x5e, 0x89, 0x5a, 0x52, 0x55, 0x00, 0x13, 0x50,
+	0x1f, 0x10, 0x79, 0x2d, 0x88, 0x6c, 0x76, 0xb7, 0xed, 0x79, 0x71, 0xbb, 0x47, 0x54, 0xf3, 0x88,
+	0x3d, 0xc1, 0x4d, 0xe7, 0x52, 0x1f, 0x9c, 0x7f, 0x9b, 0xba, 0x0f, 0x3c, 0xf2, 0x95, 0x20, 0x72,
+	0xad, 0x2e, 0xe1, 0xfd, 0x0b, 0xd4, 0x7b, 0x44, 0xf6, 0x08, 0x30, 0x60, 0xec, 0xd1, 0x27, 0x6d,
+	0x31, 0xdb, 0x54, 0x6a, 0x41, 0xd8, 0x1d, 0x5c, 0x1d, 0xdc, 0x37, 0x1a, 0x5e, 0x09, 0x22, 0x57,
+	0xea, 0xf4, 0xd8, 0x1e, 0x61, 0xf3, 0xee, 0xac, 0x47, 0x53, 0xba, 0x24, 0xd0, 0x73, 0x78, 0xde,
+	0xd3, 0xec, 0xa0, 0xd8, 0xa9, 0x32, 0x9c, 0xbf, 0xb4, 0x93, 0x33, 0x7f, 0x72, 0x55, 0x96, 0x5b,
+	0x90, 0xb9, 0x7f, 0x74, 0xd6, 0xeb, 0x34, 0xc5, 0x53, 0xe7, 0x5a, 0x15, 0xd0, 0x5d, 0xe7, 0xa3,
+	0x3e, 0xff, 0x0e, 0x00, 0x75, 0x88, 0xea, 0xae, 0x67, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,79 @@
+// Code generated by protoc-gen-go.
+// source: posts.proto
+// DO NOT EDIT!
+
+/*
+Package posts is a generated protocol buffer package.
+
+It is generated from these files:
+	posts.proto
+
+It has these top-level messages:
+	Post
+	SignedPost
+*/
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+const _ = proto.ProtoPackageIsVersion1
+
+type Post struct {
+	Text      string   `protobuf:"bytes,1,opt,name=text" json:"text,omitempty"`
+	Images    []string `protobuf:"bytes,2,rep,name=images" json:"images,omitempty"`
+	Listings  []string `protobuf:"bytes,3,rep,name=listings" json:"listings,omitempty"`
+	Timestamp uint64   `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *Post) Reset()                    { *m = Post{} }
+func (m *Post) String() string            { return proto.CompactTextString(m) }
+func (*Post) ProtoMessage()               {}
+func (*Post) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
+type SignedPost struct {
+	Post       *Post  `protobuf:"bytes,1,opt,name=post" json:"post,omitempty"`
+	GuidPubkey []byte `protobuf:"bytes,2,opt,name=guidPubkey,proto3" json:"guidPubkey,omitempty"`
+	Signature  []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedPost) Reset()                    { *m = SignedPost{} }
+func (m *SignedPost) String() string            { return proto.CompactTextString(m) }
+func (*SignedPost) ProtoMessage()               {}
+func (*SignedPost) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *SignedPost) GetPost() *Post {
+	if m != nil {
+		return m.Post
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Post)(nil), "Post")
+	proto.RegisterType((*SignedPost)(nil), "SignedPost")
+}
+
+var fileDescriptor3 = []byte{
+	// 180 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x41, 0x0e, 0x82, 0x30,
+	0x10, 0x45, 0x53, 0xa8, 0x44, 0x06, 0x57, 0x5d, 0x98, 0x6a, 0x8c, 0x69, 0x58, 0x75, 0xc5, 0x42,
+	0x2f, 0x42, 0xea, 0x09, 0x20, 0x34, 0x4d, 0x23, 0x50, 0xc2, 0x0c, 0x89, 0xde, 0xde, 0xd0, 0x18,
+	0x71, 0x37, 0xff, 0xfd, 0xc5, 0x9b, 0x0f, 0xc5, 0x14, 0x90, 0xb0, 0x9a, 0xe6, 0x40, 0xa1, 0xec,
+	0x81, 0xd7, 0x01, 0x49, 0x08, 0xe0, 0x64, 0x5f, 0x24, 0x99, 0x62, 0x3a, 0x37, 0xf1, 0x16, 0x47,
+	0xc8, 0xfc, 0xd0, 0x38, 0x8b, 0x32, 0x51, 0xa9, 0xce, 0xcd, 0x37, 0x89, 0x33, 0xec, 0x7b, 0x8f,
+	0xe4, 0x47, 0x87, 0x32, 0x8d, 0xcd, 0x2f, 0x8b, 0x0b, 0xe4, 0xe4, 0x07, 0x8b, 0xd4, 0x0c, 0x93,
+	0xe4, 0x8a, 0x69, 0x6e, 0x36, 0x50, 0x5a, 0x80, 0x87, 0x77, 0xa3, 0xed, 0xa2, 0xf3, 0x04, 0x7c,
+	0x7d, 0x25, 0x3a, 0x8b, 0xdb, 0xae, 0x5a, 0xa1, 0x89, 0x48, 0x5c, 0x01, 0xdc, 0xe2, 0xbb, 0x7a,
+	0x69, 0x9f, 0xf6, 0x2d, 0x13, 0xc5, 0xf4, 0xc1, 0xfc, 0x91, 0x55, 0x83, 0xde, 0x8d, 0x0d, 0x2d,
+	0xb3, 0x95, 0x69, 0xac, 0x37, 0xd0, 0x66, 0x71, 0xdb, 0xfd, 0x33, 0x00, 0x09, 0x98, 0x34, 0xeb,
+	0xea, 0x00, 0x00, 0x00,
+}
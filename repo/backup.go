@@ -0,0 +1,212 @@
+package repo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipfs/go-ipfs/repo/config"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// A backup archive is the magic bytes and version followed by the PBKDF2 salt, the
+// AES-GCM nonce and the encrypted gzipped tarball of the repo files.
+const (
+	backupMagic      = "OBBACKUP"
+	backupVersion    = 1
+	backupSaltSize   = 16
+	backupIterations = 100000
+)
+
+var ErrBadBackup = errors.New("Not a backup archive or the password is wrong")
+
+// Write an encrypted archive of the config, the SQLite database and the root directory
+// of the repo. The node must not be running.
+func Backup(repoRoot string, out io.Writer, password string) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	files := []string{"config"}
+	dbs, err := filepath.Glob(path.Join(repoRoot, "datastore", "*.db"))
+	if err != nil {
+		return err
+	}
+	for _, db := range dbs {
+		files = append(files, path.Join("datastore", filepath.Base(db)))
+	}
+	err = filepath.Walk(path.Join(repoRoot, "root"), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(repoRoot, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err := addTarFile(tw, repoRoot, name); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := backupCipher(password, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	header := append([]byte(backupMagic), backupVersion)
+	header = append(header, salt...)
+	header = append(header, nonce...)
+	if _, err := out.Write(header); err != nil {
+		return err
+	}
+	_, err = out.Write(gcm.Seal(nil, nonce, buf.Bytes(), header))
+	return err
+}
+
+// Unpack a backup archive into a new repo. The IPFS repo is initialized with the config
+// from the archive. The IPNS keyspace must be initialized with the identity key from the
+// restored database before the node is started.
+func RestoreBackup(in io.Reader, repoRoot string, password string) error {
+	if fsrepo.IsInitialized(repoRoot) {
+		return ErrRepoExists
+	}
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	headerSize := len(backupMagic) + 1 + backupSaltSize
+	if len(data) < headerSize || string(data[:len(backupMagic)]) != backupMagic {
+		return ErrBadBackup
+	}
+	if data[len(backupMagic)] != backupVersion {
+		return errors.New("Unsupported backup version")
+	}
+	salt := data[len(backupMagic)+1 : headerSize]
+	gcm, err := backupCipher(password, salt)
+	if err != nil {
+		return err
+	}
+	if len(data) < headerSize+gcm.NonceSize() {
+		return ErrBadBackup
+	}
+	header := data[:headerSize+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, data[headerSize:len(header)], data[len(header):], header)
+	if err != nil {
+		return ErrBadBackup
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.New("Invalid file name in backup: " + hdr.Name)
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		files[name] = b
+	}
+	cfgBytes, ok := files["config"]
+	if !ok {
+		return errors.New("Backup does not contain a config file")
+	}
+
+	if err := maybeCreateOBDirectories(repoRoot); err != nil {
+		return err
+	}
+	if err := checkWriteable(repoRoot); err != nil {
+		return err
+	}
+	// fsrepo.Init only writes the fields it knows about so the original config
+	// including our extensions is written over it afterwards
+	conf := new(config.Config)
+	if err := json.Unmarshal(cfgBytes, conf); err != nil {
+		return err
+	}
+	if err := fsrepo.Init(repoRoot, conf); err != nil {
+		return err
+	}
+	for name, b := range files {
+		p := filepath.Join(repoRoot, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, b, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addTarFile(tw *tar.Writer, repoRoot string, name string) error {
+	b, err := ioutil.ReadFile(path.Join(repoRoot, name))
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: int64(len(b)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = tw.Write(b)
+	return err
+}
+
+func backupCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key := pbkdf2.Key([]byte(password), salt, backupIterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+)
+
+func newTestRepo(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := maybeCreateOBDirectories(dir); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := initConfig(ioutil.Discard, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsrepo.Init(dir, conf); err != nil {
+		t.Fatal(err)
+	}
+	// Add an extension to the config as addConfigExtensions does
+	var cfg map[string]interface{}
+	b, _ := ioutil.ReadFile(path.Join(dir, "config"))
+	json.Unmarshal(b, &cfg)
+	cfg["Dropbox-api-token"] = "token"
+	b, _ = json.Marshal(cfg)
+	ioutil.WriteFile(path.Join(dir, "config"), b, 0600)
+
+	ioutil.WriteFile(path.Join(dir, "datastore", "mainnet.db"), []byte("database"), 0600)
+	ioutil.WriteFile(path.Join(dir, "root", "listings", "shoes.json"), []byte("listing"), 0600)
+	return dir
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	src := newTestRepo(t)
+	defer os.RemoveAll(src)
+	var archive bytes.Buffer
+	if err := Backup(src, &archive, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst)
+	if err := RestoreBackup(bytes.NewReader(archive.Bytes()), dst, "wrong"); err != ErrBadBackup {
+		t.Errorf("Expected wrong password to fail, got %v", err)
+	}
+	if err := RestoreBackup(bytes.NewReader(archive.Bytes()), dst, "password"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"config", "datastore/mainnet.db", "root/listings/shoes.json"} {
+		expected, _ := ioutil.ReadFile(path.Join(src, name))
+		restored, err := ioutil.ReadFile(path.Join(dst, name))
+		if err != nil || !bytes.Equal(expected, restored) {
+			t.Errorf("%s was not restored", name)
+		}
+	}
+	r, err := fsrepo.Open(dst)
+	if err != nil {
+		t.Fatalf("Restored IPFS repo can't be opened: %s", err)
+	}
+	r.Close()
+	if err := RestoreBackup(bytes.NewReader(archive.Bytes()), dst, "password"); err != ErrRepoExists {
+		t.Error("Restore should not overwrite an existing repo")
+	}
+}
@@ -3,10 +3,14 @@ package repo
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"strconv"
 
 	"github.com/OpenBazaar/go-libbitcoinclient"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/repo/config"
 	"github.com/pebbe/zmq4"
@@ -86,6 +90,116 @@ func GetMaxFee(cfgPath string) (uint64, error) {
 	return uint64(maxFee), nil
 }
 
+// Returns the configured coin selection strategy or an empty string if the
+// config predates the option.
+func GetCoinSelection(cfgPath string) (string, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	strategy, ok := wallet.(map[string]interface{})["CoinSelection"].(string)
+	if !ok {
+		return "", nil
+	}
+	return strategy, nil
+}
+
+// Returns the configured address type or an empty string if the
+// config predates the option.
+func GetAddressType(cfgPath string) (string, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	addressType, ok := wallet.(map[string]interface{})["AddressType"].(string)
+	if !ok {
+		return "", nil
+	}
+	return addressType, nil
+}
+
+// Returns the configured gap limit or zero if the
+// config predates the option.
+func GetGapLimit(cfgPath string) (int, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return 0, err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	gapLimit, ok := wallet.(map[string]interface{})["GapLimit"].(float64)
+	if !ok {
+		return 0, nil
+	}
+	return int(gapLimit), nil
+}
+
+// Returns the account extended public key of a watch-only wallet or an
+// empty string if the wallet holds its own keys.
+func GetWatchOnlyXpub(cfgPath string) (string, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	xpub, ok := wallet.(map[string]interface{})["WatchOnlyXpub"].(string)
+	if !ok {
+		return "", nil
+	}
+	return xpub, nil
+}
+
+// Returns the configured wallet backend or an empty string if the
+// config predates the option.
+func GetWalletType(cfgPath string) (string, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	walletType, ok := wallet.(map[string]interface{})["Type"].(string)
+	if !ok {
+		return "", nil
+	}
+	return walletType, nil
+}
+
+// Returns the RPC host and credentials of the bitcoind node used by the bitcoind wallet
+func GetBitcoindConfig(cfgPath string) (host string, user string, password string, err error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", "", "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	wallet := cfg.(map[string]interface{})["Wallet"]
+	bitcoind, ok := wallet.(map[string]interface{})["Bitcoind"].(map[string]interface{})
+	if !ok {
+		return "", "", "", errors.New("Bitcoind settings not found in config file")
+	}
+	host, _ = bitcoind["Host"].(string)
+	user, _ = bitcoind["User"].(string)
+	password, _ = bitcoind["Password"].(string)
+	return host, user, password, nil
+}
+
 func GetDropboxApiToken(cfgPath string) (string, error) {
 	file, err := ioutil.ReadFile(cfgPath)
 	if err != nil {
@@ -106,12 +220,46 @@ func extendConfigFile(r repo.Repo, key string, value interface{}) error {
 	return nil
 }
 
-func initConfig(out io.Writer) (*config.Config, error) {
+func initConfig(out io.Writer, params *chaincfg.Params) (*config.Config, error) {
 
 	bootstrapPeers, err := config.ParseBootstrapPeers(DefaultBootstrapAddresses)
 	if err != nil {
 		return nil, err
 	}
+	regtest := params.Name == chaincfg.RegressionNetParams.Name
+
+	// Use other ports if the defaults are taken, such as by another node on this host
+	swarmPort, err := allocatePort(DefaultSwarmPort, !regtest)
+	if err != nil {
+		return nil, err
+	}
+	gatewayPort, err := allocatePort(DefaultGatewayPort, false)
+	if err != nil {
+		return nil, err
+	}
+	if swarmPort != DefaultSwarmPort {
+		fmt.Fprintf(out, "port %d is in use, using swarm port %d\n", DefaultSwarmPort, swarmPort)
+	}
+	if gatewayPort != DefaultGatewayPort {
+		fmt.Fprintf(out, "port %d is in use, using gateway port %d\n", DefaultGatewayPort, gatewayPort)
+	}
+	port := strconv.Itoa(swarmPort)
+
+	swarmAddrs := []string{
+		"/ip4/0.0.0.0/tcp/" + port,
+		"/ip4/0.0.0.0/udp/" + port + "/utp",
+		"/ip6/::/tcp/" + port,
+		"/ip6/::/udp/" + port + "/utp",
+	}
+	mdns := false
+
+	// Regtest nodes form a private swarm. They only listen on localhost, have no
+	// bootstrap peers and find each other with MDNS.
+	if regtest {
+		bootstrapPeers = nil
+		swarmAddrs = []string{"/ip4/127.0.0.1/tcp/" + port}
+		mdns = true
+	}
 
 	datastore, err := datastoreConfig()
 	if err != nil {
@@ -123,20 +271,15 @@ func initConfig(out io.Writer) (*config.Config, error) {
 		// setup the node's default addresses.
 		// NOTE: two swarm listen addrs, one tcp, one utp.
 		Addresses: config.Addresses{
-			Swarm: []string{
-				"/ip4/0.0.0.0/tcp/4001",
-				"/ip4/0.0.0.0/udp/4001/utp",
-				"/ip6/::/tcp/4001",
-				"/ip6/::/udp/4001/utp",
-			},
+			Swarm:   swarmAddrs,
 			API:     "",
-			Gateway: "/ip4/127.0.0.1/tcp/8080",
+			Gateway: "/ip4/127.0.0.1/tcp/" + strconv.Itoa(gatewayPort),
 		},
 
 		Datastore: datastore,
 		Bootstrap: config.BootstrapPeerStrings(bootstrapPeers),
 		Discovery: config.Discovery{config.MDNS{
-			Enabled:  false,
+			Enabled:  mdns,
 			Interval: 10,
 		}},
 
@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"time"
+
 	b32 "github.com/tyler-smith/go-bip32"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"gx/ipfs/QmbyvM8zRFDkbFdYyt1MnevUMJ62SiSGbfDFZ3Z8nkrzr4/go-libp2p-peer"
@@ -16,17 +18,39 @@ type Datastore interface {
 	Keys() Keys
 	Transactions() Transactions
 	Coins() Coins
+	Headers() Headers
+	Feed() Feed
+	SearchIndex() SearchIndex
+	Purchases() Purchases
+	Inventory() Inventory
 	Close()
+
+	// Encrypt the unencrypted database with the password
+	Encrypt(password string) error
+
+	// Remove the encryption from a database opened with its password
+	Decrypt() error
+
+	// Change the password of an encrypted database
+	ChangePassword(newPassword string) error
 }
 
 type Config interface {
-	// Initialize the database with the node's mnemonic seed and
-	// identity key. This will be called during repo init
-	Init(mnemonic string, identityKey []byte, password string) error
+	// Initialize the database with the node's mnemonic seed, BIP39 passphrase,
+	// the version of the key derivation scheme and the identity key. This will
+	// be called during repo init
+	Init(mnemonic string, identityKey []byte, password string, passphrase string, seedVersion int) error
 
 	// Return the mnemonic string
 	GetMnemonic() (string, error)
 
+	// Return the BIP39 passphrase. Empty if none was set.
+	GetPassphrase() (string, error)
+
+	// Return the version of the scheme used to derive keys from the mnemonic.
+	// Databases created before versioning return SeedVersionLegacy.
+	GetSeedVersion() (int, error)
+
 	// Return the identity key
 	GetIdentityKey() ([]byte, error)
 
@@ -35,18 +59,29 @@ type Config interface {
 }
 
 type Followers interface {
-	// Put a B58 encoded follower ID to the database
-	Put(follower string) error
+	// Put a B58 encoded follower ID to the database along with the serialized
+	// SignedData proving the follow and the time it was signed
+	Put(follower string, proof []byte, timestamp time.Time) error
 
 	// Get followers from the database.
 	// The offset and limit arguments can be used to for lazy loading.
-	Get(offset int, limit int) ([]string, error)
+	Get(offset int, limit int) ([]Follower, error)
 
-	// Delete a follower from the databse.
-	Delete(follower string) error
+	// Delete a follower from the databse, recording the time of the unfollow.
+	Delete(follower string, timestamp time.Time) error
 
 	// Return the number of followers in the database.
 	Count() int
+
+	// Return the time of the latest follow or unfollow from the peer. This
+	// is the zero time if there has been none.
+	LastUpdate(follower string) (time.Time, error)
+}
+
+// A follower and the serialized SignedData FOLLOW command they sent
+type Follower struct {
+	PeerId string
+	Proof  []byte
 }
 
 type Following interface {
@@ -99,6 +134,10 @@ type Keys interface {
 
 	// Fetch all keys
 	GetAll() ([]*b32.Key, error)
+
+	// Fetch the scriptPubKeys of all keys. Used to
+	// derive the addresses we need to watch.
+	GetAllScripts() ([][]byte, error)
 }
 
 type Transactions interface {
@@ -123,6 +162,10 @@ type Transactions interface {
 	// Update the transaction height. This should only be needed
 	// for newly confirmed transactions and reorgs.
 	UpdateHeight(txid []byte, height int) error
+
+	// Delete a transaction from the database. Used when
+	// rebuilding the wallet during a rescan.
+	Delete(txid []byte) error
 }
 
 type Coins interface {
@@ -142,3 +185,156 @@ type Coins interface {
 	// Get the value associated with a utxo
 	GetValue(txid []byte, index int) (int, error)
 }
+
+type Headers interface {
+	// Put a block header to the database. Any existing header
+	// at the same height is replaced.
+	Put(height int, hash []byte, prevHash []byte) error
+
+	// Fetch the hash of the header at the given height
+	GetHash(height int) ([]byte, error)
+
+	// Fetch the height and hash of the best header in the database
+	GetBest() (int, []byte, error)
+
+	// Delete all headers above the given height. Used to roll back
+	// orphaned blocks during a reorg.
+	DeleteAbove(height int) error
+}
+
+type Feed interface {
+	// Put a post to the database. The hash is the IPFS hash of the published post and
+	// the post is the serialized SignedPost.
+	Put(hash string, peerId string, post []byte, timestamp time.Time) error
+
+	// Does the post with the given hash exist in the database?
+	Has(hash string) bool
+
+	// Get posts from the database, newest first.
+	// The offset and limit arguments can be used to for lazy loading.
+	Get(offset int, limit int) ([]FeedItem, error)
+}
+
+// A post in the feed of our node or a peer we follow
+type FeedItem struct {
+	Hash      string
+	PeerId    string
+	Post      []byte
+	Timestamp time.Time
+}
+
+type SearchIndex interface {
+	// Add a listing to the index, replacing any earlier version of it
+	Put(listing SearchListing) error
+
+	// Is the listing with the given IPFS hash in the index?
+	Has(hash string) bool
+
+	// Remove the peer's listings other than those with the given hashes. Used when
+	// the peer's listing index is refreshed.
+	Prune(peerId string, keep []string) error
+
+	// Return the peers with listings in the index
+	Peers() ([]string, error)
+
+	// Return the listings matching the query, newest first.
+	// The offset and limit arguments can be used to for lazy loading.
+	Search(query SearchQuery, offset int, limit int) ([]SearchListing, error)
+}
+
+// A listing in the search index
+type SearchListing struct {
+	Hash         string
+	PeerId       string
+	ListingName  string
+	Title        string
+	Description  string
+	Tags         []string
+	Category     int
+	Price        uint64 // satoshis
+	CurrencyCode string
+	FiatPrice    float64
+	ShipsTo      []int // country codes
+	Timestamp    time.Time
+}
+
+// Filters for searching the index. Zero values match everything.
+type SearchQuery struct {
+	// Words to match against the title, description and tags
+	Text string
+
+	// Only listings shipping to this country code, or to all countries
+	ShipsTo int
+
+	// Only listings of this category
+	Category int
+
+	// Only listings priced in this range. The prices are in satoshis unless a
+	// currency code is given, in which case listings priced in that fiat currency
+	// are matched.
+	MinPrice     float64
+	MaxPrice     float64
+	CurrencyCode string
+}
+
+type OrderState int
+
+const (
+	ORDER_PENDING            OrderState = 0
+	ORDER_REFUNDED           OrderState = 1
+	ORDER_PARTIALLY_REFUNDED OrderState = 2
+	ORDER_FULFILLED          OrderState = 3
+	ORDER_CONFIRMED          OrderState = 4
+)
+
+func (s OrderState) String() string {
+	switch s {
+	case ORDER_PENDING:
+		return "PENDING"
+	case ORDER_REFUNDED:
+		return "REFUNDED"
+	case ORDER_PARTIALLY_REFUNDED:
+		return "PARTIALLY_REFUNDED"
+	case ORDER_FULFILLED:
+		return "FULFILLED"
+	case ORDER_CONFIRMED:
+		return "CONFIRMED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type Purchases interface {
+	// Put the contract of an order we placed and its state, replacing any earlier
+	// version. The order hash is the hex encoded sha256 of the serialized order.
+	Put(orderHash string, contract []byte, state OrderState, timestamp time.Time) error
+
+	// Get the contract and state of an order we placed
+	Get(orderHash string) (contract []byte, state OrderState, err error)
+}
+
+type Inventory interface {
+	// Set the stock of a variant of one of our listings. The variant is the listing's
+	// option combination, empty for a listing without options.
+	Put(listingName string, variant string, count int) error
+
+	// Get the stock of each tracked variant of a listing
+	Get(listingName string) (map[string]int, error)
+
+	// Stop tracking the stock of a variant
+	Delete(listingName string, variant string) error
+
+	// Take the items of an order out of stock. Either all of the items are taken or, if
+	// any tracked variant has too few left, none are and ErrInsufficientStock is
+	// returned. Untracked variants are never out of stock.
+	Reserve(items []InventoryItem) error
+
+	// Put the items of an order back in stock
+	Restore(items []InventoryItem) error
+}
+
+type InventoryItem struct {
+	ListingName string
+	Variant     string
+	Quantity    int
+}
@@ -7,7 +7,6 @@ import (
 	"encoding/hex"
 	"strconv"
 	"strings"
-	"errors"
 )
 
 type CoinsDB struct {
@@ -18,38 +17,27 @@ type CoinsDB struct {
 func (c *CoinsDB) Put(utxo bitcoin.Utxo) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	tx, err := c.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("insert into coins(outpoint, value, scriptPubKey) values(?,?,?)")
-	if err != nil {
-		return err
-	}
 	outpoint := hex.EncodeToString(utxo.Txid) + ":" + strconv.Itoa(utxo.Index)
-	defer stmt.Close()
-	_, err = stmt.Exec(
-		outpoint,
-		utxo.Value,
-		hex.EncodeToString(utxo.ScriptPubKey),
-	)
-	if err != nil {
-		tx.Rollback()
+	return withTx(c.db, "put coin", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into coins(outpoint, value, scriptPubKey) values(?,?,?)",
+			outpoint,
+			utxo.Value,
+			hex.EncodeToString(utxo.ScriptPubKey),
+		)
 		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (c *CoinsDB) Has(txid []byte, index int) bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	stmt, err := c.db.Prepare("select outpoint from coins where outpoint=?")
-	defer stmt.Close()
 	outpoint := hex.EncodeToString(txid) + ":" + strconv.Itoa(index)
 	var ret string
-	err = stmt.QueryRow(outpoint).Scan(&ret)
+	err := c.db.QueryRow("select outpoint from coins where outpoint=?", outpoint).Scan(&ret)
 	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error(wrapError("has coin", err))
+		}
 		return false
 	}
 	return true
@@ -60,11 +48,7 @@ func (c *CoinsDB) Delete(txid []byte, index int) error {
 	defer c.lock.Unlock()
 	outpoint := hex.EncodeToString(txid) + ":" + strconv.Itoa(index)
 	_, err := c.db.Exec("delete from coins where outpoint=?", outpoint)
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	return nil
+	return wrapError("delete coin", err)
 }
 
 func (c *CoinsDB) GetAll() []bitcoin.Utxo {
@@ -74,15 +58,16 @@ func (c *CoinsDB) GetAll() []bitcoin.Utxo {
 	stm := `select * from coins`
 	rows, err := c.db.Query(stm)
 	if err != nil {
-		log.Error(err)
+		log.Error(wrapError("get coins", err))
 		return ret
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var outpoint string
 		var value int
 		var scriptPubKey string
 		if err := rows.Scan(&outpoint, &value, &scriptPubKey); err != nil {
-			log.Error(err)
+			log.Error(wrapError("get coins", err))
 			return ret
 		}
 		s := strings.Split(outpoint, ":")
@@ -116,13 +101,11 @@ func (c *CoinsDB) GetValue(txid []byte, index int) (int, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	stmt, err := c.db.Prepare("select value from coins where outpoint=?")
-	defer stmt.Close()
 	outpoint := hex.EncodeToString(txid) + ":" + strconv.Itoa(index)
 	var value int
-	err = stmt.QueryRow(outpoint).Scan(&value)
+	err := c.db.QueryRow("select value from coins where outpoint=?", outpoint).Scan(&value)
 	if err != nil {
-		return 0, errors.New("Coin not found")
+		return 0, wrapError("get coin value", err)
 	}
 	return value, nil
 }
\ No newline at end of file
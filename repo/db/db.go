@@ -3,9 +3,11 @@ package db
 import (
 	"database/sql"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/op/go-logging"
 	_ "github.com/xeodou/go-sqlcipher"
 )
@@ -21,66 +23,119 @@ type SQLiteDatastore struct {
 	keys 	        repo.Keys
 	transactions    repo.Transactions
 	coins           repo.Coins
+	headers         repo.Headers
+	feed            repo.Feed
+	searchIndex     repo.SearchIndex
+	purchases       repo.Purchases
+	inventory       repo.Inventory
 	db              *sql.DB
 	lock            *sync.Mutex
+	path            string
+	password        string
 }
 
-func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
-	var dbPath string
-	if testnet {
-		dbPath = path.Join(repoPath, "datastore", "testnet.db")
-	} else {
-		dbPath = path.Join(repoPath, "datastore", "mainnet.db")
+func Create(repoPath, password string, params *chaincfg.Params) (*SQLiteDatastore, error) {
+	dbPath := path.Join(repoPath, "datastore", dbFilename(params))
+	conn, err := openConn(dbPath, password)
+	if err != nil {
+		return nil, err
 	}
+	sqliteDB := &SQLiteDatastore{
+		path:     dbPath,
+		password: password,
+		lock:     new(sync.Mutex),
+	}
+	sqliteDB.setConn(conn)
+	return sqliteDB, nil
+}
+
+func openConn(dbPath string, password string) (*sql.DB, error) {
 	conn, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, err
 	}
 	if password != "" {
-		p := "pragma key = '" + password + "';"
+		p := "pragma key = " + quote(password) + ";"
 		conn.Exec(p)
 	}
+	return conn, nil
+}
 
-	l := new(sync.Mutex)
-	sqliteDB := &SQLiteDatastore{
-		config: &ConfigDB{
-			db:   conn,
-			lock: l,
-			path: dbPath,
-		},
-		followers: &FollowerDB{
-			db:   conn,
-			lock: l,
-		},
-		following: &FollowingDB{
-			db:   conn,
-			lock: l,
-		},
-		offlineMessages: &OfflineMessagesDB{
-			db:   conn,
-			lock: l,
-		},
-		pointers: &PointersDB{
-			db:   conn,
-			lock: l,
-		},
-		keys: &KeysDB{
-			db:   conn,
-			lock: l,
-		},
-		transactions: &TransactionsDB{
-			db:   conn,
-			lock: l,
-		},
-		coins: &CoinsDB{
-			db:   conn,
-			lock: l,
-		},
+// Point the datastore and each of its tables at the connection. This is used when
+// the database file is replaced or its key changes.
+func (d *SQLiteDatastore) setConn(conn *sql.DB) {
+	l := d.lock
+	d.config = &ConfigDB{
+		db:   conn,
+		lock: l,
+		path: d.path,
+	}
+	d.followers = &FollowerDB{
+		db:   conn,
+		lock: l,
+	}
+	d.following = &FollowingDB{
+		db:   conn,
+		lock: l,
+	}
+	d.offlineMessages = &OfflineMessagesDB{
+		db:   conn,
+		lock: l,
+	}
+	d.pointers = &PointersDB{
+		db:   conn,
+		lock: l,
+	}
+	d.keys = &KeysDB{
 		db:   conn,
 		lock: l,
 	}
+	d.transactions = &TransactionsDB{
+		db:   conn,
+		lock: l,
+	}
+	d.coins = &CoinsDB{
+		db:   conn,
+		lock: l,
+	}
+	d.headers = &HeadersDB{
+		db:   conn,
+		lock: l,
+	}
+	d.feed = &FeedDB{
+		db:   conn,
+		lock: l,
+	}
+	d.searchIndex = &SearchIndexDB{
+		db:   conn,
+		lock: l,
+	}
+	d.purchases = &PurchasesDB{
+		db:   conn,
+		lock: l,
+	}
+	d.inventory = &InventoryDB{
+		db:   conn,
+		lock: l,
+	}
+	d.db = conn
+}
 
-	return sqliteDB, nil
+// Quote a string as an SQL literal for statements such as pragmas which can't take parameters
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// Each network has its own database file
+func dbFilename(params *chaincfg.Params) string {
+	switch params.Name {
+	case chaincfg.TestNet3Params.Name:
+		return "testnet.db"
+	case chaincfg.RegressionNetParams.Name:
+		return "regtest.db"
+	default:
+		return "mainnet.db"
+	}
 }
 
 func (d *SQLiteDatastore) Close() {
@@ -119,48 +174,66 @@ func (d *SQLiteDatastore) Coins() repo.Coins {
 	return d.coins
 }
 
+func (d *SQLiteDatastore) Headers() repo.Headers {
+	return d.headers
+}
+
+func (d *SQLiteDatastore) Feed() repo.Feed {
+	return d.feed
+}
+
+func (d *SQLiteDatastore) SearchIndex() repo.SearchIndex {
+	return d.searchIndex
+}
+
+func (d *SQLiteDatastore) Purchases() repo.Purchases {
+	return d.purchases
+}
+
+func (d *SQLiteDatastore) Inventory() repo.Inventory {
+	return d.inventory
+}
+
 func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 	var cp string
-	stmt := "select name from sqlite_master where type='table'"
+	// Full-text tables store their data in ordinary shadow tables which are copied instead
+	stmt := "select name from sqlite_master where type='table' and sql not like 'create virtual table%'"
 	rows, err := d.db.Query(stmt)
 	if err != nil {
-		log.Error(err)
-		return err
+		return wrapError("copy database", err)
 	}
 	var tables []string
 	for rows.Next() {
 		var name string
 		if err := rows.Scan(&name); err != nil {
-			return err
+			rows.Close()
+			return wrapError("copy database", err)
 		}
 		tables = append(tables, name)
 	}
+	rows.Close()
 	if password == "" {
-		cp = `attach database '` + dbPath + `' as plaintext key '';`
+		cp = `attach database ` + quote(dbPath) + ` as plaintext key '';`
 		for _, name := range tables {
 			cp = cp + "insert into plaintext." + name + " select * from main." + name + ";"
 		}
 	} else {
-		cp = `attach database '` + dbPath + `' as encrypted key '` + password + `';`
+		cp = `attach database ` + quote(dbPath) + ` as encrypted key ` + quote(password) + `;`
 		for _, name := range tables {
 			cp = cp + "insert into encrypted." + name + " select * from main." + name + ";"
 		}
 	}
 
 	_, err = d.db.Exec(cp)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return wrapError("copy database", err)
 }
 
 func initDatabaseTables(db *sql.DB, password string) error {
 	var sqlStmt string
 	if password != "" {
-		sqlStmt = "PRAGMA key = '" + password + "';"
+		sqlStmt = "PRAGMA key = " + quote(password) + ";"
 	}
 	sqlStmt = sqlStmt + `
 	PRAGMA user_version = 0;
@@ -173,6 +246,7 @@ func initDatabaseTables(db *sql.DB, password string) error {
 	create index keys_scriptPubKey ON keys(scriptPubKey);
 	create table transactions (txid text primary key not null, tx blob, height integer, state integer, timestamp integer, value integer, exchangeRate real, exchangeCurrency text);
 	create table coins (outpoint text primary key not null, value integer, scriptPubKey text);
+	create table headers (height integer primary key not null, hash text, prevHash text);
 	`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
@@ -187,58 +261,80 @@ type ConfigDB struct {
 	path string
 }
 
-func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string) error {
+func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string, passphrase string, seedVersion int) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if err := initDatabaseTables(c.db, password); err != nil {
-		return err
+		return wrapError("init config", err)
 	}
-	tx, err := c.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("insert into config(key, value) values(?,?)")
-	if err != nil {
-		return err
+	if err := migrate(c.db, "", latestSchemaVersion()); err != nil {
+		return wrapError("init config", err)
 	}
-	defer stmt.Close()
-	_, err = stmt.Exec("mnemonic", mnemonic)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = stmt.Exec("identityKey", identityKey)
-	if err != nil {
-		tx.Rollback()
+	return withTx(c.db, "init config", func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare("insert into config(key, value) values(?,?)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec("mnemonic", mnemonic); err != nil {
+			return err
+		}
+		if _, err := stmt.Exec("identityKey", identityKey); err != nil {
+			return err
+		}
+		if _, err := stmt.Exec("passphrase", passphrase); err != nil {
+			return err
+		}
+		_, err = stmt.Exec("seedVersion", seedVersion)
 		return err
-	}
-	tx.Commit()
-	return nil
-
+	})
 }
 
 func (c *ConfigDB) GetMnemonic() (string, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	stmt, err := c.db.Prepare("select value from config where key=?")
-	defer stmt.Close()
 	var mnemonic string
-	err = stmt.QueryRow("mnemonic").Scan(&mnemonic)
+	err := c.db.QueryRow("select value from config where key=?", "mnemonic").Scan(&mnemonic)
 	if err != nil {
-		log.Fatal(err)
+		return "", wrapError("get mnemonic", err)
 	}
 	return mnemonic, nil
 }
 
+func (c *ConfigDB) GetPassphrase() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var passphrase string
+	err := c.db.QueryRow("select value from config where key=?", "passphrase").Scan(&passphrase)
+	if err == sql.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		return "", wrapError("get passphrase", err)
+	}
+	return passphrase, nil
+}
+
+func (c *ConfigDB) GetSeedVersion() (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var version int
+	err := c.db.QueryRow("select value from config where key=?", "seedVersion").Scan(&version)
+	if err == sql.ErrNoRows {
+		// Databases created before the seed version was saved
+		return repo.SeedVersionLegacy, nil
+	} else if err != nil {
+		return 0, wrapError("get seed version", err)
+	}
+	return version, nil
+}
+
 func (c *ConfigDB) GetIdentityKey() ([]byte, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	stmt, err := c.db.Prepare("select value from config where key=?")
-	defer stmt.Close()
 	var identityKey []byte
-	err = stmt.QueryRow("identityKey").Scan(&identityKey)
+	err := c.db.QueryRow("select value from config where key=?", "identityKey").Scan(&identityKey)
 	if err != nil {
-		log.Fatal(err)
+		return nil, wrapError("get identity key", err)
 	}
 	return identityKey, nil
 }
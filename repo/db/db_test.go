@@ -4,6 +4,8 @@ import (
 	"testing"
 	"path"
 	"os"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 )
 
 var testDB *SQLiteDatastore
@@ -17,8 +19,8 @@ func TestMain(m *testing.M) {
 
 func setup(){
 	os.MkdirAll(path.Join("./", "datastore"), os.ModePerm)
-	testDB, _ = Create("", "LetMeIn", false)
-	testDB.config.Init("Mnemonic Passphrase" , []byte("Private Key"), "LetMeIn")
+	testDB, _ = Create("", "LetMeIn", &chaincfg.MainNetParams)
+	testDB.config.Init("Mnemonic Passphrase" , []byte("Private Key"), "LetMeIn", "TREZOR", repo.SeedVersion1)
 }
 
 func teardown() {
@@ -31,6 +33,20 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateRegtest(t *testing.T) {
+	regtestDB, err := Create("", "", &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Error(err)
+	}
+	defer regtestDB.Close()
+	if err := regtestDB.config.Init("Mnemonic Passphrase", []byte("Private Key"), "", "", repo.SeedVersion1); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(path.Join("./", "datastore", "regtest.db")); os.IsNotExist(err) {
+		t.Error("Failed to create regtest database file")
+	}
+}
+
 func TestInit(t *testing.T) {
 	mn, err := testDB.config.GetMnemonic()
 	if err != nil {
@@ -49,6 +65,36 @@ func TestInit(t *testing.T) {
 			t.Error("Config returned wrong identity key")
 		}
 	}
+	passphrase, err := testDB.config.GetPassphrase()
+	if err != nil || passphrase != "TREZOR" {
+		t.Error("Config returned wrong passphrase")
+	}
+	version, err := testDB.config.GetSeedVersion()
+	if err != nil || version != repo.SeedVersion1 {
+		t.Error("Config returned wrong seed version")
+	}
+}
+
+func TestLegacySeedVersion(t *testing.T) {
+	os.MkdirAll(path.Join("./legacy", "datastore"), os.ModePerm)
+	legacyDB, err := Create("./legacy", "", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("./legacy")
+	defer legacyDB.Close()
+	if err := initDatabaseTables(legacyDB.db, ""); err != nil {
+		t.Fatal(err)
+	}
+	legacyDB.db.Exec("insert into config(key, value) values('mnemonic', 'Mnemonic Passphrase')")
+	version, err := legacyDB.config.GetSeedVersion()
+	if err != nil || version != repo.SeedVersionLegacy {
+		t.Error("Databases without a seed version should use the legacy version")
+	}
+	passphrase, err := legacyDB.config.GetPassphrase()
+	if err != nil || passphrase != "" {
+		t.Error("Databases without a passphrase should return an empty passphrase")
+	}
 }
 
 func TestInterface(t *testing.T) {
@@ -76,4 +122,19 @@ func TestInterface(t *testing.T) {
 	if testDB.Coins() != testDB.coins {
 		t.Error("Coins() return wrong value")
 	}
+	if testDB.Headers() != testDB.headers {
+		t.Error("Headers() return wrong value")
+	}
+	if testDB.Feed() != testDB.feed {
+		t.Error("Feed() return wrong value")
+	}
+	if testDB.SearchIndex() != testDB.searchIndex {
+		t.Error("SearchIndex() return wrong value")
+	}
+	if testDB.Purchases() != testDB.purchases {
+		t.Error("Purchases() return wrong value")
+	}
+	if testDB.Inventory() != testDB.inventory {
+		t.Error("Inventory() return wrong value")
+	}
 }
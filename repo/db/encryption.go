@@ -1,200 +1,108 @@
 package db
 
 import (
-	"bufio"
-	"fmt"
+	"database/sql"
+	"errors"
 	"os"
-	"path"
-	"path/filepath"
-	"strings"
-	"syscall"
-
-	lockfile "github.com/ipfs/go-ipfs/repo/fsrepo/lock"
-	"github.com/mitchellh/go-homedir"
-	"golang.org/x/crypto/ssh/terminal"
 )
 
-// FIXME: the encrypt and decrypt functions here should probably be added to the DB interface
-// FIXME: and the stdin stuff should be moved somewhere outside of this package.
+var (
+	ErrAlreadyEncrypted = errors.New("The database is already encrypted")
+	ErrNotEncrypted     = errors.New("The database is not encrypted")
+	ErrEmptyPassword    = errors.New("The password must not be empty")
+)
 
 // Create a temp encrypted database, read the unencrypted db into it then replace the unencrypted db
-func Encrypt() error {
-	reader := bufio.NewReader(os.Stdin)
-	var repoPath string
-	var filename string
-	var testnet bool
-	for {
-		fmt.Print("Encrypt the mainnet or testnet db?: ")
-		resp, _ := reader.ReadString('\n')
-		if strings.ToLower(resp) == "mainnet\n" {
-			rPath := "~/.openbazaar2"
-			filename = "mainnet.db"
-			testnet = false
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot encrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the node at least once to initialize it.")
-				return nil
-			}
-			break
-		} else if strings.ToLower(resp) == "testnet\n" {
-			rPath := "~/.openbazaar2-testnet"
-			filename = "testnet.db"
-			testnet = true
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot encrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the daemon at least once to initialize it.")
-				return nil
-			}
-			break
-		} else {
-			fmt.Println("No comprende")
-		}
+func (d *SQLiteDatastore) Encrypt(password string) error {
+	if password == "" {
+		return ErrEmptyPassword
 	}
-	var pw string
-	for {
-		fmt.Print("Enter a veerrrry strong password: ")
-		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-		fmt.Println("")
-		resp := string(bytePassword)
-		if len(resp) < 8 {
-			fmt.Println("You call that a password? Try again.")
-		} else if resp != "" {
-			pw = resp
-			break
-		} else {
-			fmt.Println("Seriously, enter a password.")
-		}
+	if d.password != "" || d.config.IsEncrypted() {
+		return ErrAlreadyEncrypted
 	}
-	for {
-		fmt.Print("Confirm your password: ")
-		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-		fmt.Println("")
-		resp := string(bytePassword)
-		if resp == pw {
-			break
-		} else {
-			fmt.Println("Quit effin around. Try again.")
-		}
+	return d.export(password)
+}
+
+// Create a temp database, read the encrypted db into it then replace the encrypted db
+func (d *SQLiteDatastore) Decrypt() error {
+	if d.password == "" {
+		return ErrNotEncrypted
 	}
-	tmpPath := path.Join(repoPath, "tmp")
-	sqlliteDB, err := Create(repoPath, "", testnet)
-	if err != nil {
-		fmt.Println(err)
-		return err
+	return d.export("")
+}
+
+// Change the key of the encrypted database with PRAGMA rekey
+func (d *SQLiteDatastore) ChangePassword(newPassword string) error {
+	if newPassword == "" {
+		return ErrEmptyPassword
 	}
-	if sqlliteDB.Config().IsEncrypted() {
-		fmt.Println("The database is alredy encrypted")
-		return nil
+	if d.password == "" {
+		return ErrNotEncrypted
 	}
-	if err := os.MkdirAll(path.Join(repoPath, "tmp", "datastore"), os.ModePerm); err != nil {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, err := d.db.Exec("PRAGMA rekey = " + quote(newPassword) + ";"); err != nil {
 		return err
 	}
-	tmpDB, err := Create(tmpPath, pw, testnet)
+	// Other connections in the pool still use the old key
+	d.db.Close()
+	conn, err := openConn(d.path, newPassword)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
+	d.setConn(conn)
+	d.password = newPassword
+	return nil
+}
 
-	initDatabaseTables(tmpDB.db, pw)
-	if err := sqlliteDB.Copy(path.Join(tmpPath, "datastore", filename), pw); err != nil {
-		fmt.Println(err)
-		return err
-	}
-	err = os.Rename(path.Join(tmpPath, "datastore", filename), path.Join(repoPath, "datastore", filename))
+// Copy the database into a new file encrypted with password, or unencrypted if it's
+// empty, then replace the database file with it and reopen it
+func (d *SQLiteDatastore) export(password string) error {
+	tmpPath := d.path + ".tmp"
+	os.Remove(tmpPath)
+	tmp, err := openConn(tmpPath, password)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
-	os.RemoveAll(path.Join(tmpPath))
-	fmt.Println("Success! You must now run openbazaard start with the --password flag.")
-	return nil
-}
-
-// Create a temp database, read the encrypted db into it then replace the encrypted db
-func Decrypt() error {
-	reader := bufio.NewReader(os.Stdin)
-	var repoPath string
-	var filename string
-	var testnet bool
-	for {
-		fmt.Print("Decrypt the mainnet or testnet db?: ")
-		resp, _ := reader.ReadString('\n')
-		if strings.ToLower(resp) == "mainnet\n" {
-			rPath := "~/.openbazaar2"
-			filename = "mainnet.db"
-			testnet = false
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot decrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the daemon at least once to initialize it.")
-				return nil
-			}
-			break
-		} else if strings.ToLower(resp) == "testnet\n" {
-			rPath := "~/.openbazaar2-testnet"
-			filename = "testnet.db"
-			testnet = true
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot decrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the node at least once to initialize it.")
-				return nil
-			}
-			break
-		} else {
-			fmt.Println("No comprende")
-		}
+	// The copy needs the same schema as the database
+	version, err := schemaVersion(d.db)
+	if err == nil {
+		err = initDatabaseTables(tmp, password)
 	}
-	fmt.Print("Enter your password: ")
-	bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-	fmt.Println("")
-	pw := string(bytePassword)
-	sqlliteDB, err := Create(repoPath, pw, testnet)
-	if err != nil || sqlliteDB.Config().IsEncrypted() {
-		fmt.Println("Invalid password")
-		return err
+	if err == nil {
+		err = migrate(tmp, "", version)
 	}
-	if err := os.MkdirAll(path.Join(repoPath, "tmp", "datastore"), os.ModePerm); err != nil {
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmpPath)
 		return err
 	}
-	tmpDB, err := Create(path.Join(repoPath, "tmp"), "", testnet)
-	if err != nil {
-		fmt.Println(err)
+	if err := d.Copy(tmpPath, password); err != nil {
+		os.Remove(tmpPath)
 		return err
 	}
-	initDatabaseTables(tmpDB.db, "")
-	if err := sqlliteDB.Copy(path.Join(repoPath, "tmp", "datastore", filename), ""); err != nil {
-		fmt.Println(err)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.db.Close()
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		// Keep using the original database
+		conn, _ := openConn(d.path, d.password)
+		d.setConn(conn)
 		return err
 	}
-	err = os.Rename(path.Join(repoPath, "tmp", "datastore", filename), path.Join(repoPath, "datastore", filename))
+	conn, err := openConn(d.path, password)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
-	os.RemoveAll(path.Join(repoPath, "tmp"))
-	fmt.Println("Success!")
+	d.setConn(conn)
+	d.password = password
 	return nil
 }
+
+// Whether the linked SQLite library is SQLCipher. Without it the key pragmas do nothing.
+func cipherSupported(db *sql.DB) bool {
+	var version string
+	err := db.QueryRow("PRAGMA cipher_version;").Scan(&version)
+	return err == nil && version != ""
+}
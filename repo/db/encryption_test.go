@@ -0,0 +1,109 @@
+package db
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func newEncryptionTestDB(t *testing.T) *SQLiteDatastore {
+	os.MkdirAll(path.Join("./encryption", "datastore"), os.ModePerm)
+	d, err := Create("./encryption", "", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.config.Init("Mnemonic Passphrase", []byte("Private Key"), "", "", repo.SeedVersion1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Followers().Put("QmFollower", []byte("proof"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// Open the database again and check whether it can be read with the password
+func canOpen(t *testing.T, password string) bool {
+	d, err := Create("./encryption", password, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	return !d.Config().IsEncrypted()
+}
+
+func checkContents(t *testing.T, d *SQLiteDatastore) {
+	mn, err := d.Config().GetMnemonic()
+	if err != nil || mn != "Mnemonic Passphrase" {
+		t.Error("Mnemonic was not preserved")
+	}
+	followers, err := d.Followers().Get(0, 10)
+	if err != nil || len(followers) != 1 {
+		t.Error("Followers were not preserved")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	d := newEncryptionTestDB(t)
+	defer os.RemoveAll("./encryption")
+	defer d.Close()
+	if err := d.Encrypt(""); err != ErrEmptyPassword {
+		t.Error("Expected empty password to be refused")
+	}
+	if err := d.Decrypt(); err != ErrNotEncrypted {
+		t.Error("Expected decrypting an unencrypted database to fail")
+	}
+	if err := d.Encrypt("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	checkContents(t, d)
+	if err := d.Encrypt("battery staple"); err != ErrAlreadyEncrypted {
+		t.Error("Expected encrypting twice to fail")
+	}
+	if cipherSupported(d.db) {
+		if canOpen(t, "") {
+			t.Error("Encrypted database opened without a password")
+		}
+		if !canOpen(t, "correct horse") {
+			t.Error("Encrypted database did not open with its password")
+		}
+	}
+	if err := d.Decrypt(); err != nil {
+		t.Fatal(err)
+	}
+	checkContents(t, d)
+	if !canOpen(t, "") {
+		t.Error("Decrypted database did not open without a password")
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	d := newEncryptionTestDB(t)
+	defer os.RemoveAll("./encryption")
+	defer d.Close()
+	if err := d.ChangePassword("battery staple"); err != ErrNotEncrypted {
+		t.Error("Expected changing the password of an unencrypted database to fail")
+	}
+	if err := d.Encrypt("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ChangePassword(""); err != ErrEmptyPassword {
+		t.Error("Expected empty password to be refused")
+	}
+	if !cipherSupported(d.db) {
+		t.Skip("SQLite was not built with SQLCipher")
+	}
+	if err := d.ChangePassword("battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	checkContents(t, d)
+	if canOpen(t, "correct horse") {
+		t.Error("Database opened with the old password")
+	}
+	if !canOpen(t, "battery staple") {
+		t.Error("Database did not open with the new password")
+	}
+}
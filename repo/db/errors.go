@@ -0,0 +1,45 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	sqlite3 "github.com/xeodou/go-sqlcipher"
+)
+
+// Wrap an error from the database driver with the operation that failed. Errors callers
+// can act on are converted to ErrNotFound, ErrWrongPassword or ErrLocked.
+func wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, repo.ErrNotFound)
+	}
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	switch sqliteErr.Code {
+	case sqlite3.ErrNotADB:
+		return fmt.Errorf("%s: %w", op, repo.ErrWrongPassword)
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return fmt.Errorf("%s: %w (%s)", op, repo.ErrLocked, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// Run fn in a transaction, rolling back if it fails
+func withTx(db *sql.DB, op string, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return wrapError(op, err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return wrapError(op, err)
+	}
+	return wrapError(op, tx.Commit())
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func newErrorsTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestNotFound(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	defer conn.Close()
+	initDatabaseTables(conn, "")
+	l := new(sync.Mutex)
+
+	if _, err := (&ConfigDB{db: conn, lock: l}).GetMnemonic(); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetMnemonic returned wrong error:", err)
+	}
+	if _, err := (&ConfigDB{db: conn, lock: l}).GetIdentityKey(); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetIdentityKey returned wrong error:", err)
+	}
+	if _, err := (&KeysDB{db: conn, lock: l}).GetKeyForScript([]byte{0x00}); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetKeyForScript returned wrong error:", err)
+	}
+	if _, err := (&CoinsDB{db: conn, lock: l}).GetValue([]byte{0x00}, 0); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetValue returned wrong error:", err)
+	}
+	if _, err := (&TransactionsDB{db: conn, lock: l}).GetHeight([]byte{0x00}); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetHeight returned wrong error:", err)
+	}
+	if _, err := (&HeadersDB{db: conn, lock: l}).GetHash(1); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetHash returned wrong error:", err)
+	}
+	if _, _, err := (&HeadersDB{db: conn, lock: l}).GetBest(); !errors.Is(err, repo.ErrNotFound) {
+		t.Error("GetBest returned wrong error:", err)
+	}
+
+	// An empty key chain is not an error
+	key, _, err := (&KeysDB{db: conn, lock: l}).GetLastKey(0)
+	if key != nil || err != nil {
+		t.Error("GetLastKey returned an error for an empty table:", err)
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	// Without the right key an encrypted database can't be told apart from a file
+	// that isn't a database
+	dir := newErrorsTestDir(t)
+	defer os.RemoveAll(dir)
+	dbPath := path.Join(dir, "mainnet.db")
+	garbage := make([]byte, 4096)
+	for i := range garbage {
+		garbage[i] = byte(i)
+	}
+	if err := ioutil.WriteFile(dbPath, garbage, 0600); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := openConn(dbPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	l := new(sync.Mutex)
+
+	if _, err := (&ConfigDB{db: conn, lock: l}).GetMnemonic(); !errors.Is(err, repo.ErrWrongPassword) {
+		t.Error("GetMnemonic returned wrong error:", err)
+	}
+	if _, err := (&ConfigDB{db: conn, lock: l}).GetIdentityKey(); !errors.Is(err, repo.ErrWrongPassword) {
+		t.Error("GetIdentityKey returned wrong error:", err)
+	}
+	if err := (&FollowerDB{db: conn, lock: l}).Put("QmFollower", nil, time.Now()); !errors.Is(err, repo.ErrWrongPassword) {
+		t.Error("Put returned wrong error:", err)
+	}
+	if _, err := (&FollowerDB{db: conn, lock: l}).Get(0, -1); !errors.Is(err, repo.ErrWrongPassword) {
+		t.Error("Get returned wrong error:", err)
+	}
+}
+
+func TestLocked(t *testing.T) {
+	dir := newErrorsTestDir(t)
+	defer os.RemoveAll(dir)
+	dbPath := path.Join(dir, "mainnet.db")
+	holder, err := openConn(dbPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	if err := initDatabaseTables(holder, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(holder, "", latestSchemaVersion()); err != nil {
+		t.Fatal(err)
+	}
+	// Hold an exclusive lock on a single connection so other connections can't read or write
+	holder.SetMaxOpenConns(1)
+	if _, err := holder.Exec("BEGIN EXCLUSIVE;"); err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Exec("ROLLBACK;")
+
+	conn, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	l := new(sync.Mutex)
+
+	if _, err := (&ConfigDB{db: conn, lock: l}).GetMnemonic(); !errors.Is(err, repo.ErrLocked) {
+		t.Error("GetMnemonic returned wrong error:", err)
+	}
+	if err := (&FollowerDB{db: conn, lock: l}).Put("QmFollower", nil, time.Now()); !errors.Is(err, repo.ErrLocked) {
+		t.Error("Put returned wrong error:", err)
+	}
+	if err := (&FollowerDB{db: conn, lock: l}).Delete("QmFollower", time.Now()); !errors.Is(err, repo.ErrLocked) {
+		t.Error("Delete returned wrong error:", err)
+	}
+	if err := (&TransactionsDB{db: conn, lock: l}).UpdateHeight([]byte{0x00}, 1); !errors.Is(err, repo.ErrLocked) {
+		t.Error("UpdateHeight returned wrong error:", err)
+	}
+}
+
+func TestCommitError(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	defer conn.Close()
+	initDatabaseTables(conn, "")
+	f := &FollowingDB{db: conn, lock: new(sync.Mutex)}
+	if err := f.Put("QmPeer"); err != nil {
+		t.Fatal(err)
+	}
+	// A constraint failure must be returned rather than dropped
+	if err := f.Put("QmPeer"); err == nil {
+		t.Error("Put of a duplicate peer did not return an error")
+	}
+	if n := f.Count(); n != 1 {
+		t.Errorf("Expected 1 peer after failed insert, got %d", n)
+	}
+}
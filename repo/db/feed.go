@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type FeedDB struct {
+	db   *sql.DB
+	lock *sync.Mutex
+}
+
+func (f *FeedDB) Put(hash string, peerId string, post []byte, timestamp time.Time) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return withTx(f.db, "put post", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert or ignore into feed(hash, peerID, post, timestamp) values(?,?,?,?)", hash, peerId, post, int(timestamp.Unix()))
+		return err
+	})
+}
+
+func (f *FeedDB) Has(hash string) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var ret string
+	err := f.db.QueryRow("select hash from feed where hash=?", hash).Scan(&ret)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error(wrapError("has post", err))
+		}
+		return false
+	}
+	return true
+}
+
+func (f *FeedDB) Get(offset int, limit int) ([]repo.FeedItem, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	stm := "select hash, peerID, post, timestamp from feed order by timestamp desc, rowid desc limit " + strconv.Itoa(limit) + " offset " + strconv.Itoa(offset)
+	rows, err := f.db.Query(stm)
+	if err != nil {
+		return nil, wrapError("get feed", err)
+	}
+	defer rows.Close()
+	var ret []repo.FeedItem
+	for rows.Next() {
+		var item repo.FeedItem
+		var timestamp int
+		if err := rows.Scan(&item.Hash, &item.PeerId, &item.Post, &timestamp); err != nil {
+			return ret, wrapError("get feed", err)
+		}
+		item.Timestamp = time.Unix(int64(timestamp), 0)
+		ret = append(ret, item)
+	}
+	return ret, wrapError("get feed", rows.Err())
+}
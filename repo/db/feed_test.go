@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var feeddb FeedDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	migrate(conn, "", latestSchemaVersion())
+	feeddb = FeedDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutPost(t *testing.T) {
+	err := feeddb.Put("QmPost", "QmPeer", []byte("post"), time.Unix(1000, 0))
+	if err != nil {
+		t.Error(err)
+	}
+	if !feeddb.Has("QmPost") {
+		t.Error("Failed to put post")
+	}
+	// Posts are immutable so fetching one again is ignored
+	err = feeddb.Put("QmPost", "QmPeer", []byte("changed"), time.Unix(1000, 0))
+	if err != nil {
+		t.Error(err)
+	}
+	posts, _ := feeddb.Get(0, -1)
+	if len(posts) != 1 || string(posts[0].Post) != "post" || posts[0].PeerId != "QmPeer" {
+		t.Error("Returned wrong post")
+	}
+	feeddb.db.Exec("delete from feed")
+}
+
+func TestHasPost(t *testing.T) {
+	if feeddb.Has("QmMissing") {
+		t.Error("Has returned true for a missing post")
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	// Inserted out of order as posts from different peers are fetched at different times
+	for _, i := range []int{3, 0, 4, 1, 2} {
+		feeddb.Put("QmPost"+strconv.Itoa(i), "QmPeer", nil, time.Unix(int64(1000+i), 0))
+	}
+	posts, err := feeddb.Get(0, -1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(posts) != 5 {
+		t.Fatalf("Expected 5 posts got %d", len(posts))
+	}
+	for i, p := range posts {
+		if p.Hash != "QmPost"+strconv.Itoa(4-i) {
+			t.Errorf("Returned %s expected QmPost%d", p.Hash, 4-i)
+		}
+	}
+	posts, err = feeddb.Get(1, 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(posts) != 2 || posts[0].Hash != "QmPost3" || posts[1].Timestamp.Unix() != 1002 {
+		t.Error("Returned wrong page")
+	}
+	feeddb.db.Exec("delete from feed")
+}
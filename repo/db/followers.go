@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"strconv"
 	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
 )
 
 type FollowerDB struct {
@@ -11,42 +14,48 @@ type FollowerDB struct {
 	lock *sync.Mutex
 }
 
-func (f *FollowerDB) Put(follower string) error {
+func (f *FollowerDB) Put(follower string, proof []byte, timestamp time.Time) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	tx, _ := f.db.Begin()
-	stmt, _ := tx.Prepare("insert into followers(peerID) values(?)")
-
-	defer stmt.Close()
-	_, err := stmt.Exec(follower)
-	if err != nil {
-		tx.Rollback()
+	return withTx(f.db, "put follower", func(tx *sql.Tx) error {
+		if _, err := tx.Exec("insert or replace into followers(peerID, proof, timestamp) values(?,?,?)", follower, proof, int(timestamp.Unix())); err != nil {
+			return err
+		}
+		_, err := tx.Exec("delete from unfollows where peerID=?", follower)
 		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
-func (f *FollowerDB) Get(offset int, limit int) ([]string, error) {
+func (f *FollowerDB) Get(offset int, limit int) ([]repo.Follower, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	stm := "select peerID from followers order by rowid desc limit " + strconv.Itoa(limit) + " offset " + strconv.Itoa(offset)
-	rows, _ := f.db.Query(stm)
-
-	var ret []string
+	stm := "select peerID, proof from followers order by rowid desc limit " + strconv.Itoa(limit) + " offset " + strconv.Itoa(offset)
+	rows, err := f.db.Query(stm)
+	if err != nil {
+		return nil, wrapError("get followers", err)
+	}
+	defer rows.Close()
+	var ret []repo.Follower
 	for rows.Next() {
-		var peerID string
-		rows.Scan(&peerID)
-		ret = append(ret, peerID)
+		var follower repo.Follower
+		if err := rows.Scan(&follower.PeerId, &follower.Proof); err != nil {
+			return ret, wrapError("get followers", err)
+		}
+		ret = append(ret, follower)
 	}
-	return ret, nil
+	return ret, wrapError("get followers", rows.Err())
 }
 
-func (f *FollowerDB) Delete(follower string) error {
+func (f *FollowerDB) Delete(follower string, timestamp time.Time) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	f.db.Exec("delete from followers where peerID=?", follower)
-	return nil
+	return withTx(f.db, "delete follower", func(tx *sql.Tx) error {
+		if _, err := tx.Exec("delete from followers where peerID=?", follower); err != nil {
+			return err
+		}
+		_, err := tx.Exec("insert or replace into unfollows(peerID, timestamp) values(?,?)", follower, int(timestamp.Unix()))
+		return err
+	})
 }
 
 func (f *FollowerDB) Count() int {
@@ -54,6 +63,23 @@ func (f *FollowerDB) Count() int {
 	defer f.lock.Unlock()
 	row := f.db.QueryRow("select Count(*) from followers")
 	var count int
-	row.Scan(&count)
+	if err := row.Scan(&count); err != nil {
+		log.Error(wrapError("count followers", err))
+	}
 	return count
 }
+
+func (f *FollowerDB) LastUpdate(follower string) (time.Time, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	stm := "select timestamp from followers where peerID=? union all select timestamp from unfollows where peerID=? order by timestamp desc limit 1"
+	var timestamp sql.NullInt64
+	err := f.db.QueryRow(stm, follower, follower).Scan(&timestamp)
+	if err == sql.ErrNoRows || (err == nil && !timestamp.Valid) {
+		// Followers from before proofs were stored have no timestamp
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, wrapError("get last follow update", err)
+	}
+	return time.Unix(timestamp.Int64, 0), nil
+}
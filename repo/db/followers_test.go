@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"testing"
 	"strconv"
+	"time"
 )
 
 var fdb FollowerDB
@@ -12,6 +13,7 @@ var fdb FollowerDB
 func init(){
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
+	migrate(conn, "", latestSchemaVersion())
 	fdb = FollowerDB{
 		db: conn,
 		lock: new(sync.Mutex),
@@ -19,46 +21,64 @@ func init(){
 }
 
 func TestPutFollower(t *testing.T) {
-	err := fdb.Put("abc")
+	err := fdb.Put("abc", []byte("proof"), time.Now())
 	if err != nil {
 		t.Error(err)
 	}
-	stmt, err := fdb.db.Prepare("select peerID from followers where peerID=?")
+	stmt, err := fdb.db.Prepare("select peerID, proof from followers where peerID=?")
 	defer stmt.Close()
 	var follower string
-	err = stmt.QueryRow("abc").Scan(&follower)
+	var proof []byte
+	err = stmt.QueryRow("abc").Scan(&follower, &proof)
 	if err != nil {
 		t.Error(err)
 	}
 	if follower != "abc" {
 		t.Errorf(`Expected "abc" got %s`, follower)
 	}
+	if string(proof) != "proof" {
+		t.Errorf(`Expected "proof" got %s`, string(proof))
+	}
 }
 
 func TestPutDuplicateFollower(t *testing.T) {
-	fdb.Put("abc")
-	err := fdb.Put("abc")
-	if err == nil {
-		t.Error("Expected unquire constriant error to be thrown")
+	fdb.Put("abc", []byte("old"), time.Now())
+	err := fdb.Put("abc", []byte("new"), time.Now())
+	if err != nil {
+		t.Error(err)
+	}
+	followers, _ := fdb.Get(0, -1)
+	n := 0
+	for _, f := range followers {
+		if f.PeerId == "abc" {
+			n++
+			if string(f.Proof) != "new" {
+				t.Error("Following again did not replace the proof")
+			}
+		}
 	}
+	if n != 1 {
+		t.Errorf("Expected 1 follower got %d", n)
+	}
+	fdb.Delete("abc", time.Now())
 }
 
 func TestCountFollowers(t *testing.T){
-	fdb.Put("abc")
-	fdb.Put("123")
-	fdb.Put("xyz")
+	fdb.Put("abc", nil, time.Now())
+	fdb.Put("123", nil, time.Now())
+	fdb.Put("xyz", nil, time.Now())
 	x := fdb.Count()
 	if x != 3 {
 		t.Errorf("Expected 3 got %d", x)
 	}
-	fdb.Delete("abc")
-	fdb.Delete("123")
-	fdb.Delete("xyz")
+	fdb.Delete("abc", time.Now())
+	fdb.Delete("123", time.Now())
+	fdb.Delete("xyz", time.Now())
 }
 
 func TestDeleteFollower(t *testing.T){
-	fdb.Put("abc")
-	err := fdb.Delete("abc")
+	fdb.Put("abc", nil, time.Now())
+	err := fdb.Delete("abc", time.Now())
 	if err != nil {
 		t.Error(err)
 	}
@@ -71,16 +91,46 @@ func TestDeleteFollower(t *testing.T){
 	}
 }
 
+func TestFollowerLastUpdate(t *testing.T){
+	last, err := fdb.LastUpdate("def")
+	if err != nil {
+		t.Error(err)
+	}
+	if !last.IsZero() {
+		t.Error("Expected zero time for unknown peer")
+	}
+	followed := time.Unix(1000, 0)
+	fdb.Put("def", nil, followed)
+	last, _ = fdb.LastUpdate("def")
+	if !last.Equal(followed) {
+		t.Errorf("Expected %s got %s", followed, last)
+	}
+	// The unfollow time is remembered after the follower is removed
+	unfollowed := time.Unix(2000, 0)
+	fdb.Delete("def", unfollowed)
+	last, _ = fdb.LastUpdate("def")
+	if !last.Equal(unfollowed) {
+		t.Errorf("Expected %s got %s", unfollowed, last)
+	}
+	refollowed := time.Unix(3000, 0)
+	fdb.Put("def", nil, refollowed)
+	last, _ = fdb.LastUpdate("def")
+	if !last.Equal(refollowed) {
+		t.Errorf("Expected %s got %s", refollowed, last)
+	}
+	fdb.Delete("def", time.Now())
+}
+
 func TestGetFollowers(t *testing.T){
 	for i:=0; i<100; i++ {
-		fdb.Put(strconv.Itoa(i))
+		fdb.Put(strconv.Itoa(i), nil, time.Now())
 	}
 	followers, err := fdb.Get(0, 100)
 	if err != nil {
 		t.Error(err)
 	}
 	for i:=0; i < 100; i++ {
-		f, _ := strconv.Atoi(followers[i])
+		f, _ := strconv.Atoi(followers[i].PeerId)
 		if f != 99- i {
 			t.Errorf("Returned %d expected %d", f, 99 - i)
 		}
@@ -91,7 +141,7 @@ func TestGetFollowers(t *testing.T){
 		t.Error(err)
 	}
 	for i:=0; i < 70; i++ {
-		f, _ := strconv.Atoi(followers[i])
+		f, _ := strconv.Atoi(followers[i].PeerId)
 		if f != 69- i {
 			t.Errorf("Returned %d expected %d", f, 69 - i)
 		}
@@ -108,7 +158,7 @@ func TestGetFollowers(t *testing.T){
 		t.Error("Incorrect number of followers returned")
 	}
 	for i:=0; i < 5; i++ {
-		f, _ := strconv.Atoi(followers[i])
+		f, _ := strconv.Atoi(followers[i].PeerId)
 		if f != 69- i {
 			t.Errorf("Returned %d expected %d", f, 69 - i)
 		}
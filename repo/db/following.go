@@ -11,40 +11,40 @@ type FollowingDB struct {
 	lock *sync.Mutex
 }
 
-func (f *FollowingDB) Put(follower string) error {
+func (f *FollowingDB) Put(peer string) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	tx, _ := f.db.Begin()
-	stmt, _ := tx.Prepare("insert into following(peerID) values(?)")
-	defer stmt.Close()
-	_, err := stmt.Exec(follower)
-	if err != nil {
-		tx.Rollback()
+	return withTx(f.db, "put following", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into following(peerID) values(?)", peer)
 		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (f *FollowingDB) Get(offset int, limit int) ([]string, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	stm := "select peerID from following order by rowid desc limit " + strconv.Itoa(limit) + " offset " + strconv.Itoa(offset)
-	rows, _ := f.db.Query(stm)
+	rows, err := f.db.Query(stm)
+	if err != nil {
+		return nil, wrapError("get following", err)
+	}
+	defer rows.Close()
 	var ret []string
 	for rows.Next() {
 		var peerID string
-		rows.Scan(&peerID)
+		if err := rows.Scan(&peerID); err != nil {
+			return ret, wrapError("get following", err)
+		}
 		ret = append(ret, peerID)
 	}
-	return ret, nil
+	return ret, wrapError("get following", rows.Err())
 }
 
-func (f *FollowingDB) Delete(follower string) error {
+func (f *FollowingDB) Delete(peer string) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	f.db.Exec("delete from following where peerID=?", follower)
-	return nil
+	_, err := f.db.Exec("delete from following where peerID=?", peer)
+	return wrapError("delete following", err)
 }
 
 func (f *FollowingDB) Count() int {
@@ -52,6 +52,8 @@ func (f *FollowingDB) Count() int {
 	defer f.lock.Unlock()
 	row := f.db.QueryRow("select Count(*) from following")
 	var count int
-	row.Scan(&count)
+	if err := row.Scan(&count); err != nil {
+		log.Error(wrapError("count following", err))
+	}
 	return count
 }
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"sync"
+)
+
+type HeadersDB struct {
+	db   *sql.DB
+	lock *sync.Mutex
+}
+
+func (h *HeadersDB) Put(height int, hash []byte, prevHash []byte) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return withTx(h.db, "put header", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert or replace into headers(height, hash, prevHash) values(?,?,?)", height, hex.EncodeToString(hash), hex.EncodeToString(prevHash))
+		return err
+	})
+}
+
+func (h *HeadersDB) GetHash(height int) ([]byte, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	var hash string
+	err := h.db.QueryRow("select hash from headers where height=?", height).Scan(&hash)
+	if err != nil {
+		return nil, wrapError("get header hash", err)
+	}
+	return hex.DecodeString(hash)
+}
+
+func (h *HeadersDB) GetBest() (int, []byte, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	var height int
+	var hash string
+	err := h.db.QueryRow("select height, hash from headers order by height desc limit 1").Scan(&height, &hash)
+	if err != nil {
+		return 0, nil, wrapError("get best header", err)
+	}
+	b, err := hex.DecodeString(hash)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, b, nil
+}
+
+func (h *HeadersDB) DeleteAbove(height int) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err := h.db.Exec("delete from headers where height>?", height)
+	return wrapError("delete headers", err)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"sync"
+	"testing"
+)
+
+var hdb HeadersDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	hdb = HeadersDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutHeader(t *testing.T) {
+	err := hdb.Put(100, []byte{0x01}, []byte{0x00})
+	if err != nil {
+		t.Error(err)
+	}
+	hash, err := hdb.GetHash(100)
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(hash, []byte{0x01}) {
+		t.Errorf("Expected 01 got %x", hash)
+	}
+	hdb.DeleteAbove(0)
+}
+
+func TestPutHeaderReplaces(t *testing.T) {
+	hdb.Put(100, []byte{0x01}, []byte{0x00})
+	err := hdb.Put(100, []byte{0x02}, []byte{0x00})
+	if err != nil {
+		t.Error(err)
+	}
+	hash, _ := hdb.GetHash(100)
+	if !bytes.Equal(hash, []byte{0x02}) {
+		t.Errorf("Expected 02 got %x", hash)
+	}
+	hdb.DeleteAbove(0)
+}
+
+func TestGetBestHeader(t *testing.T) {
+	_, _, err := hdb.GetBest()
+	if err == nil {
+		t.Error("Expected error fetching best header from empty table")
+	}
+	for i := 1; i <= 10; i++ {
+		hdb.Put(i, []byte{byte(i)}, []byte{byte(i - 1)})
+	}
+	height, hash, err := hdb.GetBest()
+	if err != nil {
+		t.Error(err)
+	}
+	if height != 10 {
+		t.Errorf("Expected height 10 got %d", height)
+	}
+	if !bytes.Equal(hash, []byte{0x0a}) {
+		t.Errorf("Expected 0a got %x", hash)
+	}
+	hdb.DeleteAbove(0)
+}
+
+func TestDeleteHeadersAbove(t *testing.T) {
+	for i := 1; i <= 10; i++ {
+		hdb.Put(i, []byte{byte(i)}, []byte{byte(i - 1)})
+	}
+	err := hdb.DeleteAbove(5)
+	if err != nil {
+		t.Error(err)
+	}
+	height, _, _ := hdb.GetBest()
+	if height != 5 {
+		t.Errorf("Expected height 5 got %d", height)
+	}
+	if _, err := hdb.GetHash(6); err == nil {
+		t.Error("Failed to delete header")
+	}
+	hdb.DeleteAbove(0)
+}
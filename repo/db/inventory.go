@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type InventoryDB struct {
+	db   *sql.DB
+	lock *sync.Mutex
+}
+
+func (i *InventoryDB) Put(listingName string, variant string, count int) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return withTx(i.db, "put inventory", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert or replace into inventory(listingName, variant, count) values(?,?,?)", listingName, variant, count)
+		return err
+	})
+}
+
+func (i *InventoryDB) Get(listingName string) (map[string]int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	rows, err := i.db.Query("select variant, count from inventory where listingName=?", listingName)
+	if err != nil {
+		return nil, wrapError("get inventory", err)
+	}
+	defer rows.Close()
+	stock := make(map[string]int)
+	for rows.Next() {
+		var variant string
+		var count int
+		if err := rows.Scan(&variant, &count); err != nil {
+			return nil, wrapError("get inventory", err)
+		}
+		stock[variant] = count
+	}
+	return stock, wrapError("get inventory", rows.Err())
+}
+
+func (i *InventoryDB) Delete(listingName string, variant string) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	_, err := i.db.Exec("delete from inventory where listingName=? and variant=?", listingName, variant)
+	return wrapError("delete inventory", err)
+}
+
+func (i *InventoryDB) Reserve(items []repo.InventoryItem) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return withTx(i.db, "reserve inventory", func(tx *sql.Tx) error {
+		for _, item := range items {
+			var count int
+			err := tx.QueryRow("select count from inventory where listingName=? and variant=?", item.ListingName, item.Variant).Scan(&count)
+			if err == sql.ErrNoRows {
+				continue
+			} else if err != nil {
+				return err
+			}
+			// An order may have several items of the same variant so the count is
+			// read again for each of them
+			if count < item.Quantity {
+				return repo.ErrInsufficientStock
+			}
+			if _, err := tx.Exec("update inventory set count=? where listingName=? and variant=?", count-item.Quantity, item.ListingName, item.Variant); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (i *InventoryDB) Restore(items []repo.InventoryItem) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return withTx(i.db, "restore inventory", func(tx *sql.Tx) error {
+		for _, item := range items {
+			if _, err := tx.Exec("update inventory set count=count+? where listingName=? and variant=?", item.Quantity, item.ListingName, item.Variant); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
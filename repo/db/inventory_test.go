@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var invdb InventoryDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	migrate(conn, "", latestSchemaVersion())
+	invdb = InventoryDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutInventory(t *testing.T) {
+	if err := invdb.Put("shoes", "Color=Red,Size=9", 5); err != nil {
+		t.Error(err)
+	}
+	if err := invdb.Put("shoes", "Color=Red,Size=10", 2); err != nil {
+		t.Error(err)
+	}
+	if err := invdb.Put("shoes", "Color=Red,Size=10", 3); err != nil {
+		t.Error(err)
+	}
+	stock, err := invdb.Get("shoes")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(stock) != 2 || stock["Color=Red,Size=9"] != 5 || stock["Color=Red,Size=10"] != 3 {
+		t.Error("Returned wrong stock")
+	}
+}
+
+func TestDeleteInventory(t *testing.T) {
+	invdb.Put("hat", "", 1)
+	if err := invdb.Delete("hat", ""); err != nil {
+		t.Error(err)
+	}
+	stock, err := invdb.Get("hat")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(stock) != 0 {
+		t.Error("Failed to delete inventory")
+	}
+}
+
+func TestReserveInventory(t *testing.T) {
+	invdb.Put("shirt", "Size=S", 2)
+	invdb.Put("shirt", "Size=M", 1)
+	items := []repo.InventoryItem{
+		{ListingName: "shirt", Variant: "Size=S", Quantity: 2},
+		{ListingName: "shirt", Variant: "Size=M", Quantity: 1},
+		{ListingName: "shirt", Variant: "Size=L", Quantity: 10}, // untracked
+	}
+	if err := invdb.Reserve(items); err != nil {
+		t.Error(err)
+	}
+	stock, _ := invdb.Get("shirt")
+	if stock["Size=S"] != 0 || stock["Size=M"] != 0 {
+		t.Error("Failed to reserve inventory")
+	}
+	if err := invdb.Restore(items); err != nil {
+		t.Error(err)
+	}
+	stock, _ = invdb.Get("shirt")
+	if stock["Size=S"] != 2 || stock["Size=M"] != 1 {
+		t.Error("Failed to restore inventory")
+	}
+	if _, ok := stock["Size=L"]; ok {
+		t.Error("Restored untracked variant")
+	}
+}
+
+func TestReserveInsufficientStock(t *testing.T) {
+	invdb.Put("socks", "Size=S", 1)
+	invdb.Put("socks", "Size=M", 1)
+	// The second item of the same variant is one too many
+	items := []repo.InventoryItem{
+		{ListingName: "socks", Variant: "Size=M", Quantity: 1},
+		{ListingName: "socks", Variant: "Size=S", Quantity: 1},
+		{ListingName: "socks", Variant: "Size=S", Quantity: 1},
+	}
+	if err := invdb.Reserve(items); !errors.Is(err, repo.ErrInsufficientStock) {
+		t.Error("Reserved more than in stock")
+	}
+	stock, _ := invdb.Get("socks")
+	if stock["Size=S"] != 1 || stock["Size=M"] != 1 {
+		t.Error("Failed reservation changed the stock")
+	}
+}
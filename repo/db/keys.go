@@ -5,7 +5,6 @@ import (
 	"sync"
 	"strconv"
 	"encoding/hex"
-	"errors"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	b32 "github.com/tyler-smith/go-bip32"
 )
@@ -18,37 +17,19 @@ type KeysDB struct {
 func (k *KeysDB) Put(key *b32.Key, scriptPubKey []byte, purpose bitcoin.KeyPurpose) error {
 	k.lock.Lock()
 	defer k.lock.Unlock()
-	tx, _ := k.db.Begin()
-	stmt, _ := tx.Prepare("insert into keys(key, scriptPubKey, purpose, used) values(?,?,?,?)")
-	defer stmt.Close()
-	_, err := stmt.Exec(key.String(), hex.EncodeToString(scriptPubKey), int(purpose), 0)
-	if err != nil {
-		tx.Rollback()
+	return withTx(k.db, "put key", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into keys(key, scriptPubKey, purpose, used) values(?,?,?,?)", key.String(), hex.EncodeToString(scriptPubKey), int(purpose), 0)
 		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (k *KeysDB) MarkKeyAsUsed(key *b32.Key) error {
 	k.lock.Lock()
 	defer k.lock.Unlock()
-	tx, err := k.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("update keys set used=1 where key=?")
-	if err != nil {
+	return withTx(k.db, "mark key as used", func(tx *sql.Tx) error {
+		_, err := tx.Exec("update keys set used=1 where key=?", key.String())
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(key.String())
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (k *KeysDB) GetLastKey(purpose bitcoin.KeyPurpose) (*b32.Key, bool, error) {
@@ -56,13 +37,14 @@ func (k *KeysDB) GetLastKey(purpose bitcoin.KeyPurpose) (*b32.Key, bool, error)
 	defer k.lock.Unlock()
 
 	stm := "select key, used from keys where purpose=" + strconv.Itoa(int(purpose)) + " order by rowid desc limit 1"
-	stmt, err := k.db.Prepare(stm)
-	defer stmt.Close()
 	var key string
 	var usedInt int
-	err = stmt.QueryRow().Scan(&key, &usedInt)
-	if err != nil {
+	err := k.db.QueryRow(stm).Scan(&key, &usedInt)
+	if err == sql.ErrNoRows {
+		// No keys have been generated for this purpose yet
 		return nil, false, nil
+	} else if err != nil {
+		return nil, false, wrapError("get last key", err)
 	}
 	var used bool
 	if usedInt == 0 {
@@ -81,12 +63,10 @@ func (k *KeysDB) GetKeyForScript(scriptPubKey []byte) (*b32.Key, error) {
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
-	stmt, err := k.db.Prepare("select key from keys where scriptPubKey=?")
-	defer stmt.Close()
 	var key string
-	err = stmt.QueryRow(hex.EncodeToString(scriptPubKey)).Scan(&key)
+	err := k.db.QueryRow("select key from keys where scriptPubKey=?", hex.EncodeToString(scriptPubKey)).Scan(&key)
 	if err != nil {
-		return nil, errors.New("Key not found")
+		return nil, wrapError("get key for script", err)
 	}
 	b32key, err := b32.B58Deserialize(key)
 	if err != nil {
@@ -102,13 +82,13 @@ func (k *KeysDB) GetAll() ([]*b32.Key, error) {
 	stm := "select key from keys"
 	rows, err := k.db.Query(stm)
 	if err != nil {
-		log.Error(err)
-		return ret, err
+		return ret, wrapError("get keys", err)
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var serializedKey string
 		if err := rows.Scan(&serializedKey); err != nil {
-			log.Error(err)
+			return ret, wrapError("get keys", err)
 		}
 		b32key, err := b32.B58Deserialize(serializedKey)
 		if err != nil {
@@ -116,5 +96,29 @@ func (k *KeysDB) GetAll() ([]*b32.Key, error) {
 		}
 		ret = append(ret, b32key)
 	}
-	return ret, nil
+	return ret, wrapError("get keys", rows.Err())
+}
+
+func (k *KeysDB) GetAllScripts() ([][]byte, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	var ret [][]byte
+	stm := "select scriptPubKey from keys"
+	rows, err := k.db.Query(stm)
+	if err != nil {
+		return ret, wrapError("get scripts", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var scriptHex string
+		if err := rows.Scan(&scriptHex); err != nil {
+			return ret, wrapError("get scripts", err)
+		}
+		script, err := hex.DecodeString(scriptHex)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, script)
+	}
+	return ret, wrapError("get scripts", rows.Err())
 }
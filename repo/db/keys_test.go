@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"sync"
 	"database/sql"
 	"testing"
@@ -49,3 +50,26 @@ func TestPutDuplicateKey(t *testing.T) {
 		t.Error("Expected unquire constriant error to be thrown")
 	}
 }
+
+func TestGetAllScripts(t *testing.T) {
+	seed, _ := bip32.NewSeed()
+	key, _ := bip32.NewMasterKey(seed)
+	script := []byte{0xa9, 0x14, 0x01, 0x87}
+	err := keysdb.Put(key, script, bitcoin.CHANGE)
+	if err != nil {
+		t.Error(err)
+	}
+	scripts, err := keysdb.GetAllScripts()
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, s := range scripts {
+		if bytes.Equal(s, script) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Failed to fetch script")
+	}
+}
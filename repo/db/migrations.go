@@ -0,0 +1,135 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Schema migrations. Migration i upgrades a database at user_version i to i+1. New
+// migrations are appended to the end and existing ones must never change as databases
+// in the wild were upgraded by them.
+var migrations = []func(tx *sql.Tx) error{
+	// 1: index transactions by height for GetUnconfirmed
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec("create index transactions_height on transactions(height);")
+		return err
+	},
+	// 2: store the signed proof of each follow and the time of each unfollow
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		alter table followers add column proof blob;
+		alter table followers add column timestamp integer;
+		create table unfollows (peerID text primary key not null, timestamp integer);
+		`)
+		return err
+	},
+	// 3: store our posts and the posts of the peers we follow
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		create table feed (hash text primary key not null, peerID text, post blob, timestamp integer);
+		create index feed_timestamp on feed(timestamp);
+		`)
+		return err
+	},
+	// 4: the listing search index
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		create table searchindex (id integer primary key, hash text unique not null, peerID text, listingName text, title text, description text, tags text, category integer, price integer, currencyCode text, fiatPrice real, timestamp integer);
+		create unique index searchindex_listing on searchindex(peerID, listingName);
+		create virtual table searchtext using fts4(title, description, tags);
+		create table searchregions (listingID integer, region integer);
+		create index searchregions_listing on searchregions(listingID);
+		`)
+		return err
+	},
+	// 5: the contracts and states of the orders we placed
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec("create table purchases (orderHash text primary key not null, contract blob, state integer, timestamp integer);")
+		return err
+	},
+	// 6: the stock of each variant of our listings
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec("create table inventory (listingName text not null, variant text not null, count integer, primary key(listingName, variant));")
+		return err
+	},
+}
+
+// The schema version of databases created by this version of the code
+func latestSchemaVersion() int {
+	return len(migrations)
+}
+
+// Bring the schema up to date. The database file is copied before the first migration
+// so a failed upgrade can be recovered from.
+func (d *SQLiteDatastore) Migrate() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return wrapError("migrate database", migrate(d.db, d.path, latestSchemaVersion()))
+}
+
+func schemaVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version;").Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Run the migrations from the database's version up to target, each in its own
+// transaction along with the version update. If dbPath is set a backup is taken first.
+func migrate(db *sql.DB, dbPath string, target int) error {
+	version, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if version > latestSchemaVersion() {
+		return fmt.Errorf("The database schema version %d is newer than this version of openbazaard supports", version)
+	}
+	if version >= target {
+		return nil
+	}
+	if dbPath != "" {
+		backupPath := fmt.Sprintf("%s.v%d.bak", dbPath, version)
+		if err := copyFile(dbPath, backupPath); err != nil {
+			return err
+		}
+		log.Infof("Upgrading database schema from version %d to %d, backup saved to %s\n", version, target, backupPath)
+	}
+	for v := version; v < target; v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := migrations[v](tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Database migration %d failed: %w", v+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", v+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
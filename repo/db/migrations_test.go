@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Create a database with the original schema as initDatabaseTables makes it
+func newV0Database(t *testing.T) *SQLiteDatastore {
+	os.MkdirAll(path.Join("./migrations", "datastore"), os.ModePerm)
+	d, err := Create("./migrations", "", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := initDatabaseTables(d.db, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.db.Exec("insert into transactions(txid, tx, height, state, timestamp, value, exchangeRate, exchangeCurrency) values('abcd', x'00', 0, 0, 0, 0, 0, '');"); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestMigrateV0(t *testing.T) {
+	d := newV0Database(t)
+	defer os.RemoveAll("./migrations")
+	defer d.Close()
+	if err := d.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	version, err := schemaVersion(d.db)
+	if err != nil || version != latestSchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d", latestSchemaVersion(), version)
+	}
+	var name string
+	if err := d.db.QueryRow("select name from sqlite_master where type='index' and name='transactions_height'").Scan(&name); err != nil {
+		t.Error("Migration 1 was not applied")
+	}
+	if len(d.Transactions().GetUnconfirmed()) != 1 {
+		t.Error("Existing rows were not preserved")
+	}
+	if _, err := os.Stat(d.path + ".v0.bak"); err != nil {
+		t.Error("No backup was taken before migrating")
+	}
+
+	// Migrating again does nothing
+	os.Remove(d.path + ".v0.bak")
+	if err := d.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(d.path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Error("Up to date database should not be backed up")
+	}
+}
+
+func TestMigrateFailureRollsBack(t *testing.T) {
+	d := newV0Database(t)
+	defer os.RemoveAll("./migrations")
+	defer d.Close()
+	original := migrations
+	defer func() { migrations = original }()
+	migrations = append(migrations, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("create table broken (id integer);"); err != nil {
+			return err
+		}
+		return errors.New("failed")
+	})
+	if err := d.Migrate(); err == nil {
+		t.Fatal("Expected migration to fail")
+	}
+	version, _ := schemaVersion(d.db)
+	if version != len(original) {
+		t.Errorf("Expected the database to stay at version %d, got %d", len(original), version)
+	}
+	if _, err := d.db.Exec("select * from broken"); err == nil {
+		t.Error("Failed migration was not rolled back")
+	}
+}
+
+func TestNewDatabaseIsLatestVersion(t *testing.T) {
+	version, err := schemaVersion(testDB.db)
+	if err != nil || version != latestSchemaVersion() {
+		t.Errorf("Expected a new database to be at version %d, got %d", latestSchemaVersion(), version)
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	d := newV0Database(t)
+	defer os.RemoveAll("./migrations")
+	defer d.Close()
+	d.db.Exec("PRAGMA user_version = 1000;")
+	if err := d.Migrate(); err == nil {
+		t.Error("Expected a database from a newer version to be refused")
+	}
+}
@@ -14,32 +14,21 @@ type OfflineMessagesDB struct {
 func (o *OfflineMessagesDB) Put(url string) error {
 	o.lock.Lock()
 	defer o.lock.Unlock()
-	tx, err := o.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("insert into offlinemessages(url, timestamp) values(?,?)")
-	if err != nil {
+	return withTx(o.db, "put offline message", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into offlinemessages(url, timestamp) values(?,?)", url, int(time.Now().Unix()))
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(url, int(time.Now().Unix()))
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (o *OfflineMessagesDB) Has(url string) bool {
 	o.lock.Lock()
 	defer o.lock.Unlock()
-	stmt, err := o.db.Prepare("select url from offlinemessages where url=?")
-	defer stmt.Close()
 	var ret string
-	err = stmt.QueryRow(url).Scan(&ret)
+	err := o.db.QueryRow("select url from offlinemessages where url=?", url).Scan(&ret)
 	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error(wrapError("has offline message", err))
+		}
 		return false
 	}
 	return true
@@ -18,33 +18,17 @@ type PointersDB struct {
 func (p *PointersDB) Put(pointer ipfs.Pointer) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	tx, err := p.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("insert into pointers(pointerID, key, address, purpose, timestamp) values(?,?,?,?,?)")
-	if err != nil {
+	return withTx(p.db, "put pointer", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into pointers(pointerID, key, address, purpose, timestamp) values(?,?,?,?,?)", pointer.Value.ID.Pretty(), pointer.Key.B58String(), pointer.Value.Addrs[0].String(), pointer.Purpose, int(time.Now().Unix()))
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(pointer.Value.ID.Pretty(), pointer.Key.B58String(), pointer.Value.Addrs[0].String(), pointer.Purpose, int(time.Now().Unix()))
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (p *PointersDB) Delete(id peer.ID) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	_, err := p.db.Exec("delete from pointers where pointerID=?", id.Pretty())
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-	return nil
+	return wrapError("delete pointer", err)
 }
 
 func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
@@ -53,9 +37,9 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 	stm := "select * from pointers"
 	rows, err := p.db.Query(stm)
 	if err != nil {
-		log.Error(err)
-		return nil, err
+		return nil, wrapError("get pointers", err)
 	}
+	defer rows.Close()
 	var ret []ipfs.Pointer
 	for rows.Next() {
 		var pointerID string
@@ -64,7 +48,7 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 		var purpose int
 		var timestamp int
 		if err := rows.Scan(&pointerID, &key, &address, &purpose, &timestamp); err != nil {
-			log.Error(err)
+			return ret, wrapError("get pointers", err)
 		}
 		maAddr, err := ma.NewMultiaddr(address)
 		if err != nil {
@@ -85,6 +69,6 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 		}
 		ret = append(ret, pointer)
 	}
-	return ret, nil
+	return ret, wrapError("get pointers", rows.Err())
 }
 
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type PurchasesDB struct {
+	db   *sql.DB
+	lock *sync.Mutex
+}
+
+func (p *PurchasesDB) Put(orderHash string, contract []byte, state repo.OrderState, timestamp time.Time) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return withTx(p.db, "put purchase", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert or replace into purchases(orderHash, contract, state, timestamp) values(?,?,?,?)", orderHash, contract, int(state), int(timestamp.Unix()))
+		return err
+	})
+}
+
+func (p *PurchasesDB) Get(orderHash string) ([]byte, repo.OrderState, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var contract []byte
+	var state int
+	err := p.db.QueryRow("select contract, state from purchases where orderHash=?", orderHash).Scan(&contract, &state)
+	if err != nil {
+		return nil, 0, wrapError("get purchase", err)
+	}
+	return contract, repo.OrderState(state), nil
+}
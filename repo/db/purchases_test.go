@@ -0,0 +1,59 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var purdb PurchasesDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	migrate(conn, "", latestSchemaVersion())
+	purdb = PurchasesDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutPurchase(t *testing.T) {
+	err := purdb.Put("abcd", []byte("contract"), repo.ORDER_PENDING, time.Unix(1000, 0))
+	if err != nil {
+		t.Error(err)
+	}
+	contract, state, err := purdb.Get("abcd")
+	if err != nil {
+		t.Error(err)
+	}
+	if string(contract) != "contract" || state != repo.ORDER_PENDING {
+		t.Error("Returned wrong purchase")
+	}
+}
+
+func TestUpdatePurchaseState(t *testing.T) {
+	purdb.Put("ef01", []byte("contract"), repo.ORDER_PENDING, time.Unix(1000, 0))
+	err := purdb.Put("ef01", []byte("refunded contract"), repo.ORDER_REFUNDED, time.Unix(2000, 0))
+	if err != nil {
+		t.Error(err)
+	}
+	contract, state, err := purdb.Get("ef01")
+	if err != nil {
+		t.Error(err)
+	}
+	if string(contract) != "refunded contract" || state != repo.ORDER_REFUNDED {
+		t.Error("Failed to update purchase")
+	}
+}
+
+func TestGetMissingPurchase(t *testing.T) {
+	_, _, err := purdb.Get("missing")
+	if !errors.Is(err, repo.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// The shipping region which matches every country
+const regionAll = 1
+
+type SearchIndexDB struct {
+	db   *sql.DB
+	lock *sync.Mutex
+}
+
+func (s *SearchIndexDB) Put(listing repo.SearchListing) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tags, err := json.Marshal(listing.Tags)
+	if err != nil {
+		return err
+	}
+	return withTx(s.db, "put search listing", func(tx *sql.Tx) error {
+		// Drop the earlier version of the listing
+		var id int64
+		err := tx.QueryRow("select id from searchindex where hash=? or (peerID=? and listingName=?)", listing.Hash, listing.PeerId, listing.ListingName).Scan(&id)
+		if err == nil {
+			if err := deleteSearchListing(tx, id); err != nil {
+				return err
+			}
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+		res, err := tx.Exec("insert into searchindex(hash, peerID, listingName, title, description, tags, category, price, currencyCode, fiatPrice, timestamp) values(?,?,?,?,?,?,?,?,?,?,?)",
+			listing.Hash, listing.PeerId, listing.ListingName, listing.Title, listing.Description, string(tags), listing.Category, int64(listing.Price), listing.CurrencyCode, listing.FiatPrice, int(listing.Timestamp.Unix()))
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("insert into searchtext(docid, title, description, tags) values(?,?,?,?)", id, listing.Title, listing.Description, strings.Join(listing.Tags, " ")); err != nil {
+			return err
+		}
+		for _, region := range listing.ShipsTo {
+			if _, err := tx.Exec("insert into searchregions(listingID, region) values(?,?)", id, region); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SearchIndexDB) Has(hash string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var ret string
+	err := s.db.QueryRow("select hash from searchindex where hash=?", hash).Scan(&ret)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error(wrapError("has search listing", err))
+		}
+		return false
+	}
+	return true
+}
+
+func (s *SearchIndexDB) Prune(peerId string, keep []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return withTx(s.db, "prune search index", func(tx *sql.Tx) error {
+		rows, err := tx.Query("select id, hash from searchindex where peerID=?", peerId)
+		if err != nil {
+			return err
+		}
+		keepHashes := make(map[string]bool)
+		for _, hash := range keep {
+			keepHashes[hash] = true
+		}
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			var hash string
+			if err := rows.Scan(&id, &hash); err != nil {
+				rows.Close()
+				return err
+			}
+			if !keepHashes[hash] {
+				ids = append(ids, id)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := deleteSearchListing(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SearchIndexDB) Peers() ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	rows, err := s.db.Query("select distinct peerID from searchindex")
+	if err != nil {
+		return nil, wrapError("get search peers", err)
+	}
+	defer rows.Close()
+	var ret []string
+	for rows.Next() {
+		var peerId string
+		if err := rows.Scan(&peerId); err != nil {
+			return ret, wrapError("get search peers", err)
+		}
+		ret = append(ret, peerId)
+	}
+	return ret, wrapError("get search peers", rows.Err())
+}
+
+func (s *SearchIndexDB) Search(query repo.SearchQuery, offset int, limit int) ([]repo.SearchListing, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stm := "select s.hash, s.peerID, s.listingName, s.title, s.description, s.tags, s.category, s.price, s.currencyCode, s.fiatPrice, s.timestamp, (select group_concat(region) from searchregions r where r.listingID = s.id) from searchindex s"
+	var where []string
+	var args []interface{}
+	if match := matchExpression(query.Text); match != "" {
+		stm += " join searchtext t on t.docid = s.id"
+		where = append(where, "searchtext match ?")
+		args = append(args, match)
+	}
+	if query.ShipsTo != 0 {
+		where = append(where, "exists (select 1 from searchregions r where r.listingID = s.id and r.region in (?,?))")
+		args = append(args, query.ShipsTo, regionAll)
+	}
+	if query.Category != 0 {
+		where = append(where, "s.category=?")
+		args = append(args, query.Category)
+	}
+	price := "s.price"
+	if query.CurrencyCode != "" {
+		price = "s.fiatPrice"
+		where = append(where, "s.currencyCode=?")
+		args = append(args, strings.ToUpper(query.CurrencyCode))
+	}
+	if query.MinPrice > 0 {
+		where = append(where, price+">=?")
+		args = append(args, query.MinPrice)
+	}
+	if query.MaxPrice > 0 {
+		where = append(where, price+"<=?")
+		args = append(args, query.MaxPrice)
+	}
+	if len(where) > 0 {
+		stm += " where " + strings.Join(where, " and ")
+	}
+	stm += " order by s.timestamp desc, s.id desc limit " + strconv.Itoa(limit) + " offset " + strconv.Itoa(offset)
+	rows, err := s.db.Query(stm, args...)
+	if err != nil {
+		return nil, wrapError("search listings", err)
+	}
+	defer rows.Close()
+	var ret []repo.SearchListing
+	for rows.Next() {
+		var l repo.SearchListing
+		var tags string
+		var price int64
+		var timestamp int
+		var regions sql.NullString
+		if err := rows.Scan(&l.Hash, &l.PeerId, &l.ListingName, &l.Title, &l.Description, &tags, &l.Category, &price, &l.CurrencyCode, &l.FiatPrice, &timestamp, &regions); err != nil {
+			return ret, wrapError("search listings", err)
+		}
+		json.Unmarshal([]byte(tags), &l.Tags)
+		l.Price = uint64(price)
+		l.Timestamp = time.Unix(int64(timestamp), 0)
+		for _, r := range strings.Split(regions.String, ",") {
+			if region, err := strconv.Atoi(r); err == nil {
+				l.ShipsTo = append(l.ShipsTo, region)
+			}
+		}
+		ret = append(ret, l)
+	}
+	return ret, wrapError("search listings", rows.Err())
+}
+
+func deleteSearchListing(tx *sql.Tx, id int64) error {
+	if _, err := tx.Exec("delete from searchtext where docid=?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("delete from searchregions where listingID=?", id); err != nil {
+		return err
+	}
+	_, err := tx.Exec("delete from searchindex where id=?", id)
+	return err
+}
+
+// Build a full-text match expression from user input. Each word must match as a
+// prefix and anything other than letters and digits is ignored so the input can't
+// form query syntax.
+func matchExpression(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + "*"
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var searchdb SearchIndexDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	migrate(conn, "", latestSchemaVersion())
+	searchdb = SearchIndexDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func putTestListings(t *testing.T) {
+	listings := []repo.SearchListing{
+		{
+			Hash:        "QmShoes",
+			PeerId:      "QmPeer1",
+			ListingName: "shoes",
+			Title:       "Red leather shoes",
+			Description: "Handmade in Italy",
+			Tags:        []string{"fashion", "footwear"},
+			Category:    1,
+			Price:       50000,
+			ShipsTo:     []int{1},
+			Timestamp:   time.Unix(1000, 0),
+		},
+		{
+			Hash:         "QmEbook",
+			PeerId:       "QmPeer1",
+			ListingName:  "ebook",
+			Title:        "Bitcoin for beginners",
+			Description:  "An introduction to bitcoin",
+			Tags:         []string{"books"},
+			Category:     2,
+			CurrencyCode: "USD",
+			FiatPrice:    9.99,
+			Timestamp:    time.Unix(2000, 0),
+		},
+		{
+			Hash:        "QmBoots",
+			PeerId:      "QmPeer2",
+			ListingName: "boots",
+			Title:       "Hiking boots",
+			Description: "Waterproof leather",
+			Tags:        []string{"footwear", "outdoors"},
+			Category:    1,
+			Price:       150000,
+			ShipsTo:     []int{229, 230},
+			Timestamp:   time.Unix(3000, 0),
+		},
+	}
+	for _, l := range listings {
+		if err := searchdb.Put(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func clearSearchIndex() {
+	searchdb.db.Exec("delete from searchindex; delete from searchtext; delete from searchregions;")
+}
+
+func searchHashes(t *testing.T, query repo.SearchQuery) []string {
+	results, err := searchdb.Search(query, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hashes []string
+	for _, r := range results {
+		hashes = append(hashes, r.Hash)
+	}
+	return hashes
+}
+
+func equalHashes(a []string, b ...string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchText(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	if h := searchHashes(t, repo.SearchQuery{Text: "leather"}); !equalHashes(h, "QmBoots", "QmShoes") {
+		t.Errorf("Searching description returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "foot"}); !equalHashes(h, "QmBoots", "QmShoes") {
+		t.Errorf("Searching tag prefix returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "RED leather"}); !equalHashes(h, "QmShoes") {
+		t.Errorf("Searching multiple words returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: `shoes" OR "boots`}); len(h) != 0 {
+		t.Errorf("Query syntax was not escaped, returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{}); !equalHashes(h, "QmBoots", "QmEbook", "QmShoes") {
+		t.Errorf("Empty query returned %v", h)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	// The shoes ship everywhere
+	if h := searchHashes(t, repo.SearchQuery{ShipsTo: 230}); !equalHashes(h, "QmBoots", "QmShoes") {
+		t.Errorf("Ships to filter returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{ShipsTo: 50}); !equalHashes(h, "QmShoes") {
+		t.Errorf("Ships to filter returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{Category: 2}); !equalHashes(h, "QmEbook") {
+		t.Errorf("Category filter returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{MinPrice: 10000, MaxPrice: 100000}); !equalHashes(h, "QmShoes") {
+		t.Errorf("Price filter returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{MaxPrice: 10, CurrencyCode: "usd"}); !equalHashes(h, "QmEbook") {
+		t.Errorf("Fiat price filter returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "leather", ShipsTo: 50}); !equalHashes(h, "QmShoes") {
+		t.Errorf("Combined filters returned %v", h)
+	}
+}
+
+func TestSearchListingFields(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	results, err := searchdb.Search(repo.SearchQuery{Text: "boots"}, 0, -1)
+	if err != nil || len(results) != 1 {
+		t.Fatal("Failed to find listing")
+	}
+	l := results[0]
+	if l.PeerId != "QmPeer2" || l.ListingName != "boots" || l.Price != 150000 || l.Category != 1 {
+		t.Error("Returned wrong listing")
+	}
+	if len(l.Tags) != 2 || l.Tags[1] != "outdoors" {
+		t.Error("Returned wrong tags")
+	}
+	if len(l.ShipsTo) != 2 || l.ShipsTo[0] != 229 || l.ShipsTo[1] != 230 {
+		t.Error("Returned wrong shipping regions")
+	}
+	if !l.Timestamp.Equal(time.Unix(3000, 0)) {
+		t.Error("Returned wrong timestamp")
+	}
+}
+
+func TestPutSearchListingReplaces(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	// A new version of the shoes listing
+	err := searchdb.Put(repo.SearchListing{
+		Hash:        "QmShoes2",
+		PeerId:      "QmPeer1",
+		ListingName: "shoes",
+		Title:       "Blue suede shoes",
+		Timestamp:   time.Unix(4000, 0),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if searchdb.Has("QmShoes") || !searchdb.Has("QmShoes2") {
+		t.Error("Failed to replace listing")
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "red"}); len(h) != 0 {
+		t.Error("Old version is still searchable")
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "suede"}); !equalHashes(h, "QmShoes2") {
+		t.Errorf("New version not searchable, returned %v", h)
+	}
+	if h := searchHashes(t, repo.SearchQuery{ShipsTo: 50}); len(h) != 0 {
+		t.Error("Old shipping regions were kept")
+	}
+}
+
+func TestPruneSearchIndex(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	if err := searchdb.Prune("QmPeer1", []string{"QmEbook"}); err != nil {
+		t.Error(err)
+	}
+	if searchdb.Has("QmShoes") {
+		t.Error("Failed to prune listing")
+	}
+	if !searchdb.Has("QmEbook") || !searchdb.Has("QmBoots") {
+		t.Error("Pruned the wrong listings")
+	}
+	if h := searchHashes(t, repo.SearchQuery{Text: "leather"}); !equalHashes(h, "QmBoots") {
+		t.Errorf("Pruned listing is still searchable, returned %v", h)
+	}
+}
+
+func TestSearchPeers(t *testing.T) {
+	putTestListings(t)
+	defer clearSearchIndex()
+	peers, err := searchdb.Peers()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(peers) != 2 {
+		t.Errorf("Expected 2 peers got %d", len(peers))
+	}
+}
@@ -16,41 +16,30 @@ type TransactionsDB struct {
 func (t *TransactionsDB) Put(txinfo bitcoin.TransactionInfo) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	tx, err := t.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("insert into transactions(txid, tx, height, state, timestamp, value, exchangeRate, exchangeCurrency) values(?,?,?,?,?,?,?,?)")
-	if err != nil {
+	return withTx(t.db, "put transaction", func(tx *sql.Tx) error {
+		_, err := tx.Exec("insert into transactions(txid, tx, height, state, timestamp, value, exchangeRate, exchangeCurrency) values(?,?,?,?,?,?,?,?)",
+			hex.EncodeToString(txinfo.Txid),
+			txinfo.Tx,
+			txinfo.Height,
+			int(txinfo.State),
+			int(txinfo.Timestamp.Unix()),
+			txinfo.Value,
+			txinfo.ExchangeRate,
+			txinfo.ExchangCurrency,
+		)
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(
-		hex.EncodeToString(txinfo.Txid),
-		txinfo.Tx,
-		txinfo.Height,
-		int(txinfo.State),
-		int(txinfo.Timestamp.Unix()),
-		txinfo.Value,
-		txinfo.ExchangeRate,
-		txinfo.ExchangCurrency,
-	)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (t *TransactionsDB) Has(txid []byte) bool {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	stmt, err := t.db.Prepare("select txid from transactions where txid=?")
-	defer stmt.Close()
 	var ret string
-	err = stmt.QueryRow(hex.EncodeToString(txid)).Scan(&ret)
+	err := t.db.QueryRow("select txid from transactions where txid=?", hex.EncodeToString(txid)).Scan(&ret)
 	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error(wrapError("has transaction", err))
+		}
 		return false
 	}
 	return true
@@ -63,9 +52,10 @@ func (t *TransactionsDB) GetAll() []bitcoin.TransactionInfo {
 	stm := `select * from transactions`
 	rows, err := t.db.Query(stm)
 	if err != nil {
-		log.Error(err)
+		log.Error(wrapError("get transactions", err))
 		return ret
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var txidhex string
 		var tx []byte
@@ -76,7 +66,7 @@ func (t *TransactionsDB) GetAll() []bitcoin.TransactionInfo {
 		var exchangeRate float64
 		var exchangeCurrency string
 		if err := rows.Scan(&txidhex, &tx, &height, &state, &timestamp, &value, &exchangeRate, &exchangeCurrency); err != nil {
-			log.Error(err)
+			log.Error(wrapError("get transactions", err))
 			return ret
 		}
 		txid, err := hex.DecodeString(txidhex)
@@ -106,9 +96,10 @@ func (t *TransactionsDB) GetUnconfirmed() []bitcoin.TransactionInfo {
 	stm := `select * from transactions where height=0`
 	rows, err := t.db.Query(stm)
 	if err != nil {
-		log.Error(err)
+		log.Error(wrapError("get transactions", err))
 		return ret
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var txidhex string
 		var tx []byte
@@ -119,7 +110,7 @@ func (t *TransactionsDB) GetUnconfirmed() []bitcoin.TransactionInfo {
 		var exchangeRate float64
 		var exchangeCurrency string
 		if err := rows.Scan(&txidhex, &tx, &height, &state, &timestamp, &value, &exchangeRate, &exchangeCurrency); err != nil {
-			log.Error(err)
+			log.Error(wrapError("get transactions", err))
 			return ret
 		}
 		txid, err := hex.DecodeString(txidhex)
@@ -145,12 +136,10 @@ func (t *TransactionsDB) GetUnconfirmed() []bitcoin.TransactionInfo {
 func (t *TransactionsDB) GetHeight(txid []byte) (int, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	stmt, err := t.db.Prepare("select height from transactions where txid=?")
-	defer stmt.Close()
 	var ret int
-	err = stmt.QueryRow(hex.EncodeToString(txid)).Scan(&ret)
+	err := t.db.QueryRow("select height from transactions where txid=?", hex.EncodeToString(txid)).Scan(&ret)
 	if err != nil {
-		return 0, err
+		return 0, wrapError("get transaction height", err)
 	}
 	return ret, nil
 }
@@ -158,42 +147,24 @@ func (t *TransactionsDB) GetHeight(txid []byte) (int, error) {
 func (t *TransactionsDB) UpdateState(txid []byte, state bitcoin.TransactionState) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	tx, err := t.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("update transactions set state=? where txid=?")
-	if err != nil {
+	return withTx(t.db, "update transaction state", func(tx *sql.Tx) error {
+		_, err := tx.Exec("update transactions set state=? where txid=?", int(state), hex.EncodeToString(txid))
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(int(state), hex.EncodeToString(txid))
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
 func (t *TransactionsDB) UpdateHeight(txid []byte, height int) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	tx, err := t.db.Begin()
-	if err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare("update transactions set height=? where txid=?")
-	if err != nil {
+	return withTx(t.db, "update transaction height", func(tx *sql.Tx) error {
+		_, err := tx.Exec("update transactions set height=? where txid=?", height, hex.EncodeToString(txid))
 		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(height, hex.EncodeToString(txid))
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	tx.Commit()
-	return nil
+	})
 }
 
+func (t *TransactionsDB) Delete(txid []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("delete from transactions where txid=?", hex.EncodeToString(txid))
+	return wrapError("delete transaction", err)
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+)
+
+var txdb TransactionsDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	txdb = TransactionsDB{
+		db:   conn,
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestDeleteTransaction(t *testing.T) {
+	txid := []byte{0x01, 0x02}
+	err := txdb.Put(bitcoin.TransactionInfo{
+		Txid:      txid,
+		Tx:        []byte{0x00},
+		Height:    100,
+		State:     bitcoin.CONFIRMED,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !txdb.Has(txid) {
+		t.Error("Failed to put transaction")
+	}
+	err = txdb.Delete(txid)
+	if err != nil {
+		t.Error(err)
+	}
+	if txdb.Has(txid) {
+		t.Error("Failed to delete transaction")
+	}
+}
@@ -0,0 +1,19 @@
+package repo
+
+import "errors"
+
+// Errors returned by the datastore. They are usually wrapped with the operation that
+// failed so check for them with errors.Is.
+var (
+	// The requested record does not exist
+	ErrNotFound = errors.New("Not found")
+
+	// The database could not be decrypted with the password it was opened with
+	ErrWrongPassword = errors.New("The database password is wrong")
+
+	// Another process or connection holds a lock on the database
+	ErrLocked = errors.New("The database is locked")
+
+	// There isn't enough stock of a listing to take an order
+	ErrInsufficientStock = errors.New("Insufficient stock")
+)
@@ -9,6 +9,7 @@ import (
 	"path"
 
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ipfs/go-ipfs/core"
 	"github.com/ipfs/go-ipfs/namesys"
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
@@ -24,7 +25,11 @@ Reinitializing would overwrite your keys.
 (use -f to force overwrite)
 `)
 
-func DoInit(out io.Writer, repoRoot string, nBitsForKeypair int, testnet bool, password string, dbInit func(string, []byte, string) error) error {
+// Initialize the repo. If mnemonic is empty a new one is generated, otherwise the node's
+// identity and wallet are restored from it and the BIP39 passphrase. A node created with
+// an earlier seed version must be restored with that version to keep its peer ID.
+func DoInit(out io.Writer, repoRoot string, nBitsForKeypair int, params *chaincfg.Params, password string, mnemonic string, passphrase string,
+	seedVersion int, dbInit func(string, []byte, string, string, int) error) error {
 	if err := maybeCreateOBDirectories(repoRoot); err != nil {
 		return err
 	}
@@ -37,19 +42,22 @@ func DoInit(out io.Writer, repoRoot string, nBitsForKeypair int, testnet bool, p
 		return err
 	}
 
-	conf, err := initConfig(out)
+	conf, err := initConfig(out, params)
 	if err != nil {
 		return err
 	}
 
-	mnemonic, err := createMnemonic()
-	if err != nil {
-		return err
+	if mnemonic == "" {
+		mnemonic, err = createMnemonic()
+		if err != nil {
+			return err
+		}
+	} else if !bip39.IsMnemonicValid(mnemonic) {
+		return ErrInvalidMnemonic
 	}
 
-	seed := bip39.NewSeed(mnemonic, "Secret Passphrase")
 	fmt.Printf("generating %d-bit RSA keypair...", nBitsForKeypair)
-	identityKey, err := ipfs.IdentityKeyFromSeed(seed, nBitsForKeypair)
+	identityKey, err := IdentityKey(mnemonic, passphrase, seedVersion, nBitsForKeypair)
 	if err != nil {
 		return err
 	}
@@ -66,15 +74,15 @@ func DoInit(out io.Writer, repoRoot string, nBitsForKeypair int, testnet bool, p
 	}
 	conf.Identity = identity
 
-	if err := addConfigExtensions(repoRoot, testnet); err != nil {
+	if err := addConfigExtensions(repoRoot, params); err != nil {
 		return err
 	}
 
-	if err := dbInit(mnemonic, identityKey, password); err != nil {
+	if err := dbInit(mnemonic, identityKey, password, passphrase, seedVersion); err != nil {
 		return err
 	}
 
-	return initializeIpnsKeyspace(repoRoot, identityKey)
+	return InitializeIpnsKeyspace(repoRoot, identityKey)
 }
 
 func maybeCreateOBDirectories(repoRoot string) error {
@@ -90,6 +98,9 @@ func maybeCreateOBDirectories(repoRoot string) error {
 	if err := os.MkdirAll(path.Join(repoRoot, "root", "channel"), os.ModePerm); err != nil {
 		return err
 	}
+	if err := os.MkdirAll(path.Join(repoRoot, "root", "ratings"), os.ModePerm); err != nil {
+		return err
+	}
 	if err := os.MkdirAll(path.Join(repoRoot, "outbox"), os.ModePerm); err != nil {
 		return err
 	}
@@ -124,7 +135,8 @@ func checkWriteable(dir string) error {
 	return err
 }
 
-func initializeIpnsKeyspace(repoRoot string, privKeyBytes []byte) error {
+// Publish an empty root directory to IPNS. The node reads its root hash from here at start.
+func InitializeIpnsKeyspace(repoRoot string, privKeyBytes []byte) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -157,7 +169,7 @@ func initializeIpnsKeyspace(repoRoot string, privKeyBytes []byte) error {
 	return namesys.InitializeKeyspace(ctx, nd.DAG, nd.Namesys, nd.Pinning, nd.PrivateKey)
 }
 
-func addConfigExtensions(repoRoot string, testnet bool) error {
+func addConfigExtensions(repoRoot string, params *chaincfg.Params) error {
 	r, err := fsrepo.Open(repoRoot)
 	if err != nil { // NB: repo is owned by the node
 		return err
@@ -167,32 +179,58 @@ func addConfigExtensions(repoRoot string, testnet bool) error {
 		PublicKey []byte
 	}
 	var ls []Server
-	if !testnet {
+	walletType := "libbitcoin"
+	bitcoindHost := "localhost:8332"
+	switch params.Name {
+	case chaincfg.MainNetParams.Name:
 		ls = []Server{
 			{Url: "tcp://libbitcoin1.openbazaar.org:9091", PublicKey: []byte{}},
 			{Url: "tcp://libbitcoin3.openbazaar.org:9091", PublicKey: []byte{}},
 		}
-	} else {
+	case chaincfg.RegressionNetParams.Name:
+		// There are no public libbitcoin servers for regtest so use a local bitcoind
+		ls = []Server{}
+		walletType = "bitcoind"
+		bitcoindHost = "localhost:18443"
+	default:
+		bitcoindHost = "localhost:18332"
 		ls = []Server{
 			{Url: "tcp://libbitcoin2.openbazaar.org:9091", PublicKey: []byte(zmq4.Z85decode("baihZB[vT(dcVCwkhYLAzah<t2gJ>{3@k?+>T&^3"))},
 			{Url: "tcp://libbitcoin4.openbazaar.org:9091", PublicKey: []byte(zmq4.Z85decode("<Z&{.=LJSPySefIKgCu99w.L%b^6VvuVp0+pbnOM"))},
 		}
 	}
+	type Bitcoind struct {
+		Host     string
+		User     string
+		Password string
+	}
 	type Wallet struct {
+		Type              string
 		LibbitcoinServers []Server
+		Bitcoind          Bitcoind
 		MaxFee            int
 		FeeAPI            string
 		HighFeeDefault    int
 		MediumFeeDefault  int
 		LowFeeDefault     int
+		CoinSelection     string
+		AddressType       string
+		GapLimit          int
+		WatchOnlyXpub     string
 	}
 	var w Wallet = Wallet{
+		Type: walletType,
 		LibbitcoinServers: ls,
+		Bitcoind: Bitcoind{Host: bitcoindHost},
 		MaxFee: 1500000,
 		FeeAPI: "https://bitcoinfees.21.co/api/v1/fees/recommended",
 		HighFeeDefault: 60,
 		MediumFeeDefault: 40,
 		LowFeeDefault: 20,
+		CoinSelection: "branchandbound",
+		AddressType: "p2pkh",
+		GapLimit: 20,
+		WatchOnlyXpub: "",
 	}
 	if err := extendConfigFile(r, "Wallet", w); err != nil {
 		return err
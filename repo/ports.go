@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"errors"
+	"net"
+	"strconv"
+)
+
+const (
+	DefaultSwarmPort   = 4001
+	DefaultGatewayPort = 8080
+)
+
+// The number of ports chosen by the OS we try before giving up
+const portAttempts = 10
+
+// Return port if it's free on the host, otherwise a free port chosen by the OS. If udp
+// is set the port must be free for both TCP and UDP as the swarm listens on both.
+func allocatePort(port int, udp bool) (int, error) {
+	if portFree(port, udp) {
+		return port, nil
+	}
+	for i := 0; i < portAttempts; i++ {
+		l, err := net.Listen("tcp", ":0")
+		if err != nil {
+			return 0, err
+		}
+		p := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+		if portFree(p, udp) {
+			return p, nil
+		}
+	}
+	return 0, errors.New("Failed to find a free port")
+}
+
+func portFree(port int, udp bool) bool {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	defer l.Close()
+	if udp {
+		c, err := net.ListenPacket("udp", ":"+strconv.Itoa(port))
+		if err != nil {
+			return false
+		}
+		c.Close()
+	}
+	return true
+}
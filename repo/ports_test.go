@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestAllocatePort(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	taken := l.Addr().(*net.TCPAddr).Port
+	port, err := allocatePort(taken, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port == taken || !portFree(port, true) {
+		t.Errorf("Expected a free port other than %d, got %d", taken, port)
+	}
+}
+
+func TestInitConfigPorts(t *testing.T) {
+	// Hold the default swarm port if nothing else is using it
+	if l, err := net.Listen("tcp", ":"+strconv.Itoa(DefaultSwarmPort)); err == nil {
+		defer l.Close()
+	}
+	conf, err := initConfig(ioutil.Discard, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range conf.Addresses.Swarm {
+		if strings.HasSuffix(addr, "/"+strconv.Itoa(DefaultSwarmPort)) || strings.Contains(addr, "/"+strconv.Itoa(DefaultSwarmPort)+"/") {
+			t.Errorf("Swarm address %s uses a port in use", addr)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Versions of the scheme used to derive the identity and wallet keys from the mnemonic.
+// The version is saved in the database at init so existing nodes keep their peer IDs
+// and wallets when a new scheme is introduced.
+const (
+	// The identity key is derived from the mnemonic with the fixed passphrase "Secret Passphrase"
+	// and the wallet with an empty passphrase. The user's passphrase is not supported.
+	SeedVersionLegacy = 0
+
+	// The identity and wallet keys are both derived from the BIP39 seed of the mnemonic
+	// and the user's passphrase. The identity uses an HMAC of the seed so it is
+	// independent of the wallet keys.
+	SeedVersion1 = 1
+
+	// As SeedVersion1 but the identity key is generated by ipfs.DeterministicIdentityKey
+	// rather than crypto/rsa. Earlier versions can only be restored with the Go release
+	// they were created with as crypto/rsa's use of the random source changes between
+	// releases.
+	SeedVersion2 = 2
+
+	CurrentSeedVersion = SeedVersion2
+)
+
+var ErrInvalidMnemonic = errors.New("Invalid mnemonic")
+
+const identitySeedKey = "OpenBazaar identity seed"
+
+// Return the seed the identity key is generated from
+func IdentitySeed(mnemonic, passphrase string, version int) ([]byte, error) {
+	switch version {
+	case SeedVersionLegacy:
+		if passphrase != "" {
+			return nil, errors.New("A passphrase is not supported by the legacy seed version")
+		}
+		return bip39.NewSeed(mnemonic, "Secret Passphrase"), nil
+	case SeedVersion1, SeedVersion2:
+		mac := hmac.New(sha512.New, []byte(identitySeedKey))
+		mac.Write(bip39.NewSeed(mnemonic, passphrase))
+		return mac.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("Unknown seed version %d", version)
+}
+
+// Generate the identity key of the mnemonic and passphrase with the given seed version
+func IdentityKey(mnemonic, passphrase string, version int, bits int) ([]byte, error) {
+	seed, err := IdentitySeed(mnemonic, passphrase, version)
+	if err != nil {
+		return nil, err
+	}
+	if version < SeedVersion2 {
+		log.Warningf("Generating the identity key with seed version %d, the peer ID may differ from the original node\n", version)
+		return ipfs.IdentityKeyFromSeed(seed, bits)
+	}
+	return ipfs.DeterministicIdentityKey(seed, bits)
+}
+
+// Return the seed the wallet's BIP32 master key is generated from
+func WalletSeed(mnemonic, passphrase string, version int) ([]byte, error) {
+	switch version {
+	case SeedVersionLegacy:
+		if passphrase != "" {
+			return nil, errors.New("A passphrase is not supported by the legacy seed version")
+		}
+		return bip39.NewSeed(mnemonic, ""), nil
+	case SeedVersion1, SeedVersion2:
+		return bip39.NewSeed(mnemonic, passphrase), nil
+	}
+	return nil, fmt.Errorf("Unknown seed version %d", version)
+}
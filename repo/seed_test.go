@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestLegacySeeds(t *testing.T) {
+	identity, err := IdentitySeed(testMnemonic, "", SeedVersionLegacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(identity, bip39.NewSeed(testMnemonic, "Secret Passphrase")) {
+		t.Error("Legacy identity seed changed")
+	}
+	wallet, err := WalletSeed(testMnemonic, "", SeedVersionLegacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wallet, bip39.NewSeed(testMnemonic, "")) {
+		t.Error("Legacy wallet seed changed")
+	}
+	if _, err := WalletSeed(testMnemonic, "TREZOR", SeedVersionLegacy); err == nil {
+		t.Error("Legacy version should not accept a passphrase")
+	}
+}
+
+func TestSeedVersion1(t *testing.T) {
+	wallet, _ := WalletSeed(testMnemonic, "TREZOR", SeedVersion1)
+	if !bytes.Equal(wallet, bip39.NewSeed(testMnemonic, "TREZOR")) {
+		t.Error("Wallet seed should be the BIP39 seed")
+	}
+	identity, _ := IdentitySeed(testMnemonic, "TREZOR", SeedVersion1)
+	if bytes.Equal(identity, wallet) {
+		t.Error("Identity seed should be independent of the wallet seed")
+	}
+	other, _ := IdentitySeed(testMnemonic, "", SeedVersion1)
+	if bytes.Equal(identity, other) {
+		t.Error("Identity seed should depend on the passphrase")
+	}
+	if _, err := IdentitySeed(testMnemonic, "", 99); err == nil {
+		t.Error("Expected unknown version to fail")
+	}
+}